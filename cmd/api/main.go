@@ -23,9 +23,14 @@ import (
 	"github.com/memclutter/go-microservices-template/internal/infrastructure/messaging/rabbitmq"
 	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/postgres"
 	userUseCase "github.com/memclutter/go-microservices-template/internal/usecase/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
 	"github.com/memclutter/go-microservices-template/pkg/config"
+	"github.com/memclutter/go-microservices-template/pkg/encryption"
 	"github.com/memclutter/go-microservices-template/pkg/logger"
 	"github.com/memclutter/go-microservices-template/pkg/metrics"
+	"github.com/memclutter/go-microservices-template/pkg/pagination"
+	"github.com/memclutter/go-microservices-template/pkg/password"
+	"github.com/memclutter/go-microservices-template/pkg/webauthn"
 )
 
 func main() {
@@ -69,17 +74,166 @@ func main() {
 
 	// Initialize repositories
 	userRepo := postgres.NewUserRepository(dbPool)
+	sessionRepo := postgres.NewSessionRepository(dbPool)
+	passwordResetRepo := postgres.NewPasswordResetRepository(dbPool)
+	emailVerificationRepo := postgres.NewEmailVerificationRepository(dbPool)
+	passwordHistoryRepo := postgres.NewPasswordHistoryRepository(dbPool)
+	loginFailureRepo := postgres.NewLoginFailureRepository(dbPool)
+	loginChallengeRepo := postgres.NewLoginChallengeRepository(dbPool)
+	magicLinkRepo := postgres.NewMagicLinkRepository(dbPool)
+	passkeyRepo := postgres.NewPasskeyRepository(dbPool)
+	passkeyChallengeRepo := postgres.NewPasskeyChallengeRepository(dbPool)
+	apiKeyRepo := postgres.NewAPIKeyRepository(dbPool)
 
 	// Initialize domain services
 	userDomainService := user.NewService(userRepo)
 
+	// Initialize page token codec
+	pageTokens, err := pagination.NewTokenCodec(cfg.Pagination.TokenSecret)
+	if err != nil {
+		log.WithError(err).Error("Failed to create page token codec")
+		os.Exit(1)
+	}
+
+	// Initialize access token manager
+	accessTokens, err := auth.NewTokenManager(cfg.Auth.SigningKey, cfg.Auth.Issuer, cfg.Auth.AccessTokenTTL)
+	if err != nil {
+		log.WithError(err).Error("Failed to create access token manager")
+		os.Exit(1)
+	}
+
+	// Initialize TOTP secret cipher and repository
+	totpCipher, err := encryption.NewCipher(cfg.Auth.TOTPEncryptionKey)
+	if err != nil {
+		log.WithError(err).Error("Failed to create TOTP secret cipher")
+		os.Exit(1)
+	}
+	twoFactorRepo := postgres.NewTwoFactorRepository(dbPool, totpCipher)
+
+	// Initialize WebAuthn relying party
+	relyingParty, err := webauthn.New(webauthn.Config{
+		RPID:    cfg.WebAuthn.RPID,
+		RPName:  cfg.WebAuthn.RPName,
+		Origins: cfg.WebAuthn.Origins,
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to create WebAuthn relying party")
+		os.Exit(1)
+	}
+
+	// Initialize password hasher
+	passwordHasher, err := password.New(password.Config{
+		Algorithm: cfg.Password.Algorithm,
+		Bcrypt:    password.BcryptParams{Cost: cfg.Password.BcryptCost},
+		Argon2id: password.Argon2idParams{
+			Memory:      cfg.Password.Argon2idMemory,
+			Iterations:  cfg.Password.Argon2idIterations,
+			Parallelism: cfg.Password.Argon2idParallelism,
+			SaltLength:  cfg.Password.Argon2idSaltLength,
+			KeyLength:   cfg.Password.Argon2idKeyLength,
+		},
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to create password hasher")
+		os.Exit(1)
+	}
+
+	// Initialize password policy
+	passwordPolicy := user.PasswordPolicy{
+		MinLength:        cfg.Password.MinLength,
+		MaxLength:        cfg.Password.MaxLength,
+		RequireUppercase: cfg.Password.RequireUppercase,
+		RequireLowercase: cfg.Password.RequireLowercase,
+		RequireDigit:     cfg.Password.RequireDigit,
+		RequireSymbol:    cfg.Password.RequireSymbol,
+		HistorySize:      cfg.Password.HistorySize,
+	}
+	if cfg.Password.Algorithm == password.AlgorithmBcrypt {
+		// Reject what bcrypt cannot hash instead of failing after validation
+		passwordPolicy.MaxBytes = password.BcryptMaxPasswordLength
+	}
+	if cfg.Password.BlocklistFile != "" {
+		blocklist, err := password.LoadBlocklist(cfg.Password.BlocklistFile)
+		if err != nil {
+			log.WithError(err).Error("Failed to load password blocklist")
+			os.Exit(1)
+		}
+		passwordPolicy.Blocklist = blocklist
+		log.WithField("passwords", blocklist.Len()).Info("Password blocklist loaded")
+	}
+
+	// Initialize failed login throttle
+	loginThrottle := userUseCase.NewLoginThrottle(
+		loginFailureRepo,
+		loginThrottlePolicy(cfg.Lockout.Email),
+		loginThrottlePolicy(cfg.Lockout.IP),
+		eventPublisher,
+		log,
+	)
+
 	// Initialize use cases
-	createUserUC := userUseCase.NewCreateUserUseCase(userRepo, userDomainService, eventPublisher, log)
+	createUserUC := userUseCase.NewCreateUserUseCase(userRepo, userDomainService, emailVerificationRepo, passwordHasher, passwordPolicy, eventPublisher, cfg.Auth.EmailVerificationTTL, log)
 	getUserUC := userUseCase.NewGetUserUseCase(userRepo, log)
+	updateUserUC := userUseCase.NewUpdateUserUseCase(userRepo, eventPublisher, log)
+	deleteUserUC := userUseCase.NewDeleteUserUseCase(userRepo, userDomainService, eventPublisher, log)
+	listUsersUC := userUseCase.NewListUsersUseCase(userRepo, pageTokens, log)
+	batchGetUsersUC := userUseCase.NewBatchGetUsersUseCase(userRepo, cfg.Pagination.BatchGetMaxIDs, log)
+	loginUC := userUseCase.NewLoginUseCase(userRepo, sessionRepo, loginChallengeRepo, accessTokens, passwordHasher, loginThrottle, cfg.Auth.RefreshTokenTTL, cfg.Auth.LoginChallengeTTL, cfg.Auth.RequireVerifiedEmail, log)
+	refreshUC := userUseCase.NewRefreshSessionUseCase(sessionRepo, accessTokens, cfg.Auth.RefreshTokenTTL, log)
+	listSessionsUC := userUseCase.NewListSessionsUseCase(sessionRepo, log)
+	revokeSessionUC := userUseCase.NewRevokeSessionUseCase(sessionRepo, log)
+	revokeAllSessionsUC := userUseCase.NewRevokeAllSessionsUseCase(sessionRepo, log)
+	updateUserRolesUC := userUseCase.NewUpdateUserRolesUseCase(userRepo, eventPublisher, log)
+	changePasswordUC := userUseCase.NewChangePasswordUseCase(userRepo, sessionRepo, passwordHistoryRepo, passwordHasher, passwordPolicy, eventPublisher, log)
+	requestPasswordResetUC := userUseCase.NewRequestPasswordResetUseCase(userRepo, passwordResetRepo, eventPublisher, cfg.Auth.PasswordResetTTL, log)
+	confirmPasswordResetUC := userUseCase.NewConfirmPasswordResetUseCase(userRepo, passwordResetRepo, sessionRepo, passwordHistoryRepo, passwordHasher, passwordPolicy, eventPublisher, log)
+	verifyEmailUC := userUseCase.NewVerifyEmailUseCase(userRepo, emailVerificationRepo, eventPublisher, log)
+	changeEmailUC := userUseCase.NewChangeEmailUseCase(userRepo, userDomainService, emailVerificationRepo, passwordHasher, eventPublisher, cfg.Auth.EmailVerificationTTL, log)
+	unlockUserUC := userUseCase.NewUnlockUserUseCase(userRepo, loginThrottle, log)
+	startTOTPEnrollmentUC := userUseCase.NewStartTOTPEnrollmentUseCase(userRepo, twoFactorRepo, cfg.Auth.TOTPIssuer, log)
+	confirmTOTPEnrollmentUC := userUseCase.NewConfirmTOTPEnrollmentUseCase(twoFactorRepo, eventPublisher, log)
+	disableTOTPUC := userUseCase.NewDisableTOTPUseCase(userRepo, twoFactorRepo, loginThrottle, eventPublisher, log)
+	verifyLoginChallengeUC := userUseCase.NewVerifyLoginChallengeUseCase(userRepo, loginChallengeRepo, twoFactorRepo, sessionRepo, accessTokens, loginThrottle, cfg.Auth.RefreshTokenTTL, log)
+	requestMagicLinkUC := userUseCase.NewRequestMagicLinkUseCase(userRepo, magicLinkRepo, eventPublisher, cfg.Auth.MagicLinkTTL, userUseCase.MagicLinkRateLimit{
+		MaxRequests: cfg.Auth.MagicLinkMaxRequests,
+		Window:      cfg.Auth.MagicLinkRateWindow,
+	}, log)
+	consumeMagicLinkUC := userUseCase.NewConsumeMagicLinkUseCase(userRepo, magicLinkRepo, sessionRepo, loginChallengeRepo, accessTokens, cfg.Auth.RefreshTokenTTL, cfg.Auth.LoginChallengeTTL, cfg.Auth.RequireVerifiedEmail, log)
+	beginPasskeyRegistrationUC := userUseCase.NewBeginPasskeyRegistrationUseCase(userRepo, passkeyRepo, passkeyChallengeRepo, relyingParty, cfg.WebAuthn.ChallengeTTL, log)
+	finishPasskeyRegistrationUC := userUseCase.NewFinishPasskeyRegistrationUseCase(passkeyRepo, passkeyChallengeRepo, relyingParty, eventPublisher, log)
+	beginPasskeyLoginUC := userUseCase.NewBeginPasskeyLoginUseCase(passkeyChallengeRepo, relyingParty, cfg.WebAuthn.ChallengeTTL, log)
+	finishPasskeyLoginUC := userUseCase.NewFinishPasskeyLoginUseCase(userRepo, passkeyRepo, passkeyChallengeRepo, sessionRepo, accessTokens, relyingParty, cfg.Auth.RefreshTokenTTL, cfg.Auth.RequireVerifiedEmail, log)
+	createAPIKeyUC := userUseCase.NewCreateAPIKeyUseCase(apiKeyRepo, grpcHandler.APIKeyScopes(), eventPublisher, log)
+	listAPIKeysUC := userUseCase.NewListAPIKeysUseCase(apiKeyRepo, log)
+	revokeAPIKeyUC := userUseCase.NewRevokeAPIKeyUseCase(apiKeyRepo, eventPublisher, log)
+	authenticateAPIKeyUC := userUseCase.NewAuthenticateAPIKeyUseCase(apiKeyRepo, log)
+	authenticateSessionUC := userUseCase.NewAuthenticateSessionUseCase(sessionRepo, log)
+	getSecuritySettingsUC := userUseCase.NewGetSecuritySettingsUseCase(twoFactorRepo, passkeyRepo, log)
+	authorizeUC := userUseCase.NewAuthorizeUseCase(userRepo, log)
 
 	// Initialize gRPC server
-	grpcServer := grpc.NewServer()
-	userGRPCService := grpcHandler.NewUserServiceServer(createUserUC, getUserUC, log, appMetrics)
+	trustedProxies, err := grpcHandler.ParseTrustedProxies(cfg.GRPC.TrustedProxies)
+	if err != nil {
+		log.WithError(err).Error("Failed to parse trusted proxies")
+		os.Exit(1)
+	}
+	authInterceptor := grpcHandler.NewAuthInterceptor(accessTokens, authenticateSessionUC, authenticateAPIKeyUC, log)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(authInterceptor.Unary()),
+		grpc.ChainStreamInterceptor(authInterceptor.Stream()),
+	)
+	userGRPCService := grpcHandler.NewUserServiceServer(
+		createUserUC, getUserUC, updateUserUC, deleteUserUC, listUsersUC, batchGetUsersUC,
+		loginUC, refreshUC, listSessionsUC, revokeSessionUC, revokeAllSessionsUC,
+		updateUserRolesUC, changePasswordUC, requestPasswordResetUC, confirmPasswordResetUC,
+		verifyEmailUC, changeEmailUC, unlockUserUC,
+		startTOTPEnrollmentUC, confirmTOTPEnrollmentUC, disableTOTPUC, verifyLoginChallengeUC,
+		requestMagicLinkUC, consumeMagicLinkUC,
+		beginPasskeyRegistrationUC, finishPasskeyRegistrationUC, beginPasskeyLoginUC, finishPasskeyLoginUC,
+		createAPIKeyUC, listAPIKeysUC, revokeAPIKeyUC, getSecuritySettingsUC,
+		authorizeUC, trustedProxies,
+		log, appMetrics,
+	)
 	user2.RegisterUserServiceServer(grpcServer, userGRPCService)
 
 	// Enable gRPC reflection for tools like grpcurl
@@ -104,7 +258,11 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	gwmux := runtime.NewServeMux()
+	gwmux := runtime.NewServeMux(
+		runtime.WithErrorHandler(grpcHandler.GatewayErrorHandler),
+		runtime.WithIncomingHeaderMatcher(grpcHandler.GatewayHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(grpcHandler.GatewayOutgoingHeaderMatcher),
+	)
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
 
 	// Register gRPC-gateway
@@ -118,7 +276,7 @@ func main() {
 	mux := http.NewServeMux()
 
 	// Register gateway routes
-	mux.Handle("/v1/", gwmux)
+	mux.Handle("/v1/", grpcHandler.GatewayConditionalGET(gwmux))
 
 	// Health check endpoint
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -179,3 +337,15 @@ func main() {
 
 	log.Info("Servers stopped gracefully")
 }
+
+// loginThrottlePolicy converts a lockout config section to its domain policy
+func loginThrottlePolicy(cfg config.LoginThrottleConfig) user.LoginThrottlePolicy {
+	return user.LoginThrottlePolicy{
+		DelayAfter:   cfg.DelayAfter,
+		BaseDelay:    cfg.BaseDelay,
+		MaxDelay:     cfg.MaxDelay,
+		LockAfter:    cfg.LockAfter,
+		LockDuration: cfg.LockDuration,
+		ResetAfter:   cfg.ResetAfter,
+	}
+}
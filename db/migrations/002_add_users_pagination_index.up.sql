@@ -0,0 +1,2 @@
+-- Support keyset pagination ordered by (created_at, id)
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users(created_at DESC, id DESC);
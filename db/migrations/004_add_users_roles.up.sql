@@ -0,0 +1,7 @@
+-- Add roles to users, every account holds the base 'user' role
+ALTER TABLE users
+    ADD COLUMN roles TEXT[] NOT NULL DEFAULT '{user}'
+    CONSTRAINT users_roles_check CHECK (roles <@ ARRAY['user', 'admin', 'support']::TEXT[]);
+
+-- Keep the account that used to be protected by a hard-coded check an administrator
+UPDATE users SET roles = '{user,admin}' WHERE email = 'admin@example.com';
@@ -0,0 +1,12 @@
+-- Create password reset tokens table.
+-- Only token hashes are stored; tokens are removed together with their user.
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP
+);
+
+-- Create index for invalidating a user's outstanding tokens
+CREATE INDEX idx_password_reset_tokens_user_id_unused ON password_reset_tokens(user_id) WHERE used_at IS NULL;
@@ -0,0 +1,18 @@
+-- Track whether users have proved they own their email address.
+-- Accounts created before verification existed are treated as verified.
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+UPDATE users SET email_verified_at = created_at;
+
+-- Create email verification tokens table.
+-- Only token hashes are stored; tokens are removed together with their user.
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP
+);
+
+-- Create index for invalidating a user's outstanding tokens
+CREATE INDEX idx_email_verification_tokens_user_id_unused ON email_verification_tokens(user_id) WHERE used_at IS NULL;
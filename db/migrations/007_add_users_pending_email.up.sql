@@ -0,0 +1,3 @@
+-- Hold a requested email change until the new address is verified.
+-- A pending address is reserved and cannot be claimed by another change request.
+ALTER TABLE users ADD COLUMN pending_email VARCHAR(255) UNIQUE;
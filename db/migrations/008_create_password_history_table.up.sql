@@ -0,0 +1,11 @@
+-- Create password history table.
+-- Holds hashes of replaced passwords so that recent ones cannot be reused.
+CREATE TABLE IF NOT EXISTS password_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index for listing a user's newest hashes
+CREATE INDEX idx_password_history_user_id_created_at ON password_history(user_id, created_at DESC, id DESC);
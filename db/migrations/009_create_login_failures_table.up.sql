@@ -0,0 +1,10 @@
+-- Create login failures table.
+-- Counts recent failed logins per email and per IP address, shared by every replica.
+CREATE TABLE IF NOT EXISTS login_failures (
+    subject VARCHAR(16) NOT NULL,
+    subject_key VARCHAR(255) NOT NULL,
+    failures INTEGER NOT NULL,
+    last_failed_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP,
+    PRIMARY KEY (subject, subject_key)
+);
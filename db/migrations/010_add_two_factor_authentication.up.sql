@@ -0,0 +1,26 @@
+-- Store the TOTP second factor of users.
+-- The secret is encrypted by the application; totp_enabled_at stays NULL until
+-- enrollment is confirmed, and totp_last_used_step prevents code replay.
+ALTER TABLE users ADD COLUMN totp_secret TEXT;
+ALTER TABLE users ADD COLUMN totp_enabled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN totp_last_used_step BIGINT NOT NULL DEFAULT 0;
+
+-- Create recovery codes table.
+-- Only code hashes are stored; codes are removed together with their user.
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    used_at TIMESTAMP,
+    PRIMARY KEY (user_id, code_hash)
+);
+
+-- Create login challenges table.
+-- Only token hashes are stored; challenges are removed together with their user.
+CREATE TABLE IF NOT EXISTS login_challenges (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP
+);
@@ -0,0 +1,12 @@
+-- Create magic link tokens table.
+-- Only token hashes are stored; tokens are removed together with their user.
+CREATE TABLE IF NOT EXISTS magic_link_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP
+);
+
+-- Create index for counting a user's recent requests
+CREATE INDEX idx_magic_link_tokens_user_id_created_at ON magic_link_tokens(user_id, created_at);
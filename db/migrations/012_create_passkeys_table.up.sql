@@ -0,0 +1,27 @@
+-- Create passkeys table.
+-- Holds the public keys of WebAuthn credentials; passkeys are removed together with their user.
+CREATE TABLE IF NOT EXISTS passkeys (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    credential_id BYTEA NOT NULL UNIQUE,
+    public_key BYTEA NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    transports TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP
+);
+
+-- Create index for listing a user's passkeys
+CREATE INDEX idx_passkeys_user_id ON passkeys(user_id);
+
+-- Create passkey challenges table.
+-- Only challenge hashes are stored; user_id is NULL for login ceremonies.
+CREATE TABLE IF NOT EXISTS passkey_challenges (
+    challenge_hash VARCHAR(64) PRIMARY KEY,
+    user_id VARCHAR(36) REFERENCES users(id) ON DELETE CASCADE,
+    ceremony VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP
+);
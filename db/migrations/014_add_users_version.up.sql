@@ -0,0 +1,3 @@
+-- Count writes to a user, so that updates based on a stale read can be rejected.
+-- The version is exposed to clients as the ETag of the user.
+ALTER TABLE users ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,10 @@
+-- Move updated_at only with version, i.e. when a field of the user resource
+-- changes. Bookkeeping such as the last used TOTP step must not change the
+-- Last-Modified of the user.
+DROP TRIGGER IF EXISTS update_users_updated_at ON users;
+
+CREATE TRIGGER update_users_updated_at
+    BEFORE UPDATE ON users
+    FOR EACH ROW
+    WHEN (OLD.version IS DISTINCT FROM NEW.version)
+    EXECUTE FUNCTION update_updated_at_column();
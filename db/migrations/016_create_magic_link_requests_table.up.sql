@@ -0,0 +1,8 @@
+-- Create magic link requests table.
+-- Counts the magic links each user requested in the current rate limit window.
+-- Counting with an upsert keeps the limit exact when replicas race.
+CREATE TABLE IF NOT EXISTS magic_link_requests (
+    user_id VARCHAR(36) PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    requests INTEGER NOT NULL,
+    window_started_at TIMESTAMP NOT NULL
+);
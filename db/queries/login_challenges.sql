@@ -0,0 +1,13 @@
+-- name: CreateLoginChallenge :one
+INSERT INTO login_challenges (token_hash, user_id, created_at, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetLoginChallenge :one
+SELECT * FROM login_challenges
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: UseLoginChallenge :execrows
+UPDATE login_challenges
+SET used_at = $2
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2;
@@ -0,0 +1,23 @@
+-- name: GetLoginFailures :one
+SELECT * FROM login_failures
+WHERE subject = $1 AND subject_key = $2 LIMIT 1;
+
+-- name: RecordLoginFailure :one
+INSERT INTO login_failures (subject, subject_key, failures, last_failed_at)
+VALUES (@subject, @subject_key, 1, @failed_at)
+ON CONFLICT (subject, subject_key) DO UPDATE
+SET failures = CASE
+        WHEN login_failures.last_failed_at >= @window_start::timestamp THEN login_failures.failures + 1
+        ELSE 1
+    END,
+    last_failed_at = EXCLUDED.last_failed_at
+RETURNING *;
+
+-- name: LockLoginFailures :execrows
+UPDATE login_failures
+SET locked_until = $3
+WHERE subject = $1 AND subject_key = $2;
+
+-- name: DeleteLoginFailures :exec
+DELETE FROM login_failures
+WHERE subject = $1 AND subject_key = $2;
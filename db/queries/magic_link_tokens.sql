@@ -0,0 +1,32 @@
+-- name: CreateMagicLinkToken :one
+INSERT INTO magic_link_tokens (token_hash, user_id, created_at, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetMagicLinkToken :one
+SELECT * FROM magic_link_tokens
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: UseMagicLinkToken :execrows
+UPDATE magic_link_tokens
+SET used_at = $2
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2;
+
+-- name: InvalidateMagicLinkTokens :execrows
+UPDATE magic_link_tokens
+SET used_at = $2
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: RecordMagicLinkRequest :one
+INSERT INTO magic_link_requests (user_id, requests, window_started_at)
+VALUES (@user_id, 1, @requested_at)
+ON CONFLICT (user_id) DO UPDATE
+SET requests = CASE
+        WHEN magic_link_requests.window_started_at >= @window_start::timestamp THEN magic_link_requests.requests + 1
+        ELSE 1
+    END,
+    window_started_at = CASE
+        WHEN magic_link_requests.window_started_at >= @window_start::timestamp THEN magic_link_requests.window_started_at
+        ELSE EXCLUDED.window_started_at
+    END
+RETURNING requests;
@@ -0,0 +1,13 @@
+-- name: CreatePasskeyChallenge :one
+INSERT INTO passkey_challenges (challenge_hash, user_id, ceremony, created_at, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetPasskeyChallenge :one
+SELECT * FROM passkey_challenges
+WHERE challenge_hash = $1 LIMIT 1;
+
+-- name: UsePasskeyChallenge :execrows
+UPDATE passkey_challenges
+SET used_at = $2
+WHERE challenge_hash = $1 AND used_at IS NULL AND expires_at > $2;
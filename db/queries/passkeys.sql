@@ -0,0 +1,18 @@
+-- name: CreatePasskey :one
+INSERT INTO passkeys (id, user_id, name, credential_id, public_key, sign_count, transports, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetPasskeyByCredentialID :one
+SELECT * FROM passkeys
+WHERE credential_id = $1 LIMIT 1;
+
+-- name: ListUserPasskeys :many
+SELECT * FROM passkeys
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: UsePasskey :execrows
+UPDATE passkeys
+SET sign_count = $2, last_used_at = $3
+WHERE id = $1;
@@ -0,0 +1,18 @@
+-- name: CreatePasswordResetToken :one
+INSERT INTO password_reset_tokens (token_hash, user_id, created_at, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetPasswordResetToken :one
+SELECT * FROM password_reset_tokens
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: UsePasswordResetToken :execrows
+UPDATE password_reset_tokens
+SET used_at = $2
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2;
+
+-- name: InvalidatePasswordResetTokens :execrows
+UPDATE password_reset_tokens
+SET used_at = $2
+WHERE user_id = $1 AND used_at IS NULL;
@@ -0,0 +1,32 @@
+-- name: CreateSession :one
+INSERT INTO sessions (id, user_id, refresh_token_hash, user_agent, ip_address, created_at, last_used_at, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetSessionByID :one
+SELECT * FROM sessions
+WHERE id = $1 LIMIT 1;
+
+-- name: RotateSession :execrows
+UPDATE sessions
+SET refresh_token_hash = @refresh_token_hash,
+    user_agent = @user_agent,
+    ip_address = @ip_address,
+    last_used_at = @last_used_at,
+    expires_at = @expires_at
+WHERE id = @id AND refresh_token_hash = @previous_hash AND revoked_at IS NULL;
+
+-- name: ListActiveSessions :many
+SELECT * FROM sessions
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+ORDER BY last_used_at DESC, id DESC;
+
+-- name: RevokeSession :execrows
+UPDATE sessions
+SET revoked_at = $3
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessions :execrows
+UPDATE sessions
+SET revoked_at = $2
+WHERE user_id = $1 AND revoked_at IS NULL;
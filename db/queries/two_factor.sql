@@ -0,0 +1,40 @@
+-- name: GetUserTOTP :one
+SELECT totp_secret, totp_enabled_at, totp_last_used_step FROM users
+WHERE id = $1 LIMIT 1;
+
+-- name: SetUserTOTPSecret :execrows
+UPDATE users
+SET totp_secret = $2, totp_last_used_step = 0
+WHERE id = $1 AND totp_enabled_at IS NULL;
+
+-- name: EnableUserTOTP :execrows
+UPDATE users
+SET totp_enabled_at = $2, totp_last_used_step = $3
+WHERE id = $1 AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL;
+
+-- name: UseUserTOTPStep :execrows
+UPDATE users
+SET totp_last_used_step = $2
+WHERE id = $1 AND totp_enabled_at IS NOT NULL AND totp_last_used_step < $2;
+
+-- name: DisableUserTOTP :execrows
+UPDATE users
+SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_used_step = 0
+WHERE id = $1;
+
+-- name: ReplaceRecoveryCodes :exec
+WITH deleted AS (
+    DELETE FROM recovery_codes
+    WHERE recovery_codes.user_id = @user_id
+)
+INSERT INTO recovery_codes (user_id, code_hash, created_at)
+SELECT @user_id, unnest(@code_hashes::varchar[]), @created_at;
+
+-- name: UseRecoveryCode :execrows
+UPDATE recovery_codes
+SET used_at = $3
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE user_id = $1;
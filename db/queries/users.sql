@@ -1,27 +1,64 @@
 -- name: CreateUser :one
-INSERT INTO users (id, email, name, password, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO users (id, email, name, password, created_at, updated_at, roles)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
 
 -- name: GetUserByID :one
 SELECT * FROM users
 WHERE id = $1 LIMIT 1;
 
+-- name: GetUsersByIDs :many
+SELECT * FROM users
+WHERE id = ANY(@ids::varchar[]);
+
 -- name: GetUserByEmail :one
 SELECT * FROM users
 WHERE email = $1 LIMIT 1;
 
+-- name: EmailExists :one
+SELECT EXISTS(
+    SELECT 1 FROM users
+    WHERE email = $1 OR pending_email = $1
+);
+
 -- name: UpdateUser :one
 UPDATE users
-SET name = $2, updated_at = $3
+SET name = $2, updated_at = $3, version = version + 1
+WHERE id = $1 AND version = $4
+RETURNING *;
+
+-- name: UpdateUserRoles :one
+UPDATE users
+SET roles = $2, updated_at = $3, version = version + 1
 WHERE id = $1
 RETURNING *;
 
--- name: DeleteUser :exec
+-- name: UpdateUserEmail :one
+UPDATE users
+SET email = $2, email_verified_at = $3, pending_email = $4, updated_at = $5, version = version + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserPassword :one
+UPDATE users
+SET password = $2, updated_at = $3, version = version + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteUser :execrows
 DELETE FROM users
-WHERE id = $1;
+WHERE id = @id AND (@version::bigint = 0 OR version = @version);
 
 -- name: ListUsers :many
 SELECT * FROM users
-ORDER BY created_at DESC
+ORDER BY created_at DESC, id DESC
 LIMIT $1 OFFSET $2;
+
+-- name: ListUsersAfter :many
+SELECT * FROM users
+WHERE (created_at, id) < (@cursor_created_at::timestamp, @cursor_id::varchar)
+ORDER BY created_at DESC, id DESC
+LIMIT @page_size;
+
+-- name: CountUsers :one
+SELECT COUNT(*) FROM users;
@@ -0,0 +1,81 @@
+package user
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxAPIKeyNameLength is the longest API key name in characters
+const maxAPIKeyNameLength = 100
+
+// APIKey is a long-lived credential for service-to-service callers. Requests
+// made with it act as the owning user, usually a dedicated service account,
+// and are limited to the RPC methods in Scopes.
+type APIKey struct {
+	ID     string
+	UserID string
+	// Name lets the owner tell their keys apart, e.g. "billing-service"
+	Name string
+	// Prefix is the visible start of the key, shown so that keys can be
+	// recognized without storing them
+	Prefix string
+	// KeyHash is the hash of the key, which itself is shown only once
+	KeyHash string
+	// Scopes are full gRPC method names, e.g. "/user.UserService/GetUser"
+	Scopes    []string
+	CreatedAt time.Time
+	// ExpiresAt is nil for keys that do not expire
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+}
+
+// NewAPIKey creates an API key. Scopes must name at least one method and
+// only methods listed in knownScopes, as a key scoped to a misspelled method
+// would never authorize anything; duplicates are dropped.
+func NewAPIKey(id, userID, name, prefix, keyHash string, scopes, knownScopes []string, expiresAt *time.Time) (*APIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPIKeyNameLength {
+		return nil, ErrInvalidAPIKeyName
+	}
+
+	unique := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !slices.Contains(knownScopes, scope) {
+			return nil, ErrInvalidAPIKeyScope
+		}
+		if !slices.Contains(unique, scope) {
+			unique = append(unique, scope)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, ErrInvalidAPIKeyScope
+	}
+
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, ErrInvalidAPIKeyExpiry
+	}
+
+	return &APIKey{
+		ID:        id,
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Scopes:    unique,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// IsActive reports whether the key can authenticate requests at the given time
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// Allows reports whether the key is scoped to the RPC method
+func (k *APIKey) Allows(method string) bool {
+	return slices.Contains(k.Scopes, method)
+}
@@ -0,0 +1,69 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testScopes are the methods API keys may be scoped to in tests
+var testScopes = []string{"/user.UserService/GetUser", "/user.UserService/DeleteUser"}
+
+func TestNewAPIKey(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		keyName   string
+		scopes    []string
+		expiresAt *time.Time
+		wantErr   error
+	}{
+		{name: "valid", keyName: "billing-service", scopes: []string{"/user.UserService/GetUser"}},
+		{name: "with expiry", keyName: "billing-service", scopes: []string{"/user.UserService/GetUser"}, expiresAt: &future},
+		{name: "empty name", keyName: "  ", scopes: []string{"/user.UserService/GetUser"}, wantErr: ErrInvalidAPIKeyName},
+		{name: "no scopes", keyName: "billing-service", wantErr: ErrInvalidAPIKeyScope},
+		{name: "short method name", keyName: "billing-service", scopes: []string{"GetUser"}, wantErr: ErrInvalidAPIKeyScope},
+		{name: "unknown method", keyName: "billing-service", scopes: []string{"/user.UserService/GetUsr"}, wantErr: ErrInvalidAPIKeyScope},
+		{name: "expired", keyName: "billing-service", scopes: []string{"/user.UserService/GetUser"}, expiresAt: &past, wantErr: ErrInvalidAPIKeyExpiry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := NewAPIKey("key-1", "user-1", tt.keyName, "mst_abcd1234", "hash", tt.scopes, testScopes, tt.expiresAt)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, key.IsActive(time.Now()))
+		})
+	}
+}
+
+func TestAPIKey_Scopes(t *testing.T) {
+	key, err := NewAPIKey("key-1", "user-1", "billing-service", "mst_abcd1234", "hash", []string{
+		"/user.UserService/GetUser",
+		"/user.UserService/GetUser",
+	}, testScopes, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/user.UserService/GetUser"}, key.Scopes)
+	assert.True(t, key.Allows("/user.UserService/GetUser"))
+	assert.False(t, key.Allows("/user.UserService/DeleteUser"))
+}
+
+func TestAPIKey_IsActive(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+	key := &APIKey{ExpiresAt: &expiresAt}
+
+	assert.True(t, key.IsActive(now))
+	assert.False(t, key.IsActive(expiresAt))
+
+	key.RevokedAt = &now
+	assert.False(t, key.IsActive(now))
+}
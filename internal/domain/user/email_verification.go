@@ -0,0 +1,31 @@
+package user
+
+import "time"
+
+// EmailVerificationToken represents a pending proof that a user owns an email address.
+// Only the hash of the token sent to the address is kept.
+type EmailVerificationToken struct {
+	TokenHash string
+	UserID    string
+	Email     string // Address the token was sent to
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// NewEmailVerificationToken creates a verification token for the user's email that expires after ttl
+func NewEmailVerificationToken(tokenHash, userID, email string, ttl time.Duration) *EmailVerificationToken {
+	now := time.Now()
+	return &EmailVerificationToken{
+		TokenHash: tokenHash,
+		UserID:    userID,
+		Email:     email,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// IsUsable reports whether the token is neither used nor expired
+func (t *EmailVerificationToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -4,12 +4,48 @@ import "errors"
 
 var (
 	// Domain validation errors
-	ErrInvalidEmail = errors.New("invalid email address")
-	ErrInvalidName  = errors.New("invalid name")
-	ErrWeakPassword = errors.New("password must be at least 8 characters")
+	ErrInvalidEmail   = errors.New("invalid email address")
+	ErrInvalidName    = errors.New("invalid name")
+	ErrWeakPassword   = errors.New("password does not meet the password policy")
+	ErrInvalidRole    = errors.New("invalid role")
+	ErrEmailUnchanged = errors.New("new email equals the current email")
+
+	ErrInvalidUpdateMask = errors.New("update mask must list known fields")
+
+	ErrInvalidPasskeyName  = errors.New("invalid passkey name")
+	ErrInvalidAPIKeyName   = errors.New("invalid api key name")
+	ErrInvalidAPIKeyScope  = errors.New("api key scopes must be full names of known rpc methods")
+	ErrInvalidAPIKeyExpiry = errors.New("api key expiry must be in the future")
 
 	// Business logic errors
-	ErrUserNotFound      = errors.New("user not found")
-	ErrUserAlreadyExists = errors.New("user already exists")
-	ErrUnauthorized      = errors.New("unauthorized")
+	ErrUserNotFound             = errors.New("user not found")
+	ErrUserAlreadyExists        = errors.New("user already exists")
+	ErrUnauthorized             = errors.New("unauthorized")
+	ErrPermissionDenied         = errors.New("permission denied")
+	ErrIncorrectPassword        = errors.New("current password is incorrect")
+	ErrUserCannotBeDeleted      = errors.New("user cannot be deleted")
+	ErrSessionNotFound          = errors.New("session not found")
+	ErrSessionRevoked           = errors.New("session was revoked or has expired")
+	ErrInvalidResetToken        = errors.New("password reset token is invalid or expired")
+	ErrInvalidVerificationToken = errors.New("email verification token is invalid or expired")
+	ErrEmailNotVerified         = errors.New("email address is not verified")
+	ErrTooManyLoginAttempts     = errors.New("too many failed login attempts")
+	ErrTwoFactorNotEnrolled     = errors.New("two-factor authentication enrollment was not started")
+	ErrTwoFactorNotEnabled      = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyEnabled  = errors.New("two-factor authentication is already enabled")
+	ErrInvalidTwoFactorCode     = errors.New("two-factor authentication code is invalid")
+	ErrInvalidLoginChallenge    = errors.New("login challenge is invalid or expired")
+	ErrInvalidMagicLink         = errors.New("magic link is invalid or expired")
+	ErrPasskeyNotFound          = errors.New("passkey not found")
+	ErrPasskeyAlreadyRegistered = errors.New("passkey is already registered")
+	ErrInvalidPasskeyChallenge  = errors.New("passkey challenge is invalid or expired")
+	ErrInvalidPasskeyResponse   = errors.New("passkey response could not be verified")
+	ErrAPIKeyNotFound           = errors.New("api key not found")
+	ErrAPIKeyAlreadyExists      = errors.New("api key already exists")
+	ErrInvalidAPIKey            = errors.New("api key is invalid, expired or revoked")
+	ErrAPIKeyScopeNotHeld       = errors.New("api key cannot grant scopes it does not hold")
+	ErrVersionMismatch          = errors.New("user was changed since it was read")
+
+	// Availability errors
+	ErrStorageUnavailable = errors.New("user storage unavailable")
 )
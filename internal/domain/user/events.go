@@ -7,6 +7,27 @@ const (
 	EventTypeUserCreated = "user.created"
 	EventTypeUserUpdated = "user.updated"
 	EventTypeUserDeleted = "user.deleted"
+
+	EventTypeUserRolesChanged    = "user.roles_changed"
+	EventTypeUserPasswordChanged = "user.password_changed"
+
+	EventTypePasswordResetRequested = "user.password_reset_requested"
+	EventTypeVerificationRequested  = "user.verification_requested"
+	EventTypeUserEmailVerified      = "user.email_verified"
+	EventTypeEmailChangeRequested   = "user.email_change_requested"
+	EventTypeUserEmailChanged       = "user.email_changed"
+
+	EventTypeUserLocked = "user.locked"
+
+	EventTypeUserTwoFactorEnabled  = "user.two_factor_enabled"
+	EventTypeUserTwoFactorDisabled = "user.two_factor_disabled"
+
+	EventTypeMagicLinkRequested = "user.magic_link_requested"
+
+	EventTypePasskeyRegistered = "user.passkey_registered"
+
+	EventTypeAPIKeyCreated = "user.api_key_created"
+	EventTypeAPIKeyRevoked = "user.api_key_revoked"
 )
 
 // UserCreatedEvent is published when a new user is created
@@ -29,3 +50,119 @@ type UserDeletedEvent struct {
 	UserID    string    `json:"user_id"`
 	DeletedAt time.Time `json:"deleted_at"`
 }
+
+// UserRolesChangedEvent is published when roles are granted to or revoked from a user
+type UserRolesChangedEvent struct {
+	UserID    string    `json:"user_id"`
+	Roles     []string  `json:"roles"`
+	Granted   []string  `json:"granted"`
+	Revoked   []string  `json:"revoked"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserPasswordChangedEvent is published when a user's password is replaced
+type UserPasswordChangedEvent struct {
+	UserID    string    `json:"user_id"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// PasswordResetRequestedEvent is published when a user asks to reset a forgotten password.
+// It carries the plain reset token so that a mailer can deliver it.
+type PasswordResetRequestedEvent struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MagicLinkRequestedEvent is published when a user asks to log in without a password.
+// It carries the plain login token so that a mailer can deliver it.
+type MagicLinkRequestedEvent struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// VerificationRequestedEvent is published when a user has to prove they own an email address.
+// It carries the plain verification token so that a mailer can deliver it.
+type VerificationRequestedEvent struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UserEmailVerifiedEvent is published when a user verifies their email address
+type UserEmailVerifiedEvent struct {
+	UserID     string    `json:"user_id"`
+	Email      string    `json:"email"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// EmailChangeRequestedEvent is published to the current address when a user
+// asks to switch to NewEmail, so that the owner notices an unexpected change
+type EmailChangeRequestedEvent struct {
+	UserID      string    `json:"user_id"`
+	Email       string    `json:"email"`
+	NewEmail    string    `json:"new_email"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// UserEmailChangedEvent is published when a user verifies their new email address
+type UserEmailChangedEvent struct {
+	UserID    string    `json:"user_id"`
+	OldEmail  string    `json:"old_email"`
+	NewEmail  string    `json:"new_email"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// UserLockedEvent is published when repeated failed logins lock a user out
+type UserLockedEvent struct {
+	UserID      string    `json:"user_id"`
+	Email       string    `json:"email"`
+	Failures    int       `json:"failures"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// UserTwoFactorEnabledEvent is published when a user confirms TOTP enrollment
+type UserTwoFactorEnabledEvent struct {
+	UserID    string    `json:"user_id"`
+	EnabledAt time.Time `json:"enabled_at"`
+}
+
+// UserTwoFactorDisabledEvent is published when a user turns two-factor
+// authentication off, so that the owner notices an unexpected change
+type UserTwoFactorDisabledEvent struct {
+	UserID     string    `json:"user_id"`
+	DisabledAt time.Time `json:"disabled_at"`
+}
+
+// PasskeyRegisteredEvent is published when a user registers a passkey, so
+// that the owner notices an unexpected new credential
+type PasskeyRegisteredEvent struct {
+	UserID       string    `json:"user_id"`
+	PasskeyID    string    `json:"passkey_id"`
+	Name         string    `json:"name"`
+	RegisteredAt time.Time `json:"registered_at"`
+}
+
+// APIKeyCreatedEvent is published when an API key is created for a user
+type APIKeyCreatedEvent struct {
+	UserID    string     `json:"user_id"`
+	APIKeyID  string     `json:"api_key_id"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// APIKeyRevokedEvent is published when an API key is revoked
+type APIKeyRevokedEvent struct {
+	UserID    string    `json:"user_id"`
+	APIKeyID  string    `json:"api_key_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
@@ -0,0 +1,118 @@
+package user
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoginSubject is what failed logins are counted against
+type LoginSubject string
+
+const (
+	// LoginSubjectEmail counts failures per email address, registered or not,
+	// so that lockouts do not reveal which emails have accounts
+	LoginSubjectEmail LoginSubject = "email"
+	// LoginSubjectIP counts failures per client IP address
+	LoginSubjectIP LoginSubject = "ip"
+)
+
+// LoginFailures tracks the recent failed logins of an email or IP address
+type LoginFailures struct {
+	Subject      LoginSubject
+	Key          string // the email or IP address
+	Count        int
+	LastFailedAt time.Time
+	LockedUntil  *time.Time
+}
+
+// LoginThrottlePolicy holds the thresholds for slowing down and locking out
+// repeated failed logins. Zero values disable a rule.
+type LoginThrottlePolicy struct {
+	// DelayAfter is the number of failures after which every further attempt
+	// has to wait BaseDelay, doubled per failure and capped at MaxDelay
+	DelayAfter int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	// LockAfter is the number of failures that lock logins for LockDuration
+	LockAfter    int
+	LockDuration time.Duration
+	// ResetAfter forgets failures once no attempt failed for this long
+	ResetAfter time.Duration
+}
+
+// WindowStart returns the time before which failures are forgotten
+func (p LoginThrottlePolicy) WindowStart(now time.Time) time.Time {
+	if p.ResetAfter <= 0 {
+		return time.Time{}
+	}
+	return now.Add(-p.ResetAfter)
+}
+
+// delay returns how long to wait after the given number of failures
+func (p LoginThrottlePolicy) delay(failures int) time.Duration {
+	if p.DelayAfter <= 0 || failures < p.DelayAfter {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := p.DelayAfter; i < failures; i++ {
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// RetryAfter returns how long the next attempt has to wait, zero if it may
+// be made at now
+func (f *LoginFailures) RetryAfter(policy LoginThrottlePolicy, now time.Time) time.Duration {
+	if f.LockedUntil != nil && now.Before(*f.LockedUntil) {
+		return f.LockedUntil.Sub(now)
+	}
+	if f.Count == 0 || f.LastFailedAt.Before(policy.WindowStart(now)) {
+		return 0
+	}
+	if next := f.LastFailedAt.Add(policy.delay(f.Count)); now.Before(next) {
+		return next.Sub(now)
+	}
+	return 0
+}
+
+// Lock locks logins for the policy's lockout duration when the failures reach
+// its threshold. It reports whether the lock was applied.
+func (f *LoginFailures) Lock(policy LoginThrottlePolicy, now time.Time) bool {
+	if policy.LockAfter <= 0 || f.Count < policy.LockAfter {
+		return false
+	}
+	lockedUntil := now.Add(policy.LockDuration)
+	f.LockedUntil = &lockedUntil
+	return true
+}
+
+// LoginThrottledError is returned for a login attempted before the delay or
+// lockout earned by earlier failures has passed.
+// It matches ErrTooManyLoginAttempts with errors.Is.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("%s, retry after %ds", ErrTooManyLoginAttempts, e.RetryAfterSeconds())
+}
+
+// RetryAfterSeconds returns RetryAfter rounded up to whole seconds
+func (e *LoginThrottledError) RetryAfterSeconds() int64 {
+	seconds := int64(e.RetryAfter / time.Second)
+	if e.RetryAfter%time.Second != 0 {
+		seconds++
+	}
+	return seconds
+}
+
+// Is reports whether target is ErrTooManyLoginAttempts
+func (e *LoginThrottledError) Is(target error) bool {
+	return target == ErrTooManyLoginAttempts
+}
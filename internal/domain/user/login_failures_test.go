@@ -0,0 +1,71 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginFailures_RetryAfter(t *testing.T) {
+	policy := LoginThrottlePolicy{
+		DelayAfter:   3,
+		BaseDelay:    time.Second,
+		MaxDelay:     10 * time.Second,
+		LockAfter:    10,
+		LockDuration: 15 * time.Minute,
+		ResetAfter:   time.Hour,
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		failures LoginFailures
+		want     time.Duration
+	}{
+		{name: "no failures", failures: LoginFailures{}, want: 0},
+		{name: "below delay threshold", failures: LoginFailures{Count: 2, LastFailedAt: now}, want: 0},
+		{name: "first delay", failures: LoginFailures{Count: 3, LastFailedAt: now}, want: time.Second},
+		{name: "delay doubles", failures: LoginFailures{Count: 5, LastFailedAt: now}, want: 4 * time.Second},
+		{name: "delay is capped", failures: LoginFailures{Count: 9, LastFailedAt: now}, want: 10 * time.Second},
+		{name: "delay passed", failures: LoginFailures{Count: 5, LastFailedAt: now.Add(-5 * time.Second)}, want: 0},
+		{name: "failures forgotten", failures: LoginFailures{Count: 9, LastFailedAt: now.Add(-2 * time.Hour)}, want: 0},
+		{
+			name:     "locked",
+			failures: LoginFailures{Count: 10, LastFailedAt: now, LockedUntil: timePtr(now.Add(time.Minute))},
+			want:     time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.failures.RetryAfter(policy, now))
+		})
+	}
+}
+
+func TestLoginFailures_Lock(t *testing.T) {
+	policy := LoginThrottlePolicy{LockAfter: 3, LockDuration: time.Minute}
+	now := time.Now()
+
+	failures := &LoginFailures{Count: 2}
+	assert.False(t, failures.Lock(policy, now))
+	assert.Nil(t, failures.LockedUntil)
+
+	failures.Count = 3
+	assert.True(t, failures.Lock(policy, now))
+	assert.Equal(t, now.Add(time.Minute), *failures.LockedUntil)
+
+	assert.False(t, failures.Lock(LoginThrottlePolicy{}, now), "zero LockAfter disables lockout")
+}
+
+func TestLoginThrottledError(t *testing.T) {
+	err := error(&LoginThrottledError{RetryAfter: 1500 * time.Millisecond})
+	assert.True(t, errors.Is(err, ErrTooManyLoginAttempts))
+	assert.Equal(t, "too many failed login attempts, retry after 2s", err.Error())
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
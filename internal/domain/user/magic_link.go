@@ -0,0 +1,29 @@
+package user
+
+import "time"
+
+// MagicLinkToken represents an outstanding request to log in without a password.
+// Only the hash of the token sent to the user is kept.
+type MagicLinkToken struct {
+	TokenHash string
+	UserID    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// NewMagicLinkToken creates a login token for the user that expires after ttl
+func NewMagicLinkToken(tokenHash, userID string, ttl time.Duration) *MagicLinkToken {
+	now := time.Now()
+	return &MagicLinkToken{
+		TokenHash: tokenHash,
+		UserID:    userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// IsUsable reports whether the token is neither used nor expired
+func (t *MagicLinkToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,111 @@
+package user
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultPasskeyName names passkeys registered without a name
+	DefaultPasskeyName = "Passkey"
+	// maxPasskeyNameLength is the longest passkey name in characters
+	maxPasskeyNameLength = 100
+)
+
+// passkeyTransports are the authenticator transports defined by WebAuthn
+var passkeyTransports = []string{"ble", "hybrid", "internal", "nfc", "smart-card", "usb"}
+
+// Passkey is a WebAuthn credential a user can log in with instead of a password
+type Passkey struct {
+	ID     string
+	UserID string
+	// Name lets the user tell their passkeys apart, e.g. "MacBook"
+	Name         string
+	CredentialID []byte
+	// PublicKey is the COSE encoded public key of the credential
+	PublicKey []byte
+	// SignCount is the signature counter of the last assertion, so that
+	// cloned authenticators can be detected
+	SignCount uint32
+	// Transports hint how clients can reach the authenticator
+	Transports []string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+// NewPasskey creates a passkey for a newly registered credential.
+// Unknown transports are dropped, since clients may report values added to
+// WebAuthn later.
+func NewPasskey(id, userID, name string, credentialID, publicKey []byte, signCount uint32, transports []string) (*Passkey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = DefaultPasskeyName
+	}
+	if utf8.RuneCountInString(name) > maxPasskeyNameLength {
+		return nil, ErrInvalidPasskeyName
+	}
+
+	known := make([]string, 0, len(transports))
+	for _, transport := range transports {
+		if slices.Contains(passkeyTransports, transport) && !slices.Contains(known, transport) {
+			known = append(known, transport)
+		}
+	}
+
+	return &Passkey{
+		ID:           id,
+		UserID:       userID,
+		Name:         name,
+		CredentialID: credentialID,
+		PublicKey:    publicKey,
+		SignCount:    signCount,
+		Transports:   known,
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// Use records a successful assertion with the given signature counter
+func (p *Passkey) Use(signCount uint32, at time.Time) {
+	p.SignCount = signCount
+	p.LastUsedAt = &at
+}
+
+// PasskeyCeremony is the WebAuthn ceremony a challenge was issued for
+type PasskeyCeremony string
+
+const (
+	PasskeyRegistration PasskeyCeremony = "registration"
+	PasskeyLogin        PasskeyCeremony = "login"
+)
+
+// PasskeyChallenge is the random challenge of a WebAuthn ceremony, to be
+// signed by the authenticator. Only its hash is kept.
+type PasskeyChallenge struct {
+	ChallengeHash string
+	// UserID is the user registering a passkey, empty for logins
+	UserID    string
+	Ceremony  PasskeyCeremony
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// NewPasskeyChallenge creates a challenge for the ceremony that expires after ttl
+func NewPasskeyChallenge(challengeHash, userID string, ceremony PasskeyCeremony, ttl time.Duration) *PasskeyChallenge {
+	now := time.Now()
+	return &PasskeyChallenge{
+		ChallengeHash: challengeHash,
+		UserID:        userID,
+		Ceremony:      ceremony,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(ttl),
+	}
+}
+
+// IsUsableFor reports whether the challenge was issued for the ceremony and
+// is neither used nor expired
+func (c *PasskeyChallenge) IsUsableFor(ceremony PasskeyCeremony, now time.Time) bool {
+	return c.Ceremony == ceremony && c.UsedAt == nil && now.Before(c.ExpiresAt)
+}
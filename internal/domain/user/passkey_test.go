@@ -0,0 +1,35 @@
+package user
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPasskey(t *testing.T) {
+	t.Run("defaults name and drops unknown transports", func(t *testing.T) {
+		p, err := NewPasskey("passkey-1", "user-1", "  ", []byte{1}, []byte{2}, 0, []string{"internal", "carrier-pigeon", "internal", "hybrid"})
+		require.NoError(t, err)
+		assert.Equal(t, DefaultPasskeyName, p.Name)
+		assert.Equal(t, []string{"internal", "hybrid"}, p.Transports)
+	})
+
+	t.Run("rejects long name", func(t *testing.T) {
+		_, err := NewPasskey("passkey-1", "user-1", strings.Repeat("a", 101), []byte{1}, []byte{2}, 0, nil)
+		assert.ErrorIs(t, err, ErrInvalidPasskeyName)
+	})
+}
+
+func TestPasskeyChallenge_IsUsableFor(t *testing.T) {
+	c := NewPasskeyChallenge("hash", "", PasskeyLogin, time.Minute)
+	assert.True(t, c.IsUsableFor(PasskeyLogin, time.Now()))
+	assert.False(t, c.IsUsableFor(PasskeyRegistration, time.Now()))
+	assert.False(t, c.IsUsableFor(PasskeyLogin, time.Now().Add(2*time.Minute)))
+
+	usedAt := time.Now()
+	c.UsedAt = &usedAt
+	assert.False(t, c.IsUsableFor(PasskeyLogin, time.Now()))
+}
@@ -0,0 +1,14 @@
+package user
+
+// PasswordHasher hashes and verifies passwords.
+// This is a Port in Hexagonal Architecture terms. Hashes must record the
+// algorithm and parameters they were made with, so that hashes made with
+// earlier settings keep verifying.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify returns an error when password does not match hash
+	Verify(hash, password string) error
+	// NeedsRehash reports whether hash was made with an algorithm or
+	// parameters other than the ones currently configured
+	NeedsRehash(hash string) bool
+}
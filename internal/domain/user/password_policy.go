@@ -0,0 +1,169 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordRule identifies a password policy rule
+type PasswordRule string
+
+// Password policy rules
+const (
+	PasswordRuleMinLength PasswordRule = "MIN_LENGTH"
+	PasswordRuleMaxLength PasswordRule = "MAX_LENGTH"
+	PasswordRuleUppercase PasswordRule = "UPPERCASE"
+	PasswordRuleLowercase PasswordRule = "LOWERCASE"
+	PasswordRuleDigit     PasswordRule = "DIGIT"
+	PasswordRuleSymbol    PasswordRule = "SYMBOL"
+	PasswordRuleBlocklist PasswordRule = "BLOCKLIST"
+	PasswordRuleReuse     PasswordRule = "REUSE"
+)
+
+// PasswordViolation describes a rule that a password breaks
+type PasswordViolation struct {
+	Rule    PasswordRule
+	Message string
+}
+
+// PasswordPolicyError lists every rule a password breaks.
+// It matches ErrWeakPassword with errors.Is.
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return ErrWeakPassword.Error() + ": " + strings.Join(messages, "; ")
+}
+
+// Is reports whether target is ErrWeakPassword
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
+
+// PasswordBlocklist holds passwords that must not be used, such as passwords
+// known from breaches.
+// This is a Port in Hexagonal Architecture terms.
+type PasswordBlocklist interface {
+	Contains(password string) bool
+}
+
+// PasswordPolicy holds the rules new passwords must follow.
+// Zero values disable a rule.
+type PasswordPolicy struct {
+	// MinLength and MaxLength count characters, not bytes
+	MinLength int
+	MaxLength int
+	// MaxBytes caps the UTF-8 length for hashers that ignore anything
+	// beyond it, such as bcrypt
+	MaxBytes         int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	Blocklist        PasswordBlocklist
+	// HistorySize is how many of the user's most recent passwords,
+	// including the current one, cannot be reused
+	HistorySize int
+}
+
+// Validate checks password against every rule except reuse
+func (p PasswordPolicy) Validate(password string) error {
+	return p.toError(p.violations(password))
+}
+
+// ValidateChange checks password against every rule. recentHashes are the
+// user's most recent password hashes, newest first, current one included.
+func (p PasswordPolicy) ValidateChange(password string, recentHashes []string, hasher PasswordHasher) error {
+	violations := p.violations(password)
+	if p.isReused(password, recentHashes, hasher) {
+		violations = append(violations, PasswordViolation{
+			Rule:    PasswordRuleReuse,
+			Message: fmt.Sprintf("must differ from the last %d passwords", p.HistorySize),
+		})
+	}
+	return p.toError(violations)
+}
+
+// PreviousPasswordLimit is how many replaced password hashes the policy
+// needs besides the current one
+func (p PasswordPolicy) PreviousPasswordLimit() int {
+	if p.HistorySize <= 1 {
+		return 0
+	}
+	return p.HistorySize - 1
+}
+
+func (p PasswordPolicy) violations(password string) []PasswordViolation {
+	var violations []PasswordViolation
+	add := func(rule PasswordRule, message string) {
+		violations = append(violations, PasswordViolation{Rule: rule, Message: message})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		add(PasswordRuleMinLength, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		add(PasswordRuleMaxLength, fmt.Sprintf("must be at most %d characters", p.MaxLength))
+	} else if p.MaxBytes > 0 && len(password) > p.MaxBytes {
+		add(PasswordRuleMaxLength, fmt.Sprintf("must be at most %d bytes", p.MaxBytes))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsLetter(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUppercase && !hasUpper {
+		add(PasswordRuleUppercase, "must contain an uppercase letter")
+	}
+	if p.RequireLowercase && !hasLower {
+		add(PasswordRuleLowercase, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		add(PasswordRuleDigit, "must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		add(PasswordRuleSymbol, "must contain a symbol")
+	}
+
+	if p.Blocklist != nil && p.Blocklist.Contains(password) {
+		add(PasswordRuleBlocklist, "is too common")
+	}
+
+	return violations
+}
+
+func (p PasswordPolicy) isReused(password string, recentHashes []string, hasher PasswordHasher) bool {
+	if len(recentHashes) > p.HistorySize {
+		recentHashes = recentHashes[:p.HistorySize]
+	}
+	for _, hash := range recentHashes {
+		if hasher.Verify(hash, password) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (p PasswordPolicy) toError(violations []PasswordViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &PasswordPolicyError{Violations: violations}
+}
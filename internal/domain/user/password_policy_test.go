@@ -0,0 +1,80 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBlocklist map[string]bool
+
+func (b fakeBlocklist) Contains(password string) bool {
+	return b[password]
+}
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:        10,
+		MaxLength:        20,
+		MaxBytes:         24,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		Blocklist:        fakeBlocklist{"Password123!": true},
+	}
+
+	tests := []struct {
+		name      string
+		password  string
+		wantRules []PasswordRule
+	}{
+		{name: "meets every rule", password: "Correct-Horse-42"},
+		{name: "length counts characters", password: "Пароль-Ёж-42"},
+		{name: "too short", password: "Ab-1", wantRules: []PasswordRule{PasswordRuleMinLength}},
+		{name: "too long", password: "Abcdefghijklmnopqrs-1", wantRules: []PasswordRule{PasswordRuleMaxLength}},
+		{name: "too many bytes", password: "Пароль-Ёжики-42", wantRules: []PasswordRule{PasswordRuleMaxLength}},
+		{
+			name:      "missing character classes",
+			password:  "lowercaseonly",
+			wantRules: []PasswordRule{PasswordRuleUppercase, PasswordRuleDigit, PasswordRuleSymbol},
+		},
+		{name: "blocklisted", password: "Password123!", wantRules: []PasswordRule{PasswordRuleBlocklist}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password)
+			if tt.wantRules == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrWeakPassword)
+			var policyErr *PasswordPolicyError
+			require.True(t, errors.As(err, &policyErr))
+			var rules []PasswordRule
+			for _, v := range policyErr.Violations {
+				assert.NotEmpty(t, v.Message)
+				rules = append(rules, v.Rule)
+			}
+			assert.Equal(t, tt.wantRules, rules)
+		})
+	}
+}
+
+func TestPasswordPolicy_ValidateChange(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 8, HistorySize: 2}
+	recent := []string{"v1:current-password", "v1:previous-password", "v1:oldest-password"}
+
+	assert.ErrorIs(t, policy.ValidateChange("current-password", recent, testHasher), ErrWeakPassword)
+	assert.ErrorIs(t, policy.ValidateChange("previous-password", recent, testHasher), ErrWeakPassword)
+	assert.NoError(t, policy.ValidateChange("oldest-password", recent, testHasher), "only the last HistorySize passwords count")
+	assert.Equal(t, 1, policy.PreviousPasswordLimit())
+
+	policy.HistorySize = 0
+	assert.NoError(t, policy.ValidateChange("current-password", recent, testHasher))
+	assert.Equal(t, 0, policy.PreviousPasswordLimit())
+}
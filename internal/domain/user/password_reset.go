@@ -0,0 +1,29 @@
+package user
+
+import "time"
+
+// PasswordResetToken represents an outstanding request to reset a forgotten password.
+// Only the hash of the token sent to the user is kept.
+type PasswordResetToken struct {
+	TokenHash string
+	UserID    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// NewPasswordResetToken creates a reset token for the user that expires after ttl
+func NewPasswordResetToken(tokenHash, userID string, ttl time.Duration) *PasswordResetToken {
+	now := time.Now()
+	return &PasswordResetToken{
+		TokenHash: tokenHash,
+		UserID:    userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// IsUsable reports whether the token is neither used nor expired
+func (t *PasswordResetToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,67 @@
+package user
+
+// Action is an operation on a user account that is subject to authorization
+type Action string
+
+const (
+	ActionReadUser Action = "user.read"
+	// ActionReadPendingEmail covers seeing the pending email address of any
+	// account. Users see their own through the /me endpoints instead.
+	ActionReadPendingEmail Action = "user.read_pending_email"
+	ActionUpdateUser       Action = "user.update"
+	ActionDeleteUser       Action = "user.delete"
+	ActionListUsers        Action = "user.list"
+	ActionManageRoles      Action = "user.manage_roles"
+	ActionManageSessions   Action = "user.manage_sessions"
+	ActionChangePassword   Action = "user.change_password"
+	ActionChangeEmail      Action = "user.change_email"
+	ActionUnlockUser       Action = "user.unlock"
+	ActionManageTwoFactor  Action = "user.manage_two_factor"
+	ActionManagePasskeys   Action = "user.manage_passkeys"
+	ActionManageAPIKeys    Action = "user.manage_api_keys"
+)
+
+// selfActions may be performed by any user on their own account
+var selfActions = map[Action]bool{
+	ActionReadUser:        true,
+	ActionUpdateUser:      true,
+	ActionManageSessions:  true,
+	ActionChangePassword:  true,
+	ActionChangeEmail:     true,
+	ActionManageTwoFactor: true,
+	ActionManagePasskeys:  true,
+}
+
+// roleActions may be performed on any account by holders of the role
+var roleActions = map[Role]map[Action]bool{
+	RoleAdmin: {
+		ActionReadUser:         true,
+		ActionReadPendingEmail: true,
+		ActionUpdateUser:       true,
+		ActionDeleteUser:       true,
+		ActionListUsers:        true,
+		ActionManageRoles:      true,
+		ActionManageSessions:   true,
+		ActionUnlockUser:       true,
+		ActionManageAPIKeys:    true,
+	},
+	RoleSupport: {
+		ActionReadUser:       true,
+		ActionManageSessions: true,
+	},
+}
+
+// Authorize checks whether actor may perform action on the account targetID.
+// targetID is empty for actions that do not concern a single account.
+// It returns ErrPermissionDenied when no rule allows the action.
+func Authorize(actor *User, action Action, targetID string) error {
+	if targetID != "" && actor.ID == targetID && selfActions[action] {
+		return nil
+	}
+	for _, role := range actor.Roles {
+		if roleActions[role][action] {
+			return nil
+		}
+	}
+	return ErrPermissionDenied
+}
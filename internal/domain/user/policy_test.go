@@ -0,0 +1,82 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorize(t *testing.T) {
+	member := &User{ID: "user-1", Roles: []Role{RoleUser}}
+	support := &User{ID: "support-1", Roles: []Role{RoleUser, RoleSupport}}
+	admin := &User{ID: "admin-1", Roles: []Role{RoleUser, RoleAdmin}}
+
+	tests := []struct {
+		name    string
+		actor   *User
+		action  Action
+		target  string
+		allowed bool
+	}{
+		{name: "user reads self", actor: member, action: ActionReadUser, target: "user-1", allowed: true},
+		{name: "user updates self", actor: member, action: ActionUpdateUser, target: "user-1", allowed: true},
+		{name: "user reads other", actor: member, action: ActionReadUser, target: "user-2"},
+		{name: "user deletes self", actor: member, action: ActionDeleteUser, target: "user-1"},
+		{name: "user lists users", actor: member, action: ActionListUsers},
+		{name: "user grants roles to self", actor: member, action: ActionManageRoles, target: "user-1"},
+		{name: "support reads other", actor: support, action: ActionReadUser, target: "user-1", allowed: true},
+		{name: "support revokes sessions of other", actor: support, action: ActionManageSessions, target: "user-1", allowed: true},
+		{name: "support reads pending email", actor: support, action: ActionReadPendingEmail},
+		{name: "admin reads pending email", actor: admin, action: ActionReadPendingEmail, allowed: true},
+		{name: "support updates other", actor: support, action: ActionUpdateUser, target: "user-1"},
+		{name: "support lists users", actor: support, action: ActionListUsers},
+		{name: "admin deletes other", actor: admin, action: ActionDeleteUser, target: "user-1", allowed: true},
+		{name: "admin lists users", actor: admin, action: ActionListUsers, allowed: true},
+		{name: "admin grants roles", actor: admin, action: ActionManageRoles, target: "user-1", allowed: true},
+		{name: "user changes own password", actor: member, action: ActionChangePassword, target: "user-1", allowed: true},
+		{name: "admin changes password of other", actor: admin, action: ActionChangePassword, target: "user-1"},
+		{name: "user changes own email", actor: member, action: ActionChangeEmail, target: "user-1", allowed: true},
+		{name: "admin changes email of other", actor: admin, action: ActionChangeEmail, target: "user-1"},
+		{name: "admin unlocks other", actor: admin, action: ActionUnlockUser, target: "user-1", allowed: true},
+		{name: "support unlocks other", actor: support, action: ActionUnlockUser, target: "user-1"},
+		{name: "user unlocks self", actor: member, action: ActionUnlockUser, target: "user-1"},
+		{name: "user manages own two-factor", actor: member, action: ActionManageTwoFactor, target: "user-1", allowed: true},
+		{name: "admin manages two-factor of other", actor: admin, action: ActionManageTwoFactor, target: "user-1"},
+		{name: "user manages own passkeys", actor: member, action: ActionManagePasskeys, target: "user-1", allowed: true},
+		{name: "admin manages passkeys of other", actor: admin, action: ActionManagePasskeys, target: "user-1"},
+		{name: "admin manages api keys of other", actor: admin, action: ActionManageAPIKeys, target: "user-1", allowed: true},
+		{name: "user manages own api keys", actor: member, action: ActionManageAPIKeys, target: "user-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Authorize(tt.actor, tt.action, tt.target)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrPermissionDenied)
+			}
+		})
+	}
+}
+
+func TestUser_Roles(t *testing.T) {
+	u, err := NewUser("test@example.com", "Test", "password123", testPolicy, testHasher)
+	assert.NoError(t, err)
+	assert.Equal(t, []Role{RoleUser}, u.Roles)
+
+	assert.True(t, u.GrantRole(RoleAdmin))
+	assert.False(t, u.GrantRole(RoleAdmin))
+	assert.True(t, u.HasRole(RoleAdmin))
+
+	revoked, err := u.RevokeRole(RoleAdmin)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	assert.False(t, u.HasRole(RoleAdmin))
+
+	_, err = u.RevokeRole(RoleUser)
+	assert.ErrorIs(t, err, ErrInvalidRole)
+
+	_, err = ParseRole("root")
+	assert.ErrorIs(t, err, ErrInvalidRole)
+}
@@ -1,14 +1,182 @@
 package user
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Repository defines the interface for user data access
 // This is a Port in Hexagonal Architecture terms
 type Repository interface {
 	Create(ctx context.Context, user *User) error
 	GetByID(ctx context.Context, id string) (*User, error)
+	// GetByIDs returns the users with the given IDs in no particular order.
+	// IDs without a user are left out rather than reported as an error.
+	GetByIDs(ctx context.Context, ids []string) ([]*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	// Update saves the profile only if the stored version still equals
+	// user.Version, returning ErrVersionMismatch otherwise. Like the other
+	// writes to fields of the user resource it increments user.Version.
 	Update(ctx context.Context, user *User) error
-	Delete(ctx context.Context, id string) error
+	UpdateRoles(ctx context.Context, user *User) error
+	UpdatePassword(ctx context.Context, user *User) error
+	// UpdateEmail saves the email address, its verification state and the
+	// pending email address
+	UpdateEmail(ctx context.Context, user *User) error
+	// EmailExists reports whether any user holds email as their address or
+	// as their pending address
+	EmailExists(ctx context.Context, email string) (bool, error)
+	// Delete removes the user if version is zero or equals the stored
+	// version, returning ErrVersionMismatch otherwise
+	Delete(ctx context.Context, id string, version int64) error
 	List(ctx context.Context, limit, offset int32) ([]*User, error)
+	ListAfter(ctx context.Context, cursor PageCursor, limit int32) ([]*User, error)
+	Count(ctx context.Context) (int64, error)
+}
+
+// SessionRepository defines the interface for session data access
+type SessionRepository interface {
+	Create(ctx context.Context, session *Session) error
+	GetByID(ctx context.Context, id string) (*Session, error)
+	// Rotate saves the session only if its stored refresh token hash still
+	// equals previousHash, returning ErrSessionNotFound otherwise
+	Rotate(ctx context.Context, session *Session, previousHash string) error
+	ListActive(ctx context.Context, userID string, now time.Time) ([]*Session, error)
+	Revoke(ctx context.Context, userID, sessionID string, at time.Time) error
+	RevokeAll(ctx context.Context, userID string, at time.Time) (int64, error)
+}
+
+// PasswordResetRepository defines the interface for password reset token data access
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *PasswordResetToken) error
+	// GetByHash returns ErrInvalidResetToken when no token has the hash
+	GetByHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+	// Consume marks the token used if it is still usable at the given time,
+	// returning ErrInvalidResetToken otherwise, and invalidates every other
+	// outstanding token of the same user
+	Consume(ctx context.Context, token *PasswordResetToken, at time.Time) error
+}
+
+// EmailVerificationRepository defines the interface for email verification token data access
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, token *EmailVerificationToken) error
+	// GetByHash returns ErrInvalidVerificationToken when no token has the hash
+	GetByHash(ctx context.Context, tokenHash string) (*EmailVerificationToken, error)
+	// Consume marks the token used if it is still usable at the given time,
+	// returning ErrInvalidVerificationToken otherwise, and invalidates every
+	// other outstanding token of the same user
+	Consume(ctx context.Context, token *EmailVerificationToken, at time.Time) error
+}
+
+// PasswordHistoryRepository defines the interface for data access to the
+// password hashes users have replaced
+type PasswordHistoryRepository interface {
+	// Add records hash as replaced at the given time, keeping only the
+	// newest keep hashes of the user
+	Add(ctx context.Context, userID, hash string, at time.Time, keep int) error
+	// ListRecent returns up to limit hashes of the user, newest first
+	ListRecent(ctx context.Context, userID string, limit int) ([]string, error)
+}
+
+// LoginFailureRepository defines the interface for failed login data access.
+// It is shared by every replica, so counting must be atomic.
+type LoginFailureRepository interface {
+	// Get returns the failures of the subject key, with a zero Count if none
+	Get(ctx context.Context, subject LoginSubject, key string) (*LoginFailures, error)
+	// RecordFailure atomically counts a failure at the given time, restarting
+	// the count when the previous failure happened before windowStart
+	RecordFailure(ctx context.Context, subject LoginSubject, key string, at, windowStart time.Time) (*LoginFailures, error)
+	// Lock stores the LockedUntil of failures
+	Lock(ctx context.Context, failures *LoginFailures) error
+	// Reset forgets the failures and lock of the subject key
+	Reset(ctx context.Context, subject LoginSubject, key string) error
+}
+
+// TwoFactorRepository defines the interface for data access to the TOTP
+// secrets and recovery codes of users. Secrets are stored encrypted.
+type TwoFactorRepository interface {
+	// GetTOTP returns ErrTwoFactorNotEnrolled when the user has no TOTP secret
+	GetTOTP(ctx context.Context, userID string) (*TOTP, error)
+	// SaveTOTP stores the secret of a new enrollment, replacing an unconfirmed
+	// one, and returns ErrTwoFactorAlreadyEnabled when one is confirmed
+	SaveTOTP(ctx context.Context, totp *TOTP) error
+	// EnableTOTP confirms the enrollment and replaces the recovery codes of
+	// the user with recoveryCodeHashes
+	EnableTOTP(ctx context.Context, totp *TOTP, recoveryCodeHashes []string) error
+	// UseTOTPStep stores LastUsedStep only if it is later than the stored
+	// one, returning ErrInvalidTwoFactorCode otherwise
+	UseTOTPStep(ctx context.Context, totp *TOTP) error
+	// UseRecoveryCode marks an unused recovery code of the user used,
+	// returning ErrInvalidTwoFactorCode when there is none with the hash
+	UseRecoveryCode(ctx context.Context, userID, codeHash string, at time.Time) error
+	// DisableTOTP removes the TOTP secret and the recovery codes of the user
+	DisableTOTP(ctx context.Context, userID string) error
+}
+
+// LoginChallengeRepository defines the interface for login challenge data access
+type LoginChallengeRepository interface {
+	Create(ctx context.Context, challenge *LoginChallenge) error
+	// GetByHash returns ErrInvalidLoginChallenge when no challenge has the hash
+	GetByHash(ctx context.Context, tokenHash string) (*LoginChallenge, error)
+	// Consume marks the challenge used if it is still usable at the given
+	// time, returning ErrInvalidLoginChallenge otherwise
+	Consume(ctx context.Context, challenge *LoginChallenge, at time.Time) error
+}
+
+// MagicLinkRepository defines the interface for magic link login token data access
+type MagicLinkRepository interface {
+	Create(ctx context.Context, token *MagicLinkToken) error
+	// GetByHash returns ErrInvalidMagicLink when no token has the hash
+	GetByHash(ctx context.Context, tokenHash string) (*MagicLinkToken, error)
+	// Consume marks the token used if it is still usable at the given time,
+	// returning ErrInvalidMagicLink otherwise, and invalidates every other
+	// outstanding token of the same user
+	Consume(ctx context.Context, token *MagicLinkToken, at time.Time) error
+	// RecordRequest atomically counts a token request of the user at the
+	// given time, restarting the count when the current window started before
+	// windowStart, and returns the number of requests in the window
+	RecordRequest(ctx context.Context, userID string, at, windowStart time.Time) (int, error)
+}
+
+// PasskeyRepository defines the interface for passkey data access
+type PasskeyRepository interface {
+	// Create returns ErrPasskeyAlreadyRegistered when a passkey has the same credential ID
+	Create(ctx context.Context, passkey *Passkey) error
+	// GetByCredentialID returns ErrPasskeyNotFound when no passkey has the credential ID
+	GetByCredentialID(ctx context.Context, credentialID []byte) (*Passkey, error)
+	// ListByUser returns the passkeys of the user, oldest first
+	ListByUser(ctx context.Context, userID string) ([]*Passkey, error)
+	// Use stores the SignCount and LastUsedAt of the passkey
+	Use(ctx context.Context, passkey *Passkey) error
+}
+
+// PasskeyChallengeRepository defines the interface for WebAuthn challenge data access
+type PasskeyChallengeRepository interface {
+	Create(ctx context.Context, challenge *PasskeyChallenge) error
+	// GetByHash returns ErrInvalidPasskeyChallenge when no challenge has the hash
+	GetByHash(ctx context.Context, challengeHash string) (*PasskeyChallenge, error)
+	// Consume marks the challenge used if it is still usable at the given
+	// time, returning ErrInvalidPasskeyChallenge otherwise
+	Consume(ctx context.Context, challenge *PasskeyChallenge, at time.Time) error
+}
+
+// APIKeyRepository defines the interface for API key data access
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	// GetByID returns ErrAPIKeyNotFound when no key has the ID
+	GetByID(ctx context.Context, id string) (*APIKey, error)
+	// GetByHash returns ErrInvalidAPIKey when no key has the hash
+	GetByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	// ListByUser returns the keys of the user that are not revoked, newest first
+	ListByUser(ctx context.Context, userID string) ([]*APIKey, error)
+	// Revoke sets RevokedAt, returning ErrAPIKeyNotFound when the key does not
+	// exist or is already revoked
+	Revoke(ctx context.Context, key *APIKey, at time.Time) error
+}
+
+// PageCursor marks the last user of a page for keyset pagination.
+// Users are ordered by (CreatedAt, ID) descending.
+type PageCursor struct {
+	CreatedAt time.Time
+	ID        string
 }
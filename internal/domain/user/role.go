@@ -0,0 +1,61 @@
+package user
+
+import "slices"
+
+// Role grants a set of permissions to a user
+type Role string
+
+const (
+	// RoleUser is granted to every account and allows managing oneself
+	RoleUser Role = "user"
+	// RoleAdmin allows managing all accounts and their roles
+	RoleAdmin Role = "admin"
+	// RoleSupport allows inspecting accounts and signing them out
+	RoleSupport Role = "support"
+)
+
+// ParseRole converts a role name into a Role
+func ParseRole(name string) (Role, error) {
+	switch r := Role(name); r {
+	case RoleUser, RoleAdmin, RoleSupport:
+		return r, nil
+	}
+	return "", ErrInvalidRole
+}
+
+// HasRole reports whether the user holds the role
+func (u *User) HasRole(role Role) bool {
+	return slices.Contains(u.Roles, role)
+}
+
+// GrantRole adds the role to the user, returning false if it was already held
+func (u *User) GrantRole(role Role) bool {
+	if u.HasRole(role) {
+		return false
+	}
+	u.Roles = append(u.Roles, role)
+	return true
+}
+
+// RevokeRole removes the role from the user, returning false if it was not held.
+// The base user role cannot be revoked.
+func (u *User) RevokeRole(role Role) (bool, error) {
+	if role == RoleUser {
+		return false, ErrInvalidRole
+	}
+	i := slices.Index(u.Roles, role)
+	if i < 0 {
+		return false, nil
+	}
+	u.Roles = slices.Delete(u.Roles, i, i+1)
+	return true, nil
+}
+
+// RoleNames returns the user's roles as strings
+func (u *User) RoleNames() []string {
+	names := make([]string, len(u.Roles))
+	for i, r := range u.Roles {
+		names[i] = string(r)
+	}
+	return names
+}
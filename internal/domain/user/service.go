@@ -1,17 +1,16 @@
 package user
 
-import (
-	"context"
-	"errors"
-)
+import "context"
 
 // Service defines domain operations that don't naturally fit in entities
 // These are domain services, not application services
 type Service interface {
-	// IsEmailUnique checks if email is not already taken
+	// IsEmailUnique checks if email is not already taken, either as an
+	// address or as a pending address
 	IsEmailUnique(ctx context.Context, email string) (bool, error)
 
-	// CanUserBeDeleted checks business rules for user deletion
+	// CanUserBeDeleted checks business rules for user deletion.
+	// It returns false without an error when a rule blocks the deletion.
 	CanUserBeDeleted(ctx context.Context, userID string) (bool, error)
 }
 
@@ -25,14 +24,11 @@ func NewService(repo Repository) Service {
 }
 
 func (s *service) IsEmailUnique(ctx context.Context, email string) (bool, error) {
-	_, err := s.repo.GetByEmail(ctx, email)
+	exists, err := s.repo.EmailExists(ctx, email)
 	if err != nil {
-		if errors.Is(err, ErrUserNotFound) {
-			return true, nil
-		}
 		return false, err
 	}
-	return false, nil
+	return !exists, nil
 }
 
 func (s *service) CanUserBeDeleted(ctx context.Context, userID string) (bool, error) {
@@ -44,9 +40,9 @@ func (s *service) CanUserBeDeleted(ctx context.Context, userID string) (bool, er
 		return false, err
 	}
 
-	// Example business rule
-	if user.Email == "admin@example.com" {
-		return false, errors.New("admin user cannot be deleted")
+	// Administrators must be demoted before their account can be deleted
+	if user.HasRole(RoleAdmin) {
+		return false, nil
 	}
 
 	return true, nil
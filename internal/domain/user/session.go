@@ -0,0 +1,46 @@
+package user
+
+import "time"
+
+// Session represents a login session backed by a rotating refresh token
+type Session struct {
+	ID               string
+	UserID           string
+	RefreshTokenHash string
+	UserAgent        string
+	IPAddress        string
+	CreatedAt        time.Time
+	LastUsedAt       time.Time
+	ExpiresAt        time.Time
+	RevokedAt        *time.Time
+}
+
+// NewSession starts a session for the user that expires after ttl
+func NewSession(id, userID, refreshTokenHash, userAgent, ipAddress string, ttl time.Duration) *Session {
+	now := time.Now()
+	return &Session{
+		ID:               id,
+		UserID:           userID,
+		RefreshTokenHash: refreshTokenHash,
+		UserAgent:        userAgent,
+		IPAddress:        ipAddress,
+		CreatedAt:        now,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(ttl),
+	}
+}
+
+// IsActive reports whether the session is neither revoked nor expired
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Rotate replaces the refresh token and extends the session by ttl
+func (s *Session) Rotate(refreshTokenHash, userAgent, ipAddress string, ttl time.Duration) {
+	now := time.Now()
+	s.RefreshTokenHash = refreshTokenHash
+	s.UserAgent = userAgent
+	s.IPAddress = ipAddress
+	s.LastUsedAt = now
+	s.ExpiresAt = now.Add(ttl)
+}
@@ -0,0 +1,79 @@
+package user
+
+import "time"
+
+// TOTP is the time-based one-time password second factor of a user
+type TOTP struct {
+	UserID string
+	// Secret is the base32 encoded key shared with the authenticator app.
+	// It is only stored encrypted.
+	Secret string
+	// EnabledAt is nil until the user confirms enrollment with a valid code
+	EnabledAt *time.Time
+	// LastUsedStep is the time step of the last accepted code, so that a
+	// code cannot be used twice
+	LastUsedStep int64
+}
+
+// NewTOTP starts the enrollment of secret as the user's second factor
+func NewTOTP(userID, secret string) *TOTP {
+	return &TOTP{
+		UserID: userID,
+		Secret: secret,
+	}
+}
+
+// IsEnabled reports whether enrollment was confirmed
+func (t *TOTP) IsEnabled() bool {
+	return t.EnabledAt != nil
+}
+
+// UseStep accepts a code of the given time step, returning
+// ErrInvalidTwoFactorCode when a code of that or a later step was already used
+func (t *TOTP) UseStep(step int64) error {
+	if step <= t.LastUsedStep {
+		return ErrInvalidTwoFactorCode
+	}
+	t.LastUsedStep = step
+	return nil
+}
+
+// Enable confirms enrollment with a code of the given time step
+func (t *TOTP) Enable(step int64) error {
+	if t.IsEnabled() {
+		return ErrTwoFactorAlreadyEnabled
+	}
+	if err := t.UseStep(step); err != nil {
+		return err
+	}
+	now := time.Now()
+	t.EnabledAt = &now
+	return nil
+}
+
+// LoginChallenge is issued instead of session tokens when a user with two-factor
+// authentication enabled logs in with a correct password. Only the hash of the
+// token sent to the client is kept.
+type LoginChallenge struct {
+	TokenHash string
+	UserID    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// NewLoginChallenge creates a login challenge for the user that expires after ttl
+func NewLoginChallenge(tokenHash, userID string, ttl time.Duration) *LoginChallenge {
+	now := time.Now()
+	return &LoginChallenge{
+		TokenHash: tokenHash,
+		UserID:    userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// IsUsable reports whether the challenge is neither used nor expired
+func (c *LoginChallenge) IsUsable(now time.Time) bool {
+	return c.UsedAt == nil && now.Before(c.ExpiresAt)
+}
@@ -0,0 +1,40 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTP_Enable(t *testing.T) {
+	totp := NewTOTP("user-1", "SECRET")
+	assert.False(t, totp.IsEnabled())
+
+	require.NoError(t, totp.Enable(100))
+	assert.True(t, totp.IsEnabled())
+	assert.Equal(t, int64(100), totp.LastUsedStep)
+
+	assert.ErrorIs(t, totp.Enable(101), ErrTwoFactorAlreadyEnabled)
+}
+
+func TestTOTP_UseStep(t *testing.T) {
+	totp := &TOTP{UserID: "user-1", LastUsedStep: 100}
+
+	assert.ErrorIs(t, totp.UseStep(100), ErrInvalidTwoFactorCode, "replayed code")
+	assert.ErrorIs(t, totp.UseStep(99), ErrInvalidTwoFactorCode, "code older than the last used one")
+	assert.NoError(t, totp.UseStep(101))
+	assert.Equal(t, int64(101), totp.LastUsedStep)
+}
+
+func TestLoginChallenge_IsUsable(t *testing.T) {
+	c := NewLoginChallenge("hash", "user-1", time.Minute)
+	now := time.Now()
+
+	assert.True(t, c.IsUsable(now))
+	assert.False(t, c.IsUsable(now.Add(2*time.Minute)), "expired")
+
+	c.UsedAt = &now
+	assert.False(t, c.IsUsable(now), "used")
+}
@@ -1,10 +1,6 @@
 package user
 
-import (
-	"time"
-
-	"golang.org/x/crypto/bcrypt"
-)
+import "time"
 
 // User represents a user in the system
 type User struct {
@@ -12,23 +8,35 @@ type User struct {
 	Email     string
 	Name      string
 	Password  string // Hashed password
+	Roles     []Role
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// EmailVerifiedAt is nil until the user proves they own Email
+	EmailVerifiedAt *time.Time
+	// PendingEmail is the address the user asked to switch to, empty if none.
+	// It replaces Email once verified.
+	PendingEmail string
+	// TwoFactorEnabledAt is nil unless login requires a TOTP code
+	TwoFactorEnabledAt *time.Time
+	// Version counts the changes to the profile, roles, email and password,
+	// so that changes based on a stale read can be detected. Two-factor
+	// bookkeeping does not change it.
+	Version int64
 }
 
-// NewUser creates a new user with hashed password
-func NewUser(email, name, password string) (*User, error) {
+// NewUser creates a new user with a password hashed after checking it against policy
+func NewUser(email, name, password string, policy PasswordPolicy, hasher PasswordHasher) (*User, error) {
 	if email == "" {
 		return nil, ErrInvalidEmail
 	}
 	if name == "" {
 		return nil, ErrInvalidName
 	}
-	if len(password) < 8 {
-		return nil, ErrWeakPassword
+	if err := policy.Validate(password); err != nil {
+		return nil, err
 	}
 
-	hashedPassword, err := hashPassword(password)
+	hashedPassword, err := hasher.Hash(password)
 	if err != nil {
 		return nil, err
 	}
@@ -38,17 +46,86 @@ func NewUser(email, name, password string) (*User, error) {
 		Email:     email,
 		Name:      name,
 		Password:  hashedPassword,
+		Roles:     []Role{RoleUser},
 		CreatedAt: now,
 		UpdatedAt: now,
 	}, nil
 }
 
 // CheckPassword verifies if the provided password is correct
-func (u *User) CheckPassword(password string) error {
-	return bcrypt.CompareHashAndPassword(
-		[]byte(u.Password),
-		[]byte(password),
-	)
+func (u *User) CheckPassword(password string, hasher PasswordHasher) error {
+	return hasher.Verify(u.Password, password)
+}
+
+// UpgradePasswordHash rehashes a password that was just verified when its
+// hash was made with outdated settings. It reports whether the hash changed.
+func (u *User) UpgradePasswordHash(password string, hasher PasswordHasher) (bool, error) {
+	if !hasher.NeedsRehash(u.Password) {
+		return false, nil
+	}
+
+	hashedPassword, err := hasher.Hash(password)
+	if err != nil {
+		return false, err
+	}
+
+	u.Password = hashedPassword
+	return true, nil
+}
+
+// ChangePassword replaces the password hash after checking newPassword against
+// policy. previousHashes are the hashes the password replaced, newest first.
+func (u *User) ChangePassword(newPassword string, previousHashes []string, policy PasswordPolicy, hasher PasswordHasher) error {
+	recentHashes := append([]string{u.Password}, previousHashes...)
+	if err := policy.ValidateChange(newPassword, recentHashes, hasher); err != nil {
+		return err
+	}
+
+	hashedPassword, err := hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	u.Password = hashedPassword
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsEmailVerified reports whether the user has verified their email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// VerifyEmail records that the user proved they own their email address
+func (u *User) VerifyEmail() {
+	now := time.Now()
+	u.EmailVerifiedAt = &now
+	u.UpdatedAt = now
+}
+
+// IsTwoFactorEnabled reports whether login requires a second factor
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil
+}
+
+// RequestEmailChange holds newEmail as pending until the user verifies it
+func (u *User) RequestEmailChange(newEmail string) error {
+	if newEmail == "" {
+		return ErrInvalidEmail
+	}
+	if newEmail == u.Email {
+		return ErrEmailUnchanged
+	}
+	u.PendingEmail = newEmail
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+// ConfirmEmailChange replaces the email with the verified pending address
+func (u *User) ConfirmEmailChange() {
+	u.Email = u.PendingEmail
+	u.PendingEmail = ""
+	u.VerifyEmail()
 }
 
 // UpdateProfile updates user profile fields
@@ -61,10 +138,39 @@ func (u *User) UpdateProfile(name string) error {
 	return nil
 }
 
-func hashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword(
-		[]byte(password),
-		bcrypt.DefaultCost,
-	)
-	return string(bytes), err
+// Field paths of the profile, as named in update masks
+const (
+	ProfilePathName = "name"
+)
+
+// ProfileUpdate holds new values for the profile fields listed in Paths.
+// Fields that are not listed keep their value.
+type ProfileUpdate struct {
+	Paths []string
+	Name  string
+}
+
+// ApplyProfileUpdate updates the profile fields listed in update.Paths. It
+// returns ErrInvalidUpdateMask when no path or an unknown path is listed, in
+// which case nothing is changed.
+func (u *User) ApplyProfileUpdate(update ProfileUpdate) error {
+	if len(update.Paths) == 0 {
+		return ErrInvalidUpdateMask
+	}
+	name := u.Name
+	for _, path := range update.Paths {
+		switch path {
+		case ProfilePathName:
+			if update.Name == "" {
+				return ErrInvalidName
+			}
+			name = update.Name
+		default:
+			return ErrInvalidUpdateMask
+		}
+	}
+
+	u.Name = name
+	u.UpdatedAt = time.Now()
+	return nil
 }
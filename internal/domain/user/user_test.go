@@ -1,12 +1,41 @@
 package user
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// fakeHasher is a PasswordHasher that prefixes passwords with its version,
+// treating hashes of other versions as outdated
+type fakeHasher struct {
+	version string
+}
+
+func (h fakeHasher) Hash(password string) (string, error) {
+	return h.version + ":" + password, nil
+}
+
+func (h fakeHasher) Verify(hash, password string) error {
+	_, stored, _ := strings.Cut(hash, ":")
+	if stored != password {
+		return errors.New("password does not match")
+	}
+	return nil
+}
+
+func (h fakeHasher) NeedsRehash(hash string) bool {
+	return !strings.HasPrefix(hash, h.version+":")
+}
+
+var (
+	testHasher = fakeHasher{version: "v1"}
+	testPolicy = PasswordPolicy{MinLength: 8, MaxLength: 64, HistorySize: 3}
+)
+
 func TestNewUser(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -47,7 +76,7 @@ func TestNewUser(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			user, err := NewUser(tt.email, tt.userName, tt.password)
+			user, err := NewUser(tt.email, tt.userName, tt.password, testPolicy, testHasher)
 			if tt.wantErr != nil {
 				require.ErrorIs(t, err, tt.wantErr)
 				assert.Nil(t, user)
@@ -64,7 +93,7 @@ func TestNewUser(t *testing.T) {
 }
 
 func TestUser_CheckPassword(t *testing.T) {
-	user, err := NewUser("test@example.com", "Test", "password123")
+	user, err := NewUser("test@example.com", "Test", "password123", testPolicy, testHasher)
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -86,7 +115,7 @@ func TestUser_CheckPassword(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := user.CheckPassword(tt.password)
+			err := user.CheckPassword(tt.password, testHasher)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -97,7 +126,7 @@ func TestUser_CheckPassword(t *testing.T) {
 }
 
 func TestUser_UpdateProfile(t *testing.T) {
-	user, err := NewUser("test@example.com", "Test", "password123")
+	user, err := NewUser("test@example.com", "Test", "password123", testPolicy, testHasher)
 	require.NoError(t, err)
 
 	oldUpdatedAt := user.UpdatedAt
@@ -110,3 +139,117 @@ func TestUser_UpdateProfile(t *testing.T) {
 	err = user.UpdateProfile("")
 	assert.ErrorIs(t, err, ErrInvalidName)
 }
+
+func TestUser_ApplyProfileUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		update   ProfileUpdate
+		wantName string
+		wantErr  error
+	}{
+		{
+			name:     "masked name",
+			update:   ProfileUpdate{Paths: []string{ProfilePathName}, Name: "New Name"},
+			wantName: "New Name",
+		},
+		{
+			name:    "empty masked name",
+			update:  ProfileUpdate{Paths: []string{ProfilePathName}},
+			wantErr: ErrInvalidName,
+		},
+		{
+			name:    "empty mask",
+			update:  ProfileUpdate{Name: "New Name"},
+			wantErr: ErrInvalidUpdateMask,
+		},
+		{
+			name:    "unknown path",
+			update:  ProfileUpdate{Paths: []string{ProfilePathName, "email"}, Name: "New Name"},
+			wantErr: ErrInvalidUpdateMask,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := NewUser("test@example.com", "Test", "password123", testPolicy, testHasher)
+			require.NoError(t, err)
+
+			err = user.ApplyProfileUpdate(tt.update)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, "Test", user.Name)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, user.Name)
+		})
+	}
+}
+
+func TestUser_ChangePassword(t *testing.T) {
+	user, err := NewUser("test@example.com", "Test", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+	oldHash := user.Password
+
+	err = user.ChangePassword("short", nil, testPolicy, testHasher)
+	assert.ErrorIs(t, err, ErrWeakPassword)
+	assert.Equal(t, oldHash, user.Password)
+
+	err = user.ChangePassword("password123", nil, testPolicy, testHasher)
+	assert.ErrorIs(t, err, ErrWeakPassword, "current password cannot be reused")
+
+	err = user.ChangePassword("older-password", []string{"v1:older-password"}, testPolicy, testHasher)
+	assert.ErrorIs(t, err, ErrWeakPassword, "recent password cannot be reused")
+
+	err = user.ChangePassword("new-password456", []string{"v1:older-password"}, testPolicy, testHasher)
+	require.NoError(t, err)
+	assert.NotEqual(t, oldHash, user.Password)
+	assert.NoError(t, user.CheckPassword("new-password456", testHasher))
+	assert.Error(t, user.CheckPassword("password123", testHasher))
+}
+
+func TestUser_UpgradePasswordHash(t *testing.T) {
+	user, err := NewUser("test@example.com", "Test", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+	oldHash := user.Password
+
+	upgraded, err := user.UpgradePasswordHash("password123", testHasher)
+	require.NoError(t, err)
+	assert.False(t, upgraded)
+	assert.Equal(t, oldHash, user.Password)
+
+	newHasher := fakeHasher{version: "v2"}
+	upgraded, err = user.UpgradePasswordHash("password123", newHasher)
+	require.NoError(t, err)
+	assert.True(t, upgraded)
+	assert.NotEqual(t, oldHash, user.Password)
+	assert.False(t, newHasher.NeedsRehash(user.Password))
+	assert.NoError(t, user.CheckPassword("password123", newHasher))
+}
+
+func TestUser_VerifyEmail(t *testing.T) {
+	user, err := NewUser("test@example.com", "Test", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+	assert.False(t, user.IsEmailVerified())
+
+	user.VerifyEmail()
+	assert.True(t, user.IsEmailVerified())
+	assert.Equal(t, *user.EmailVerifiedAt, user.UpdatedAt)
+}
+
+func TestUser_EmailChange(t *testing.T) {
+	user, err := NewUser("old@example.com", "Test", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, user.RequestEmailChange(""), ErrInvalidEmail)
+	assert.ErrorIs(t, user.RequestEmailChange("old@example.com"), ErrEmailUnchanged)
+
+	require.NoError(t, user.RequestEmailChange("new@example.com"))
+	assert.Equal(t, "old@example.com", user.Email)
+	assert.Equal(t, "new@example.com", user.PendingEmail)
+
+	user.ConfirmEmailChange()
+	assert.Equal(t, "new@example.com", user.Email)
+	assert.Empty(t, user.PendingEmail)
+	assert.True(t, user.IsEmailVerified())
+}
@@ -0,0 +1,237 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/memclutter/go-microservices-template/api/gen/user"
+	domainUser "github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// accessPolicy declares whether an RPC requires an authenticated caller
+type accessPolicy int
+
+const (
+	protected accessPolicy = iota
+	public
+)
+
+// methodPolicies declares the access policy of every RPC served by the gRPC
+// server. Methods missing from this table are protected.
+var methodPolicies = map[string]accessPolicy{
+	user.UserService_CreateUser_FullMethodName:                public,
+	user.UserService_GetUser_FullMethodName:                   protected,
+	user.UserService_UpdateUser_FullMethodName:                protected,
+	user.UserService_DeleteUser_FullMethodName:                protected,
+	user.UserService_ListUsers_FullMethodName:                 protected,
+	user.UserService_BatchGetUsers_FullMethodName:             protected,
+	user.UserService_Login_FullMethodName:                     public,
+	user.UserService_RefreshToken_FullMethodName:              public,
+	user.UserService_ListSessions_FullMethodName:              protected,
+	user.UserService_RevokeSession_FullMethodName:             protected,
+	user.UserService_RevokeAllSessions_FullMethodName:         protected,
+	user.UserService_UpdateUserRoles_FullMethodName:           protected,
+	user.UserService_ChangePassword_FullMethodName:            protected,
+	user.UserService_RequestPasswordReset_FullMethodName:      public,
+	user.UserService_ConfirmPasswordReset_FullMethodName:      public,
+	user.UserService_VerifyEmail_FullMethodName:               public,
+	user.UserService_ChangeEmail_FullMethodName:               protected,
+	user.UserService_UnlockUser_FullMethodName:                protected,
+	user.UserService_StartTOTPEnrollment_FullMethodName:       protected,
+	user.UserService_ConfirmTOTPEnrollment_FullMethodName:     protected,
+	user.UserService_DisableTOTP_FullMethodName:               protected,
+	user.UserService_VerifyLoginChallenge_FullMethodName:      public,
+	user.UserService_RequestMagicLink_FullMethodName:          public,
+	user.UserService_ConsumeMagicLink_FullMethodName:          public,
+	user.UserService_BeginPasskeyRegistration_FullMethodName:  protected,
+	user.UserService_FinishPasskeyRegistration_FullMethodName: protected,
+	user.UserService_BeginPasskeyLogin_FullMethodName:         public,
+	user.UserService_FinishPasskeyLogin_FullMethodName:        public,
+	user.UserService_CreateAPIKey_FullMethodName:              protected,
+	user.UserService_ListAPIKeys_FullMethodName:               protected,
+	user.UserService_RevokeAPIKey_FullMethodName:              protected,
+	user.UserService_GetMe_FullMethodName:                     protected,
+	user.UserService_UpdateMe_FullMethodName:                  protected,
+
+	reflectionv1.ServerReflection_ServerReflectionInfo_FullMethodName:      public,
+	reflectionv1alpha.ServerReflection_ServerReflectionInfo_FullMethodName: public,
+}
+
+// APIKeyScopes returns the full names of the UserService methods, which are
+// the methods API keys may be scoped to
+func APIKeyScopes() []string {
+	prefix := "/" + user.UserService_ServiceDesc.ServiceName + "/"
+	scopes := make([]string, 0, len(methodPolicies))
+	for method := range methodPolicies {
+		if strings.HasPrefix(method, prefix) {
+			scopes = append(scopes, method)
+		}
+	}
+	slices.Sort(scopes)
+	return scopes
+}
+
+// TokenVerifier verifies access tokens presented by callers
+type TokenVerifier interface {
+	VerifyAccessToken(token string) (*auth.Claims, error)
+}
+
+// SessionAuthenticator checks that the session of an access token is still
+// active, returning domainUser.ErrSessionRevoked otherwise
+type SessionAuthenticator interface {
+	Execute(ctx context.Context, userID, sessionID string) error
+}
+
+// APIKeyAuthenticator looks up API keys presented by service callers
+type APIKeyAuthenticator interface {
+	Execute(ctx context.Context, key string) (*domainUser.APIKey, error)
+}
+
+// AuthInterceptor authenticates bearer tokens from the authorization metadata
+// or API keys from the x-api-key metadata, and stores the caller in the
+// request context as an auth.Principal
+type AuthInterceptor struct {
+	verifier TokenVerifier
+	sessions SessionAuthenticator
+	apiKeys  APIKeyAuthenticator
+	logger   *logger.Logger
+}
+
+// NewAuthInterceptor creates a new authentication interceptor
+func NewAuthInterceptor(verifier TokenVerifier, sessions SessionAuthenticator, apiKeys APIKeyAuthenticator, log *logger.Logger) *AuthInterceptor {
+	return &AuthInterceptor{
+		verifier: verifier,
+		sessions: sessions,
+		apiKeys:  apiKeys,
+		logger:   log,
+	}
+}
+
+// Unary returns the interceptor for unary RPCs
+func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := i.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns the interceptor for streaming RPCs
+func (i *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := i.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate returns ctx with the caller's principal. Public methods are
+// let through without credentials, but presented credentials must still be valid.
+func (i *AuthInterceptor) authenticate(ctx context.Context, method string) (context.Context, error) {
+	token, found := bearerToken(ctx)
+	key, keyFound := apiKey(ctx)
+	if found && keyFound {
+		return nil, newStatusError(codes.Unauthenticated, "only one of access token and api key may be presented", "AMBIGUOUS_CREDENTIALS", "")
+	}
+	if keyFound {
+		return i.authenticateAPIKey(ctx, method, key)
+	}
+	if !found {
+		if methodPolicies[method] == public {
+			return ctx, nil
+		}
+		return nil, newStatusError(codes.Unauthenticated, "missing access token", "MISSING_ACCESS_TOKEN", "")
+	}
+
+	claims, err := i.verifier.VerifyAccessToken(token)
+	if err != nil {
+		i.logger.WithField("method", method).Info("Rejected request with invalid access token")
+		return nil, newStatusError(codes.Unauthenticated, "invalid access token", "INVALID_ACCESS_TOKEN", "")
+	}
+
+	// Access tokens end with their session rather than only at expiry
+	if err := i.sessions.Execute(ctx, claims.Subject, claims.SessionID); err != nil {
+		if errors.Is(err, domainUser.ErrSessionRevoked) {
+			i.logger.WithField("method", method).Info("Rejected request with access token of a revoked session")
+			return nil, newStatusError(codes.Unauthenticated, "session was revoked", "SESSION_REVOKED", "")
+		}
+		return nil, toStatusError(err)
+	}
+
+	return auth.WithPrincipal(ctx, &auth.Principal{
+		UserID:    claims.Subject,
+		SessionID: claims.SessionID,
+	}), nil
+}
+
+// authenticateAPIKey returns ctx with the owner of the API key as principal,
+// provided the key is scoped to the method
+func (i *AuthInterceptor) authenticateAPIKey(ctx context.Context, method, key string) (context.Context, error) {
+	apiKey, err := i.apiKeys.Execute(ctx, key)
+	if err != nil {
+		if errors.Is(err, domainUser.ErrInvalidAPIKey) {
+			i.logger.WithField("method", method).Info("Rejected request with invalid api key")
+		}
+		return nil, toStatusError(err)
+	}
+	if !apiKey.Allows(method) {
+		i.logger.WithFields(map[string]any{
+			"method":     method,
+			"api_key_id": apiKey.ID,
+		}).Info("Rejected request outside api key scopes")
+		return nil, newStatusError(codes.PermissionDenied, "api key is not scoped to this method", "API_KEY_SCOPE_DENIED", "")
+	}
+
+	return auth.WithPrincipal(ctx, &auth.Principal{
+		UserID:   apiKey.UserID,
+		APIKeyID: apiKey.ID,
+	}), nil
+}
+
+// apiKey extracts the key from "x-api-key: <key>" metadata
+func apiKey(ctx context.Context) (string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("x-api-key")
+	if len(values) == 0 {
+		return "", false
+	}
+	return strings.TrimSpace(values[0]), true
+}
+
+// bearerToken extracts the token from "authorization: Bearer <token>" metadata
+func bearerToken(ctx context.Context) (string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", false
+	}
+
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", true
+	}
+	return strings.TrimSpace(token), true
+}
+
+// authenticatedStream overrides the context of a server stream
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,165 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/memclutter/go-microservices-template/api/gen/user"
+	domainUser "github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeVerifier struct{}
+
+func (fakeVerifier) VerifyAccessToken(token string) (*auth.Claims, error) {
+	sessionID := "session-1"
+	switch token {
+	case "valid":
+	case "of-revoked-session":
+		sessionID = "session-2"
+	default:
+		return nil, auth.ErrInvalidToken
+	}
+	claims := &auth.Claims{SessionID: sessionID}
+	claims.Subject = "user-1"
+	return claims, nil
+}
+
+type fakeSessions struct{}
+
+func (fakeSessions) Execute(ctx context.Context, userID, sessionID string) error {
+	if sessionID != "session-1" {
+		return domainUser.ErrSessionRevoked
+	}
+	return nil
+}
+
+type fakeAPIKeys struct{}
+
+func (fakeAPIKeys) Execute(ctx context.Context, key string) (*domainUser.APIKey, error) {
+	if key != "mst_valid" {
+		return nil, domainUser.ErrInvalidAPIKey
+	}
+	return &domainUser.APIKey{
+		ID:     "key-1",
+		UserID: "service-1",
+		Scopes: []string{user.UserService_GetUser_FullMethodName},
+	}, nil
+}
+
+func TestAuthInterceptor_Unary(t *testing.T) {
+	interceptor := NewAuthInterceptor(fakeVerifier{}, fakeSessions{}, fakeAPIKeys{}, logger.New("test"))
+	unary := interceptor.Unary()
+
+	var principal *auth.Principal
+	handler := func(ctx context.Context, req any) (any, error) {
+		principal, _ = auth.PrincipalFromContext(ctx)
+		return "ok", nil
+	}
+
+	call := func(method, authorization string) error {
+		principal = nil
+		ctx := context.Background()
+		if authorization != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", authorization))
+		}
+		_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		wantCode      codes.Code
+		wantPrincipal bool
+	}{
+		{name: "public without token", method: user.UserService_Login_FullMethodName, wantCode: codes.OK},
+		{name: "public with invalid token", method: user.UserService_CreateUser_FullMethodName, authorization: "Bearer bad", wantCode: codes.Unauthenticated},
+		{name: "protected without token", method: user.UserService_DeleteUser_FullMethodName, wantCode: codes.Unauthenticated},
+		{name: "protected with invalid token", method: user.UserService_DeleteUser_FullMethodName, authorization: "Bearer bad", wantCode: codes.Unauthenticated},
+		{name: "protected with wrong scheme", method: user.UserService_DeleteUser_FullMethodName, authorization: "Basic valid", wantCode: codes.Unauthenticated},
+		{name: "protected with valid token", method: user.UserService_DeleteUser_FullMethodName, authorization: "Bearer valid", wantCode: codes.OK, wantPrincipal: true},
+		{name: "protected with token of revoked session", method: user.UserService_DeleteUser_FullMethodName, authorization: "Bearer of-revoked-session", wantCode: codes.Unauthenticated},
+		{name: "public with token of revoked session", method: user.UserService_Login_FullMethodName, authorization: "Bearer of-revoked-session", wantCode: codes.Unauthenticated},
+		{name: "undeclared method is protected", method: "/other.Service/Method", wantCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := call(tt.method, tt.authorization)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantPrincipal {
+				require.NotNil(t, principal)
+				assert.Equal(t, "user-1", principal.UserID)
+				assert.Equal(t, "session-1", principal.SessionID)
+			} else {
+				assert.Nil(t, principal)
+			}
+		})
+	}
+}
+
+func TestAuthInterceptor_APIKey(t *testing.T) {
+	interceptor := NewAuthInterceptor(fakeVerifier{}, fakeSessions{}, fakeAPIKeys{}, logger.New("test"))
+	unary := interceptor.Unary()
+
+	var principal *auth.Principal
+	handler := func(ctx context.Context, req any) (any, error) {
+		principal, _ = auth.PrincipalFromContext(ctx)
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		md       metadata.MD
+		wantCode codes.Code
+	}{
+		{name: "scoped method", method: user.UserService_GetUser_FullMethodName, md: metadata.Pairs("x-api-key", "mst_valid"), wantCode: codes.OK},
+		{name: "method outside scopes", method: user.UserService_DeleteUser_FullMethodName, md: metadata.Pairs("x-api-key", "mst_valid"), wantCode: codes.PermissionDenied},
+		{name: "invalid key", method: user.UserService_GetUser_FullMethodName, md: metadata.Pairs("x-api-key", "mst_bad"), wantCode: codes.Unauthenticated},
+		{name: "invalid key on public method", method: user.UserService_Login_FullMethodName, md: metadata.Pairs("x-api-key", "mst_bad"), wantCode: codes.Unauthenticated},
+		{name: "key and token", method: user.UserService_GetUser_FullMethodName, md: metadata.Pairs("x-api-key", "mst_valid", "authorization", "Bearer valid"), wantCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal = nil
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				require.NotNil(t, principal)
+				assert.Equal(t, "service-1", principal.UserID)
+				assert.Equal(t, "key-1", principal.APIKeyID)
+				assert.Empty(t, principal.SessionID)
+			} else {
+				assert.Nil(t, principal)
+			}
+		})
+	}
+}
+
+func TestMethodPolicies_CoverUserService(t *testing.T) {
+	for _, m := range user.UserService_ServiceDesc.Methods {
+		method := "/" + user.UserService_ServiceDesc.ServiceName + "/" + m.MethodName
+		_, declared := methodPolicies[method]
+		assert.True(t, declared, "access policy for %s is not declared", method)
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	scopes := APIKeyScopes()
+
+	assert.Len(t, scopes, len(user.UserService_ServiceDesc.Methods))
+	assert.Contains(t, scopes, user.UserService_GetUser_FullMethodName)
+	assert.NotContains(t, scopes, "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo")
+}
@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// TrustedProxies lists the networks of proxies whose x-forwarded-for
+// metadata is believed, such as the HTTP gateway and load balancers
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses CIDRs such as 10.0.0.0/8. Plain IP addresses
+// are taken as a network of that address alone.
+func ParseTrustedProxies(cidrs []string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", cidr)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+func (p TrustedProxies) contains(ip net.IP) bool {
+	for _, network := range p {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientInfo returns the caller's user agent and IP address.
+// Requests proxied by the HTTP gateway carry the original user agent in
+// grpcgateway-user-agent metadata. The IP address is the peer's, unless the
+// peer is a trusted proxy: then it is the right-most x-forwarded-for entry
+// that is not a trusted proxy, since entries left of it are set by the
+// client and can be spoofed.
+func clientInfo(ctx context.Context, proxies TrustedProxies) (userAgent, ipAddress string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if v := md.Get("grpcgateway-user-agent"); len(v) > 0 {
+		userAgent = v[0]
+	} else if v := md.Get("user-agent"); len(v) > 0 {
+		userAgent = v[0]
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return userAgent, ""
+	}
+	ipAddress = p.Addr.String()
+	if host, _, err := net.SplitHostPort(ipAddress); err == nil {
+		ipAddress = host
+	}
+
+	ip := net.ParseIP(ipAddress)
+	if ip == nil || !proxies.contains(ip) {
+		return userAgent, ipAddress
+	}
+	forwarded := strings.Split(strings.Join(md.Get("x-forwarded-for"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if hop == nil {
+			break
+		}
+		ip = hop
+		if !proxies.contains(hop) {
+			break
+		}
+	}
+
+	return userAgent, ip.String()
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"127.0.0.1/32", " 10.0.0.0/8 ", "::1", ""})
+	require.NoError(t, err)
+	assert.Len(t, proxies, 3)
+	assert.True(t, proxies.contains(net.ParseIP("10.1.2.3")))
+	assert.True(t, proxies.contains(net.ParseIP("::1")))
+	assert.False(t, proxies.contains(net.ParseIP("192.0.2.1")))
+
+	_, err = ParseTrustedProxies([]string{"not-a-network"})
+	assert.Error(t, err)
+}
+
+func TestClientInfo(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"127.0.0.1/32", "10.0.0.0/8"})
+	require.NoError(t, err)
+
+	call := func(peerAddr string, forwardedFor ...string) string {
+		addr, err := net.ResolveTCPAddr("tcp", peerAddr)
+		require.NoError(t, err)
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+		md := metadata.MD{}
+		for _, v := range forwardedFor {
+			md.Append("x-forwarded-for", v)
+		}
+		_, ipAddress := clientInfo(metadata.NewIncomingContext(ctx, md), proxies)
+		return ipAddress
+	}
+
+	tests := []struct {
+		name         string
+		peer         string
+		forwardedFor []string
+		want         string
+	}{
+		{name: "direct client", peer: "198.51.100.7:4000", want: "198.51.100.7"},
+		{name: "direct client sending the header", peer: "198.51.100.7:4000", forwardedFor: []string{"203.0.113.1"}, want: "198.51.100.7"},
+		{name: "gateway", peer: "127.0.0.1:4000", forwardedFor: []string{"198.51.100.7"}, want: "198.51.100.7"},
+		{name: "gateway without header", peer: "127.0.0.1:4000", want: "127.0.0.1"},
+		{name: "spoofed leading entry", peer: "127.0.0.1:4000", forwardedFor: []string{"203.0.113.1, 198.51.100.7"}, want: "198.51.100.7"},
+		{name: "chain of trusted proxies", peer: "127.0.0.1:4000", forwardedFor: []string{"203.0.113.1, 198.51.100.7", "10.0.0.5"}, want: "198.51.100.7"},
+		{name: "garbage entry", peer: "127.0.0.1:4000", forwardedFor: []string{"garbage, 10.0.0.5"}, want: "10.0.0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, call(tt.peer, tt.forwardedFor...))
+		})
+	}
+
+	// A client choosing its own leading entry keeps its throttled address
+	assert.Equal(t,
+		call("127.0.0.1:4000", "203.0.113.1, 198.51.100.7"),
+		call("127.0.0.1:4000", "203.0.113.2, 198.51.100.7"),
+	)
+}
@@ -0,0 +1,188 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	domainUser "github.com/memclutter/go-microservices-template/internal/domain/user"
+	userUseCase "github.com/memclutter/go-microservices-template/internal/usecase/user"
+	"github.com/memclutter/go-microservices-template/pkg/pagination"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// errorDomain identifies this service in google.rpc.ErrorInfo details
+const errorDomain = "user.UserService"
+
+// errorMapping describes how a use case error is exposed over gRPC
+type errorMapping struct {
+	err    error
+	code   codes.Code
+	reason string
+	field  string // request field reported in google.rpc.BadRequest
+}
+
+var errorMappings = []errorMapping{
+	{err: domainUser.ErrInvalidEmail, code: codes.InvalidArgument, reason: "INVALID_EMAIL", field: "email"},
+	{err: domainUser.ErrInvalidName, code: codes.InvalidArgument, reason: "INVALID_NAME", field: "name"},
+	{err: domainUser.ErrWeakPassword, code: codes.InvalidArgument, reason: "WEAK_PASSWORD", field: "password"},
+	{err: domainUser.ErrIncorrectPassword, code: codes.InvalidArgument, reason: "INCORRECT_PASSWORD", field: "current_password"},
+	{err: domainUser.ErrInvalidResetToken, code: codes.InvalidArgument, reason: "INVALID_RESET_TOKEN", field: "token"},
+	{err: domainUser.ErrInvalidVerificationToken, code: codes.InvalidArgument, reason: "INVALID_VERIFICATION_TOKEN", field: "token"},
+	{err: domainUser.ErrEmailUnchanged, code: codes.InvalidArgument, reason: "EMAIL_UNCHANGED", field: "new_email"},
+	{err: domainUser.ErrInvalidRole, code: codes.InvalidArgument, reason: "INVALID_ROLE", field: "roles"},
+	{err: domainUser.ErrInvalidUpdateMask, code: codes.InvalidArgument, reason: "INVALID_UPDATE_MASK", field: "update_mask"},
+	{err: domainUser.ErrInvalidTwoFactorCode, code: codes.InvalidArgument, reason: "INVALID_TWO_FACTOR_CODE", field: "code"},
+	{err: domainUser.ErrInvalidPasskeyName, code: codes.InvalidArgument, reason: "INVALID_PASSKEY_NAME", field: "name"},
+	{err: domainUser.ErrInvalidPasskeyChallenge, code: codes.InvalidArgument, reason: "INVALID_PASSKEY_CHALLENGE", field: "client_data_json"},
+	{err: domainUser.ErrInvalidPasskeyResponse, code: codes.InvalidArgument, reason: "INVALID_PASSKEY_RESPONSE"},
+	{err: domainUser.ErrInvalidAPIKeyName, code: codes.InvalidArgument, reason: "INVALID_API_KEY_NAME", field: "name"},
+	{err: domainUser.ErrInvalidAPIKeyScope, code: codes.InvalidArgument, reason: "INVALID_API_KEY_SCOPE", field: "scopes"},
+	{err: domainUser.ErrInvalidAPIKeyExpiry, code: codes.InvalidArgument, reason: "INVALID_API_KEY_EXPIRY", field: "expires_at"},
+	{err: pagination.ErrInvalidPageToken, code: codes.InvalidArgument, reason: "INVALID_PAGE_TOKEN", field: "page_token"},
+	{err: userUseCase.ErrTooManyUserIDs, code: codes.InvalidArgument, reason: "TOO_MANY_USER_IDS", field: "user_ids"},
+	{err: domainUser.ErrUserNotFound, code: codes.NotFound, reason: "USER_NOT_FOUND"},
+	{err: domainUser.ErrSessionNotFound, code: codes.NotFound, reason: "SESSION_NOT_FOUND"},
+	{err: domainUser.ErrPasskeyNotFound, code: codes.NotFound, reason: "PASSKEY_NOT_FOUND"},
+	{err: domainUser.ErrAPIKeyNotFound, code: codes.NotFound, reason: "API_KEY_NOT_FOUND"},
+	{err: domainUser.ErrUserAlreadyExists, code: codes.AlreadyExists, reason: "USER_ALREADY_EXISTS"},
+	{err: domainUser.ErrPasskeyAlreadyRegistered, code: codes.AlreadyExists, reason: "PASSKEY_ALREADY_REGISTERED"},
+	{err: domainUser.ErrAPIKeyAlreadyExists, code: codes.AlreadyExists, reason: "API_KEY_ALREADY_EXISTS"},
+	{err: domainUser.ErrUnauthorized, code: codes.Unauthenticated, reason: "UNAUTHORIZED"},
+	{err: domainUser.ErrInvalidLoginChallenge, code: codes.Unauthenticated, reason: "INVALID_LOGIN_CHALLENGE"},
+	{err: domainUser.ErrInvalidMagicLink, code: codes.Unauthenticated, reason: "INVALID_MAGIC_LINK"},
+	{err: domainUser.ErrInvalidAPIKey, code: codes.Unauthenticated, reason: "INVALID_API_KEY"},
+	{err: domainUser.ErrPermissionDenied, code: codes.PermissionDenied, reason: "PERMISSION_DENIED"},
+	{err: domainUser.ErrAPIKeyScopeNotHeld, code: codes.PermissionDenied, reason: "API_KEY_SCOPE_NOT_HELD"},
+	{err: domainUser.ErrEmailNotVerified, code: codes.FailedPrecondition, reason: "EMAIL_NOT_VERIFIED"},
+	{err: domainUser.ErrUserCannotBeDeleted, code: codes.FailedPrecondition, reason: "USER_CANNOT_BE_DELETED"},
+	{err: domainUser.ErrTwoFactorNotEnrolled, code: codes.FailedPrecondition, reason: "TWO_FACTOR_NOT_ENROLLED"},
+	{err: domainUser.ErrTwoFactorNotEnabled, code: codes.FailedPrecondition, reason: "TWO_FACTOR_NOT_ENABLED"},
+	{err: domainUser.ErrTwoFactorAlreadyEnabled, code: codes.FailedPrecondition, reason: "TWO_FACTOR_ALREADY_ENABLED"},
+	{err: domainUser.ErrVersionMismatch, code: codes.Aborted, reason: "VERSION_MISMATCH"},
+	{err: domainUser.ErrTooManyLoginAttempts, code: codes.ResourceExhausted, reason: "TOO_MANY_LOGIN_ATTEMPTS"},
+	{err: domainUser.ErrStorageUnavailable, code: codes.Unavailable, reason: "STORAGE_UNAVAILABLE"},
+}
+
+// toStatusError translates a use case error into a gRPC status error with
+// google.rpc.ErrorInfo and, for invalid arguments, google.rpc.BadRequest details.
+// Unknown errors become Internal without exposing their message.
+func toStatusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var policyErr *domainUser.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		return passwordPolicyStatusError(policyErr)
+	}
+	var throttledErr *domainUser.LoginThrottledError
+	if errors.As(err, &throttledErr) {
+		return loginThrottledStatusError(throttledErr)
+	}
+
+	for _, m := range errorMappings {
+		if errors.Is(err, m.err) {
+			return newStatusError(m.code, m.err.Error(), m.reason, m.field)
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+// invalidArgument builds an InvalidArgument status error for a request field
+func invalidArgument(field, message string) error {
+	return newStatusError(codes.InvalidArgument, message, "INVALID_ARGUMENT", field)
+}
+
+func newStatusError(code codes.Code, message, reason, field string) error {
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain},
+	}
+	if field != "" {
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: field, Description: message},
+			},
+		})
+	}
+
+	st := status.New(code, message)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// passwordPolicyStatusError reports every broken password rule as a
+// google.rpc.BadRequest field violation with the rule as its reason
+func passwordPolicyStatusError(err *domainUser.PasswordPolicyError) error {
+	rules := make([]string, len(err.Violations))
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(err.Violations))
+	for i, v := range err.Violations {
+		rules[i] = string(v.Rule)
+		violations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       "password",
+			Description: "password " + v.Message,
+			Reason:      string(v.Rule),
+		}
+	}
+
+	st := status.New(codes.InvalidArgument, err.Error())
+	if withDetails, detailsErr := st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason:   "WEAK_PASSWORD",
+			Domain:   errorDomain,
+			Metadata: map[string]string{"violations": strings.Join(rules, ",")},
+		},
+		&errdetails.BadRequest{FieldViolations: violations},
+	); detailsErr == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// loginThrottledStatusError reports when the caller may retry as
+// google.rpc.RetryInfo
+func loginThrottledStatusError(err *domainUser.LoginThrottledError) error {
+	st := status.New(codes.ResourceExhausted, err.Error())
+	if withDetails, detailsErr := st.WithDetails(
+		&errdetails.ErrorInfo{Reason: "TOO_MANY_LOGIN_ATTEMPTS", Domain: errorDomain},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Duration(err.RetryAfterSeconds()) * time.Second)},
+	); detailsErr == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// statusLabel returns the metrics label for a gRPC status code
+func statusLabel(code codes.Code) string {
+	switch code {
+	case codes.OK:
+		return "ok"
+	case codes.Internal:
+		return "internal_error"
+	}
+
+	name := code.String()
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToLower(b.String())
+}
@@ -0,0 +1,134 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	domainUser "github.com/memclutter/go-microservices-template/internal/domain/user"
+	userUseCase "github.com/memclutter/go-microservices-template/internal/usecase/user"
+	"github.com/memclutter/go-microservices-template/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatusError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantCode  codes.Code
+		wantField string
+	}{
+		{name: "invalid email", err: fmt.Errorf("invalid user data: %w", domainUser.ErrInvalidEmail), wantCode: codes.InvalidArgument, wantField: "email"},
+		{name: "weak password", err: fmt.Errorf("invalid user data: %w", domainUser.ErrWeakPassword), wantCode: codes.InvalidArgument, wantField: "password"},
+		{name: "incorrect password", err: domainUser.ErrIncorrectPassword, wantCode: codes.InvalidArgument, wantField: "current_password"},
+		{name: "invalid reset token", err: domainUser.ErrInvalidResetToken, wantCode: codes.InvalidArgument, wantField: "token"},
+		{name: "invalid update mask", err: fmt.Errorf("invalid user data: %w", domainUser.ErrInvalidUpdateMask), wantCode: codes.InvalidArgument, wantField: "update_mask"},
+		{name: "invalid page token", err: pagination.ErrInvalidPageToken, wantCode: codes.InvalidArgument, wantField: "page_token"},
+		{name: "too many user ids", err: userUseCase.ErrTooManyUserIDs, wantCode: codes.InvalidArgument, wantField: "user_ids"},
+		{name: "invalid two-factor code", err: domainUser.ErrInvalidTwoFactorCode, wantCode: codes.InvalidArgument, wantField: "code"},
+		{name: "invalid passkey challenge", err: domainUser.ErrInvalidPasskeyChallenge, wantCode: codes.InvalidArgument, wantField: "client_data_json"},
+		{name: "invalid passkey response", err: fmt.Errorf("%w: %w", domainUser.ErrInvalidPasskeyResponse, errors.New("bad signature")), wantCode: codes.InvalidArgument},
+		{name: "invalid api key scope", err: domainUser.ErrInvalidAPIKeyScope, wantCode: codes.InvalidArgument, wantField: "scopes"},
+		{name: "not found", err: domainUser.ErrUserNotFound, wantCode: codes.NotFound},
+		{name: "api key not found", err: domainUser.ErrAPIKeyNotFound, wantCode: codes.NotFound},
+		{name: "invalid api key", err: domainUser.ErrInvalidAPIKey, wantCode: codes.Unauthenticated},
+		{name: "passkey already registered", err: domainUser.ErrPasskeyAlreadyRegistered, wantCode: codes.AlreadyExists},
+		{name: "api key already exists", err: domainUser.ErrAPIKeyAlreadyExists, wantCode: codes.AlreadyExists},
+		{name: "already exists", err: domainUser.ErrUserAlreadyExists, wantCode: codes.AlreadyExists},
+		{name: "unauthorized", err: domainUser.ErrUnauthorized, wantCode: codes.Unauthenticated},
+		{name: "invalid login challenge", err: domainUser.ErrInvalidLoginChallenge, wantCode: codes.Unauthenticated},
+		{name: "invalid magic link", err: domainUser.ErrInvalidMagicLink, wantCode: codes.Unauthenticated},
+		{name: "permission denied", err: domainUser.ErrPermissionDenied, wantCode: codes.PermissionDenied},
+		{name: "api key scope not held", err: domainUser.ErrAPIKeyScopeNotHeld, wantCode: codes.PermissionDenied},
+		{name: "email not verified", err: domainUser.ErrEmailNotVerified, wantCode: codes.FailedPrecondition},
+		{name: "login throttled", err: &domainUser.LoginThrottledError{RetryAfter: time.Minute}, wantCode: codes.ResourceExhausted},
+		{name: "two-factor already enabled", err: domainUser.ErrTwoFactorAlreadyEnabled, wantCode: codes.FailedPrecondition},
+		{name: "cannot be deleted", err: domainUser.ErrUserCannotBeDeleted, wantCode: codes.FailedPrecondition},
+		{name: "version mismatch", err: domainUser.ErrVersionMismatch, wantCode: codes.Aborted},
+		{name: "storage unavailable", err: fmt.Errorf("failed to get user: %w", domainUser.ErrStorageUnavailable), wantCode: codes.Unavailable},
+		{name: "deadline exceeded", err: fmt.Errorf("query: %w", context.DeadlineExceeded), wantCode: codes.DeadlineExceeded},
+		{name: "unknown error", err: errors.New("boom"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := status.FromError(toStatusError(tt.err))
+			require.True(t, ok)
+			assert.Equal(t, tt.wantCode, st.Code())
+
+			var field string
+			for _, d := range st.Details() {
+				if br, ok := d.(*errdetails.BadRequest); ok {
+					field = br.GetFieldViolations()[0].GetField()
+				}
+			}
+			assert.Equal(t, tt.wantField, field)
+		})
+	}
+}
+
+func TestToStatusError_PasswordPolicyViolations(t *testing.T) {
+	err := fmt.Errorf("invalid password: %w", &domainUser.PasswordPolicyError{Violations: []domainUser.PasswordViolation{
+		{Rule: domainUser.PasswordRuleMinLength, Message: "must be at least 12 characters"},
+		{Rule: domainUser.PasswordRuleBlocklist, Message: "is too common"},
+	}})
+
+	st := status.Convert(toStatusError(err))
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	var info *errdetails.ErrorInfo
+	var badRequest *errdetails.BadRequest
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.BadRequest:
+			badRequest = d
+		}
+	}
+	require.NotNil(t, info)
+	assert.Equal(t, "WEAK_PASSWORD", info.GetReason())
+	assert.Equal(t, "MIN_LENGTH,BLOCKLIST", info.GetMetadata()["violations"])
+	require.NotNil(t, badRequest)
+	require.Len(t, badRequest.GetFieldViolations(), 2)
+	assert.Equal(t, "password", badRequest.GetFieldViolations()[0].GetField())
+	assert.Equal(t, "MIN_LENGTH", badRequest.GetFieldViolations()[0].GetReason())
+	assert.Equal(t, "password is too common", badRequest.GetFieldViolations()[1].GetDescription())
+}
+
+func TestToStatusError_LoginThrottledRetryInfo(t *testing.T) {
+	st := status.Convert(toStatusError(&domainUser.LoginThrottledError{RetryAfter: 1500 * time.Millisecond}))
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+
+	var retry *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if r, ok := d.(*errdetails.RetryInfo); ok {
+			retry = r
+		}
+	}
+	require.NotNil(t, retry)
+	assert.Equal(t, 2*time.Second, retry.GetRetryDelay().AsDuration())
+}
+
+func TestToStatusError_HidesInternalMessage(t *testing.T) {
+	st := status.Convert(toStatusError(errors.New("pq: password authentication failed")))
+	assert.Equal(t, "internal error", st.Message())
+}
+
+func TestToStatusError_PassesThroughStatus(t *testing.T) {
+	err := invalidArgument("email", "email is required")
+	assert.Equal(t, err, toStatusError(err))
+}
+
+func TestStatusLabel(t *testing.T) {
+	assert.Equal(t, "ok", statusLabel(codes.OK))
+	assert.Equal(t, "internal_error", statusLabel(codes.Internal))
+	assert.Equal(t, "invalid_argument", statusLabel(codes.InvalidArgument))
+	assert.Equal(t, "failed_precondition", statusLabel(codes.FailedPrecondition))
+}
@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// formatETag renders the version of a user as a strong entity tag, such as "3"
+func formatETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// parseETag returns the version in an entity tag. An empty tag and "*", which
+// matches any version in If-Match, return zero.
+func parseETag(etag string) (int64, error) {
+	etag = strings.TrimSpace(etag)
+	if etag == "" || etag == "*" {
+		return 0, nil
+	}
+
+	unquoted, err := strconv.Unquote(etag)
+	if err != nil {
+		return 0, invalidArgument("etag", "etag is malformed")
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, invalidArgument("etag", "etag is malformed")
+	}
+	return version, nil
+}
+
+// requestETag returns etag, or the If-Match header the gateway forwarded
+// when the request field is empty
+func requestETag(ctx context.Context, etag string) string {
+	if etag != "" {
+		return etag
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("if-match"); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// sendValidators returns the entity tag and the modification time of a user
+// as etag and last-modified response metadata, which the gateway turns into
+// ETag and Last-Modified headers
+func sendValidators(ctx context.Context, version int64, updatedAt time.Time) {
+	// Only fails outside of a gRPC call, where there is nobody to tell
+	_ = grpc.SetHeader(ctx, metadata.Pairs(
+		"etag", formatETag(version),
+		"last-modified", updatedAt.UTC().Format(http.TimeFormat),
+	))
+}
@@ -0,0 +1,37 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseETag(t *testing.T) {
+	version, err := parseETag(formatETag(42))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), version)
+
+	for _, etag := range []string{"", "*"} {
+		version, err := parseETag(etag)
+		require.NoError(t, err)
+		assert.Zero(t, version)
+	}
+
+	for _, etag := range []string{"42", `W/"42"`, `"abc"`, `"0"`} {
+		_, err := parseETag(etag)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), etag)
+	}
+}
+
+func TestRequestETag(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("if-match", `"3"`))
+
+	assert.Equal(t, `"5"`, requestETag(ctx, `"5"`))
+	assert.Equal(t, `"3"`, requestETag(ctx, ""))
+	assert.Equal(t, "", requestETag(context.Background(), ""))
+}
@@ -0,0 +1,185 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/memclutter/go-microservices-template/api/gen/common"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GatewayHeaderMatcher decides which HTTP headers reach the gRPC server as metadata.
+// Authorization is forwarded unprefixed by the gateway itself, so it is not
+// duplicated as grpcgateway-authorization. X-Api-Key and If-Match are
+// forwarded unprefixed as x-api-key and if-match, where the server expects them.
+func GatewayHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, "Authorization") {
+		return "", false
+	}
+	if strings.EqualFold(key, "X-Api-Key") {
+		return "x-api-key", true
+	}
+	if strings.EqualFold(key, "If-Match") {
+		return "if-match", true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// GatewayOutgoingHeaderMatcher decides which response metadata becomes HTTP
+// headers. The etag and last-modified metadata of a user become the ETag and
+// Last-Modified headers, other metadata keeps the Grpc-Metadata- prefix.
+func GatewayOutgoingHeaderMatcher(key string) (string, bool) {
+	switch key {
+	case "etag":
+		return "ETag", true
+	case "last-modified":
+		return "Last-Modified", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// GatewayConditionalGET answers GET requests with 304 Not Modified and no body
+// when the ETag or Last-Modified header of the response shows that the copy
+// the client names in If-None-Match or If-Modified-Since is still current
+func GatewayConditionalGET(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional := r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != ""
+		if !conditional || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(&conditionalResponseWriter{ResponseWriter: w, request: r}, r)
+	})
+}
+
+// conditionalResponseWriter turns a 200 OK response into 304 Not Modified
+// once its headers show that the client's copy is current
+type conditionalResponseWriter struct {
+	http.ResponseWriter
+	request     *http.Request
+	wroteHeader bool
+	notModified bool
+}
+
+func (w *conditionalResponseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if statusCode == http.StatusOK && notModified(w.request, w.Header()) {
+		w.notModified = true
+		w.Header().Del("Content-Type")
+		w.Header().Del("Content-Length")
+		statusCode = http.StatusNotModified
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *conditionalResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.notModified {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when there is no
+// If-None-Match, against the ETag and Last-Modified headers of a response
+func notModified(r *http.Request, header http.Header) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		etag := header.Get("ETag")
+		if etag == "" {
+			return false
+		}
+		// If-None-Match uses weak comparison
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
+
+// GatewayErrorHandler renders gRPC errors as common.Error JSON bodies.
+// ErrorInfo reason and metadata and BadRequest fields are flattened into details,
+// and RetryInfo becomes a Retry-After header. A version mismatch of a request
+// with an If-Match header is 412 Precondition Failed, as HTTP requires.
+func GatewayErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	st := status.Convert(err)
+
+	body := &common.Error{
+		Code:    code.Code_name[int32(st.Code())],
+		Message: st.Message(),
+		Details: map[string]string{},
+	}
+
+	var fields []string
+	var retryAfter string
+	seen := map[string]bool{}
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			body.Details["reason"] = d.GetReason()
+			for k, v := range d.GetMetadata() {
+				body.Details[k] = v
+			}
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				if !seen[v.GetField()] {
+					seen[v.GetField()] = true
+					fields = append(fields, v.GetField())
+				}
+			}
+		case *errdetails.RetryInfo:
+			retryAfter = strconv.FormatInt(int64(d.GetRetryDelay().AsDuration().Seconds()), 10)
+		}
+	}
+	if len(fields) > 0 {
+		sort.Strings(fields)
+		body.Details["field"] = strings.Join(fields, ",")
+	}
+
+	buf, merr := marshaler.Marshal(body)
+	if merr != nil {
+		runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", marshaler.ContentType(body))
+	if retryAfter != "" {
+		w.Header().Set("Retry-After", retryAfter)
+	}
+	w.WriteHeader(gatewayHTTPStatus(st.Code(), body.Details["reason"], r))
+	_, _ = w.Write(buf)
+}
+
+// gatewayHTTPStatus returns the HTTP status of an error with the given code
+// and reason in response to r
+func gatewayHTTPStatus(c codes.Code, reason string, r *http.Request) int {
+	if reason == "VERSION_MISMATCH" && r.Header.Get("If-Match") != "" {
+		return http.StatusPreconditionFailed
+	}
+	return runtime.HTTPStatusFromCode(c)
+}
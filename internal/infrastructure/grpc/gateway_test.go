@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestGatewayHeaderMatcher_ForwardsAuthorization(t *testing.T) {
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(GatewayHeaderMatcher))
+	req := httptest.NewRequest("GET", "/v1/users/user-1", nil)
+	req.Header.Set("Authorization", "Bearer token")
+
+	ctx, err := runtime.AnnotateContext(context.Background(), mux, req, "/user.UserService/GetUser")
+	require.NoError(t, err)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, []string{"Bearer token"}, md.Get("authorization"))
+	assert.Empty(t, md.Get("grpcgateway-authorization"))
+}
+
+func TestGatewayHeaderMatcher_ForwardsAPIKey(t *testing.T) {
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(GatewayHeaderMatcher))
+	req := httptest.NewRequest("GET", "/v1/users/user-1", nil)
+	req.Header.Set("X-Api-Key", "mst_key")
+
+	ctx, err := runtime.AnnotateContext(context.Background(), mux, req, "/user.UserService/GetUser")
+	require.NoError(t, err)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, []string{"mst_key"}, md.Get("x-api-key"))
+	assert.Empty(t, md.Get("grpcgateway-x-api-key"))
+}
+
+func TestGatewayHeaderMatcher_ForwardsIfMatch(t *testing.T) {
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(GatewayHeaderMatcher))
+	req := httptest.NewRequest("PATCH", "/v1/users/user-1", nil)
+	req.Header.Set("If-Match", `"3"`)
+
+	ctx, err := runtime.AnnotateContext(context.Background(), mux, req, "/user.UserService/UpdateUser")
+	require.NoError(t, err)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, []string{`"3"`}, md.Get("if-match"))
+}
+
+func TestGatewayOutgoingHeaderMatcher(t *testing.T) {
+	header, ok := GatewayOutgoingHeaderMatcher("etag")
+	assert.True(t, ok)
+	assert.Equal(t, "ETag", header)
+
+	header, ok = GatewayOutgoingHeaderMatcher("last-modified")
+	assert.True(t, ok)
+	assert.Equal(t, "Last-Modified", header)
+
+	header, ok = GatewayOutgoingHeaderMatcher("x-request-id")
+	assert.True(t, ok)
+	assert.Equal(t, "Grpc-Metadata-x-request-id", header)
+}
+
+func TestGatewayHTTPStatus_VersionMismatch(t *testing.T) {
+	req := httptest.NewRequest("PATCH", "/v1/users/user-1", nil)
+	assert.Equal(t, http.StatusConflict, gatewayHTTPStatus(codes.Aborted, "VERSION_MISMATCH", req))
+
+	req.Header.Set("If-Match", `"3"`)
+	assert.Equal(t, http.StatusPreconditionFailed, gatewayHTTPStatus(codes.Aborted, "VERSION_MISMATCH", req))
+	assert.Equal(t, http.StatusNotFound, gatewayHTTPStatus(codes.NotFound, "USER_NOT_FOUND", req))
+}
+
+func TestGatewayConditionalGET(t *testing.T) {
+	lastModified := time.Date(2025, 10, 30, 19, 0, 0, 0, time.UTC)
+	handler := GatewayConditionalGET(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"3"`)
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		_, _ = w.Write([]byte(`{"user":{}}`))
+	}))
+
+	tests := []struct {
+		name       string
+		method     string
+		header     map[string]string
+		wantStatus int
+	}{
+		{name: "unconditional", method: "GET", wantStatus: http.StatusOK},
+		{name: "matching etag", method: "GET", header: map[string]string{"If-None-Match": `"3"`}, wantStatus: http.StatusNotModified},
+		{name: "matching weak etag in a list", method: "GET", header: map[string]string{"If-None-Match": `"2", W/"3"`}, wantStatus: http.StatusNotModified},
+		{name: "any etag", method: "GET", header: map[string]string{"If-None-Match": "*"}, wantStatus: http.StatusNotModified},
+		{name: "stale etag", method: "GET", header: map[string]string{"If-None-Match": `"2"`}, wantStatus: http.StatusOK},
+		{
+			name:       "stale etag takes precedence over date",
+			method:     "GET",
+			header:     map[string]string{"If-None-Match": `"2"`, "If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			wantStatus: http.StatusOK,
+		},
+		{name: "not modified since", method: "GET", header: map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)}, wantStatus: http.StatusNotModified},
+		{name: "modified since", method: "GET", header: map[string]string{"If-Modified-Since": lastModified.Add(-time.Second).Format(http.TimeFormat)}, wantStatus: http.StatusOK},
+		{name: "malformed date", method: "GET", header: map[string]string{"If-Modified-Since": "yesterday"}, wantStatus: http.StatusOK},
+		{name: "not a GET", method: "PATCH", header: map[string]string{"If-None-Match": `"3"`}, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/v1/users/user-1", nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+			if tt.wantStatus == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+				assert.Empty(t, w.Header().Get("Content-Type"))
+			} else {
+				assert.Equal(t, `{"user":{}}`, w.Body.String())
+			}
+		})
+	}
+}
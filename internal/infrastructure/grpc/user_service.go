@@ -2,11 +2,15 @@ package grpc
 
 import (
 	"context"
+	"encoding/base64"
+	"strings"
 	"time"
 
 	"github.com/memclutter/go-microservices-template/api/gen/common"
 	"github.com/memclutter/go-microservices-template/api/gen/user"
+	domainUser "github.com/memclutter/go-microservices-template/internal/domain/user"
 	userUseCase "github.com/memclutter/go-microservices-template/internal/usecase/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
 	"github.com/memclutter/go-microservices-template/pkg/logger"
 	"github.com/memclutter/go-microservices-template/pkg/metrics"
 	"google.golang.org/grpc/codes"
@@ -16,27 +20,157 @@ import (
 // UserServiceServer implements the gRPC UserService
 type UserServiceServer struct {
 	user.UnimplementedUserServiceServer
-	createUserUC *userUseCase.CreateUserUseCase
-	getUserUC    *userUseCase.GetUserUseCase
-	logger       *logger.Logger
-	metrics      *metrics.Metrics
+	createUserUC     *userUseCase.CreateUserUseCase
+	getUserUC        *userUseCase.GetUserUseCase
+	updateUserUC     *userUseCase.UpdateUserUseCase
+	deleteUserUC     *userUseCase.DeleteUserUseCase
+	listUsersUC      *userUseCase.ListUsersUseCase
+	batchGetUC       *userUseCase.BatchGetUsersUseCase
+	loginUC          *userUseCase.LoginUseCase
+	refreshUC        *userUseCase.RefreshSessionUseCase
+	sessionsUC       *userUseCase.ListSessionsUseCase
+	revokeUC         *userUseCase.RevokeSessionUseCase
+	revokeAllUC      *userUseCase.RevokeAllSessionsUseCase
+	rolesUC          *userUseCase.UpdateUserRolesUseCase
+	passwordUC       *userUseCase.ChangePasswordUseCase
+	resetUC          *userUseCase.RequestPasswordResetUseCase
+	confirmUC        *userUseCase.ConfirmPasswordResetUseCase
+	verifyUC         *userUseCase.VerifyEmailUseCase
+	emailUC          *userUseCase.ChangeEmailUseCase
+	unlockUC         *userUseCase.UnlockUserUseCase
+	enrollUC         *userUseCase.StartTOTPEnrollmentUseCase
+	enableUC         *userUseCase.ConfirmTOTPEnrollmentUseCase
+	disableUC        *userUseCase.DisableTOTPUseCase
+	challengeUC      *userUseCase.VerifyLoginChallengeUseCase
+	magicLinkUC      *userUseCase.RequestMagicLinkUseCase
+	consumeUC        *userUseCase.ConsumeMagicLinkUseCase
+	beginRegisterUC  *userUseCase.BeginPasskeyRegistrationUseCase
+	finishRegisterUC *userUseCase.FinishPasskeyRegistrationUseCase
+	beginPasskeyUC   *userUseCase.BeginPasskeyLoginUseCase
+	finishPasskeyUC  *userUseCase.FinishPasskeyLoginUseCase
+	createKeyUC      *userUseCase.CreateAPIKeyUseCase
+	listKeysUC       *userUseCase.ListAPIKeysUseCase
+	revokeKeyUC      *userUseCase.RevokeAPIKeyUseCase
+	securityUC       *userUseCase.GetSecuritySettingsUseCase
+	authorizeUC      *userUseCase.AuthorizeUseCase
+	proxies          TrustedProxies
+	logger           *logger.Logger
+	metrics          *metrics.Metrics
 }
 
 // NewUserServiceServer creates a new gRPC user service server
 func NewUserServiceServer(
 	createUserUC *userUseCase.CreateUserUseCase,
 	getUserUC *userUseCase.GetUserUseCase,
+	updateUserUC *userUseCase.UpdateUserUseCase,
+	deleteUserUC *userUseCase.DeleteUserUseCase,
+	listUsersUC *userUseCase.ListUsersUseCase,
+	batchGetUC *userUseCase.BatchGetUsersUseCase,
+	loginUC *userUseCase.LoginUseCase,
+	refreshUC *userUseCase.RefreshSessionUseCase,
+	sessionsUC *userUseCase.ListSessionsUseCase,
+	revokeUC *userUseCase.RevokeSessionUseCase,
+	revokeAllUC *userUseCase.RevokeAllSessionsUseCase,
+	rolesUC *userUseCase.UpdateUserRolesUseCase,
+	passwordUC *userUseCase.ChangePasswordUseCase,
+	resetUC *userUseCase.RequestPasswordResetUseCase,
+	confirmUC *userUseCase.ConfirmPasswordResetUseCase,
+	verifyUC *userUseCase.VerifyEmailUseCase,
+	emailUC *userUseCase.ChangeEmailUseCase,
+	unlockUC *userUseCase.UnlockUserUseCase,
+	enrollUC *userUseCase.StartTOTPEnrollmentUseCase,
+	enableUC *userUseCase.ConfirmTOTPEnrollmentUseCase,
+	disableUC *userUseCase.DisableTOTPUseCase,
+	challengeUC *userUseCase.VerifyLoginChallengeUseCase,
+	magicLinkUC *userUseCase.RequestMagicLinkUseCase,
+	consumeUC *userUseCase.ConsumeMagicLinkUseCase,
+	beginRegisterUC *userUseCase.BeginPasskeyRegistrationUseCase,
+	finishRegisterUC *userUseCase.FinishPasskeyRegistrationUseCase,
+	beginPasskeyUC *userUseCase.BeginPasskeyLoginUseCase,
+	finishPasskeyUC *userUseCase.FinishPasskeyLoginUseCase,
+	createKeyUC *userUseCase.CreateAPIKeyUseCase,
+	listKeysUC *userUseCase.ListAPIKeysUseCase,
+	revokeKeyUC *userUseCase.RevokeAPIKeyUseCase,
+	securityUC *userUseCase.GetSecuritySettingsUseCase,
+	authorizeUC *userUseCase.AuthorizeUseCase,
+	proxies TrustedProxies,
 	log *logger.Logger,
 	metrics *metrics.Metrics,
 ) *UserServiceServer {
 	return &UserServiceServer{
-		createUserUC: createUserUC,
-		getUserUC:    getUserUC,
-		logger:       log,
-		metrics:      metrics,
+		createUserUC:     createUserUC,
+		getUserUC:        getUserUC,
+		updateUserUC:     updateUserUC,
+		deleteUserUC:     deleteUserUC,
+		listUsersUC:      listUsersUC,
+		batchGetUC:       batchGetUC,
+		loginUC:          loginUC,
+		refreshUC:        refreshUC,
+		sessionsUC:       sessionsUC,
+		revokeUC:         revokeUC,
+		revokeAllUC:      revokeAllUC,
+		rolesUC:          rolesUC,
+		passwordUC:       passwordUC,
+		resetUC:          resetUC,
+		confirmUC:        confirmUC,
+		verifyUC:         verifyUC,
+		emailUC:          emailUC,
+		unlockUC:         unlockUC,
+		enrollUC:         enrollUC,
+		enableUC:         enableUC,
+		disableUC:        disableUC,
+		challengeUC:      challengeUC,
+		magicLinkUC:      magicLinkUC,
+		consumeUC:        consumeUC,
+		beginRegisterUC:  beginRegisterUC,
+		finishRegisterUC: finishRegisterUC,
+		beginPasskeyUC:   beginPasskeyUC,
+		finishPasskeyUC:  finishPasskeyUC,
+		createKeyUC:      createKeyUC,
+		listKeysUC:       listKeysUC,
+		revokeKeyUC:      revokeKeyUC,
+		securityUC:       securityUC,
+		authorizeUC:      authorizeUC,
+		proxies:          proxies,
+		logger:           log,
+		metrics:          metrics,
 	}
 }
 
+// fail records a failed request and translates err into a gRPC status error
+func (s *UserServiceServer) fail(method string, err error) error {
+	stErr := toStatusError(err)
+	code := status.Code(stErr)
+
+	if code == codes.Internal || code == codes.Unavailable {
+		s.logger.WithError(err).WithField("method", method).Error("gRPC request failed")
+	}
+	s.metrics.GRPCRequestsTotal.WithLabelValues(method, statusLabel(code)).Inc()
+
+	return stErr
+}
+
+// authorize checks that the authenticated caller may perform action on the
+// account targetUserID according to the caller's current roles
+func (s *UserServiceServer) authorize(ctx context.Context, action domainUser.Action, targetUserID string) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return domainUser.ErrUnauthorized
+	}
+
+	return s.authorizeUC.Execute(ctx, userUseCase.AuthorizeInput{
+		ActorID:      principal.UserID,
+		Action:       action,
+		TargetUserID: targetUserID,
+	})
+}
+
+// mayReadPendingEmail reports whether the caller may see pending email
+// addresses of any account
+func (s *UserServiceServer) mayReadPendingEmail(ctx context.Context) bool {
+	return s.authorize(ctx, domainUser.ActionReadPendingEmail, "") == nil
+}
+
 // CreateUser creates a new user
 func (s *UserServiceServer) CreateUser(ctx context.Context, req *user.CreateUserRequest) (*user.CreateUserResponse, error) {
 	start := time.Now()
@@ -52,16 +186,13 @@ func (s *UserServiceServer) CreateUser(ctx context.Context, req *user.CreateUser
 
 	// Validate input
 	if req.Email == "" {
-		s.metrics.GRPCRequestsTotal.WithLabelValues("CreateUser", "invalid_argument").Inc()
-		return nil, status.Error(codes.InvalidArgument, "email is required")
+		return nil, s.fail("CreateUser", invalidArgument("email", "email is required"))
 	}
 	if req.Name == "" {
-		s.metrics.GRPCRequestsTotal.WithLabelValues("CreateUser", "invalid_argument").Inc()
-		return nil, status.Error(codes.InvalidArgument, "name is required")
+		return nil, s.fail("CreateUser", invalidArgument("name", "name is required"))
 	}
 	if req.Password == "" {
-		s.metrics.GRPCRequestsTotal.WithLabelValues("CreateUser", "invalid_argument").Inc()
-		return nil, status.Error(codes.InvalidArgument, "password is required")
+		return nil, s.fail("CreateUser", invalidArgument("password", "password is required"))
 	}
 
 	// Execute use case
@@ -73,9 +204,7 @@ func (s *UserServiceServer) CreateUser(ctx context.Context, req *user.CreateUser
 
 	output, err := s.createUserUC.Execute(ctx, input)
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to create user")
-		s.metrics.GRPCRequestsTotal.WithLabelValues("CreateUser", "internal_error").Inc()
-		return nil, status.Error(codes.Internal, "failed to create user")
+		return nil, s.fail("CreateUser", err)
 	}
 
 	s.metrics.GRPCRequestsTotal.WithLabelValues("CreateUser", "ok").Inc()
@@ -83,14 +212,17 @@ func (s *UserServiceServer) CreateUser(ctx context.Context, req *user.CreateUser
 	// Build response
 	return &user.CreateUserResponse{
 		User: &user.User{
-			Id:    output.UserID,
-			Email: output.Email,
-			Name:  output.Name,
+			Id:            output.UserID,
+			Email:         output.Email,
+			Name:          output.Name,
+			Roles:         output.Roles,
+			EmailVerified: output.EmailVerified,
+			Etag:          formatETag(output.Version),
 			CreatedAt: &common.Timestamp{
-				Seconds: time.Now().Unix(),
+				Seconds: output.CreatedAt.Unix(),
 			},
 			UpdatedAt: &common.Timestamp{
-				Seconds: time.Now().Unix(),
+				Seconds: output.UpdatedAt.Unix(),
 			},
 		},
 	}, nil
@@ -108,35 +240,43 @@ func (s *UserServiceServer) GetUser(ctx context.Context, req *user.GetUserReques
 
 	// Validate input
 	if req.UserId == "" {
-		s.metrics.GRPCRequestsTotal.WithLabelValues("GetUser", "invalid_argument").Inc()
-		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+		return nil, s.fail("GetUser", invalidArgument("user_id", "user_id is required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionReadUser, req.UserId); err != nil {
+		return nil, s.fail("GetUser", err)
 	}
 
 	// Execute use case
 	input := userUseCase.GetUserInput{
-		UserID: req.UserId,
+		UserID:              req.UserId,
+		IncludePendingEmail: s.mayReadPendingEmail(ctx),
 	}
 
 	output, err := s.getUserUC.Execute(ctx, input)
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to get user")
-		s.metrics.GRPCRequestsTotal.WithLabelValues("GetUser", "not_found").Inc()
-		return nil, status.Error(codes.NotFound, "user not found")
+		return nil, s.fail("GetUser", err)
 	}
 
 	s.metrics.GRPCRequestsTotal.WithLabelValues("GetUser", "ok").Inc()
+	sendValidators(ctx, output.Version, output.UpdatedAt)
 
 	// Build response
 	return &user.GetUserResponse{
 		User: &user.User{
-			Id:    output.ID,
-			Email: output.Email,
-			Name:  output.Name,
+			Id:            output.ID,
+			Email:         output.Email,
+			Name:          output.Name,
+			Roles:         output.Roles,
+			EmailVerified: output.EmailVerified,
+			PendingEmail:  output.PendingEmail,
+			Etag:          formatETag(output.Version),
 			CreatedAt: &common.Timestamp{
-				Seconds: time.Now().Unix(),
+				Seconds: output.CreatedAt.Unix(),
 			},
 			UpdatedAt: &common.Timestamp{
-				Seconds: time.Now().Unix(),
+				Seconds: output.UpdatedAt.Unix(),
 			},
 		},
 	}, nil
@@ -144,21 +284,1495 @@ func (s *UserServiceServer) GetUser(ctx context.Context, req *user.GetUserReques
 
 // UpdateUser updates an existing user
 func (s *UserServiceServer) UpdateUser(ctx context.Context, req *user.UpdateUserRequest) (*user.UpdateUserResponse, error) {
-	// TODO: Implement UpdateUser use case
-	s.metrics.GRPCRequestsTotal.WithLabelValues("UpdateUser", "unimplemented").Inc()
-	return nil, status.Error(codes.Unimplemented, "UpdateUser not implemented yet")
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("UpdateUser").Observe(duration)
+	}()
+
+	s.logger.WithFields(map[string]any{
+		"user_id":     req.UserId,
+		"name":        req.Name,
+		"update_mask": req.UpdateMask.GetPaths(),
+	}).Info("UpdateUser gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("UpdateUser", invalidArgument("user_id", "user_id is required"))
+	}
+	version, err := parseETag(requestETag(ctx, req.Etag))
+	if err != nil {
+		return nil, s.fail("UpdateUser", err)
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionUpdateUser, req.UserId); err != nil {
+		return nil, s.fail("UpdateUser", err)
+	}
+
+	// Execute use case
+	input := userUseCase.UpdateUserInput{
+		UserID:     req.UserId,
+		Name:       req.Name,
+		UpdateMask: req.UpdateMask.GetPaths(),
+		Version:    version,
+	}
+
+	output, err := s.updateUserUC.Execute(ctx, input)
+	if err != nil {
+		return nil, s.fail("UpdateUser", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("UpdateUser", "ok").Inc()
+	sendValidators(ctx, output.Version, output.UpdatedAt)
+
+	// Build response
+	return &user.UpdateUserResponse{
+		User: &user.User{
+			Id:            output.ID,
+			Email:         output.Email,
+			Name:          output.Name,
+			Roles:         output.Roles,
+			EmailVerified: output.EmailVerified,
+			PendingEmail:  output.PendingEmail,
+			Etag:          formatETag(output.Version),
+			CreatedAt: &common.Timestamp{
+				Seconds: output.CreatedAt.Unix(),
+			},
+			UpdatedAt: &common.Timestamp{
+				Seconds: output.UpdatedAt.Unix(),
+			},
+		},
+	}, nil
 }
 
 // DeleteUser deletes a user
 func (s *UserServiceServer) DeleteUser(ctx context.Context, req *user.DeleteUserRequest) (*user.DeleteUserResponse, error) {
-	// TODO: Implement DeleteUser use case
-	s.metrics.GRPCRequestsTotal.WithLabelValues("DeleteUser", "unimplemented").Inc()
-	return nil, status.Error(codes.Unimplemented, "DeleteUser not implemented yet")
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("DeleteUser").Observe(duration)
+	}()
+
+	s.logger.WithField("user_id", req.UserId).Info("DeleteUser gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("DeleteUser", invalidArgument("user_id", "user_id is required"))
+	}
+	version, err := parseETag(requestETag(ctx, req.Etag))
+	if err != nil {
+		return nil, s.fail("DeleteUser", err)
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionDeleteUser, req.UserId); err != nil {
+		return nil, s.fail("DeleteUser", err)
+	}
+
+	// Execute use case
+	input := userUseCase.DeleteUserInput{
+		UserID:  req.UserId,
+		Version: version,
+	}
+
+	if err := s.deleteUserUC.Execute(ctx, input); err != nil {
+		return nil, s.fail("DeleteUser", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("DeleteUser", "ok").Inc()
+
+	return &user.DeleteUserResponse{}, nil
 }
 
 // ListUsers retrieves a list of users
 func (s *UserServiceServer) ListUsers(ctx context.Context, req *user.ListUsersRequest) (*user.ListUsersResponse, error) {
-	// TODO: Implement ListUsers use case
-	s.metrics.GRPCRequestsTotal.WithLabelValues("ListUsers", "unimplemented").Inc()
-	return nil, status.Error(codes.Unimplemented, "ListUsers not implemented yet")
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("ListUsers").Observe(duration)
+	}()
+
+	pagination := req.GetPagination()
+	s.logger.WithFields(map[string]any{
+		"limit":  pagination.GetLimit(),
+		"offset": pagination.GetOffset(),
+	}).Info("ListUsers gRPC request")
+
+	// Validate input
+	if pagination.GetLimit() < 0 {
+		return nil, s.fail("ListUsers", invalidArgument("pagination.limit", "limit must not be negative"))
+	}
+	if pagination.GetOffset() < 0 {
+		return nil, s.fail("ListUsers", invalidArgument("pagination.offset", "offset must not be negative"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionListUsers, ""); err != nil {
+		return nil, s.fail("ListUsers", err)
+	}
+
+	// Execute use case
+	input := userUseCase.ListUsersInput{
+		Limit:               pagination.GetLimit(),
+		Offset:              pagination.GetOffset(),
+		PageToken:           req.PageToken,
+		IncludePendingEmail: s.mayReadPendingEmail(ctx),
+	}
+
+	output, err := s.listUsersUC.Execute(ctx, input)
+	if err != nil {
+		return nil, s.fail("ListUsers", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("ListUsers", "ok").Inc()
+
+	// Build response
+	users := make([]*user.User, len(output.Users))
+	for i, u := range output.Users {
+		users[i] = &user.User{
+			Id:            u.ID,
+			Email:         u.Email,
+			Name:          u.Name,
+			Roles:         u.Roles,
+			EmailVerified: u.EmailVerified,
+			PendingEmail:  u.PendingEmail,
+			Etag:          formatETag(u.Version),
+			CreatedAt: &common.Timestamp{
+				Seconds: u.CreatedAt.Unix(),
+			},
+			UpdatedAt: &common.Timestamp{
+				Seconds: u.UpdatedAt.Unix(),
+			},
+		}
+	}
+
+	return &user.ListUsersResponse{
+		Users:         users,
+		Total:         int32(output.Total),
+		NextPageToken: output.NextPageToken,
+	}, nil
+}
+
+// BatchGetUsers retrieves several users by ID with a single query
+func (s *UserServiceServer) BatchGetUsers(ctx context.Context, req *user.BatchGetUsersRequest) (*user.BatchGetUsersResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("BatchGetUsers").Observe(duration)
+	}()
+
+	s.logger.WithField("count", len(req.UserIds)).Info("BatchGetUsers gRPC request")
+
+	// Validate input
+	if len(req.UserIds) == 0 {
+		return nil, s.fail("BatchGetUsers", invalidArgument("user_ids", "user_ids is required"))
+	}
+	for _, id := range req.UserIds {
+		if id == "" {
+			return nil, s.fail("BatchGetUsers", invalidArgument("user_ids", "user_ids must not contain empty IDs"))
+		}
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionListUsers, ""); err != nil {
+		return nil, s.fail("BatchGetUsers", err)
+	}
+
+	// Execute use case
+	output, err := s.batchGetUC.Execute(ctx, userUseCase.BatchGetUsersInput{
+		UserIDs:             req.UserIds,
+		IncludePendingEmail: s.mayReadPendingEmail(ctx),
+	})
+	if err != nil {
+		return nil, s.fail("BatchGetUsers", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("BatchGetUsers", "ok").Inc()
+
+	// Build response
+	users := make([]*user.User, len(output.Users))
+	for i, u := range output.Users {
+		users[i] = &user.User{
+			Id:            u.ID,
+			Email:         u.Email,
+			Name:          u.Name,
+			Roles:         u.Roles,
+			EmailVerified: u.EmailVerified,
+			PendingEmail:  u.PendingEmail,
+			Etag:          formatETag(u.Version),
+			CreatedAt: &common.Timestamp{
+				Seconds: u.CreatedAt.Unix(),
+			},
+			UpdatedAt: &common.Timestamp{
+				Seconds: u.UpdatedAt.Unix(),
+			},
+		}
+	}
+
+	return &user.BatchGetUsersResponse{
+		Users:          users,
+		MissingUserIds: output.MissingIDs,
+	}, nil
+}
+
+// Login authenticates a user and issues an access token
+func (s *UserServiceServer) Login(ctx context.Context, req *user.LoginRequest) (*user.LoginResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("Login").Observe(duration)
+	}()
+
+	s.logger.WithField("email", req.Email).Info("Login gRPC request")
+
+	// Validate input
+	if req.Email == "" {
+		return nil, s.fail("Login", invalidArgument("email", "email is required"))
+	}
+	if req.Password == "" {
+		return nil, s.fail("Login", invalidArgument("password", "password is required"))
+	}
+
+	// Execute use case
+	userAgent, ipAddress := clientInfo(ctx, s.proxies)
+	input := userUseCase.LoginInput{
+		Email:     req.Email,
+		Password:  req.Password,
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+	}
+
+	output, err := s.loginUC.Execute(ctx, input)
+	if err != nil {
+		return nil, s.fail("Login", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("Login", "ok").Inc()
+
+	// Build response
+	if output.TwoFactorRequired {
+		return &user.LoginResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    output.ChallengeToken,
+			ChallengeExpiresAt: &common.Timestamp{
+				Seconds: output.ChallengeExpiresAt.Unix(),
+			},
+		}, nil
+	}
+	return &user.LoginResponse{
+		AccessToken: output.AccessToken,
+		TokenType:   output.TokenType,
+		ExpiresAt: &common.Timestamp{
+			Seconds: output.ExpiresAt.Unix(),
+		},
+		RefreshToken: output.RefreshToken,
+		RefreshTokenExpiresAt: &common.Timestamp{
+			Seconds: output.RefreshTokenExpiresAt.Unix(),
+		},
+		SessionId: output.SessionID,
+	}, nil
+}
+
+// RefreshToken exchanges a refresh token for a new token pair
+func (s *UserServiceServer) RefreshToken(ctx context.Context, req *user.RefreshTokenRequest) (*user.RefreshTokenResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("RefreshToken").Observe(duration)
+	}()
+
+	s.logger.Info("RefreshToken gRPC request")
+
+	// Validate input
+	if req.RefreshToken == "" {
+		return nil, s.fail("RefreshToken", invalidArgument("refresh_token", "refresh_token is required"))
+	}
+
+	// Execute use case
+	userAgent, ipAddress := clientInfo(ctx, s.proxies)
+	input := userUseCase.RefreshSessionInput{
+		RefreshToken: req.RefreshToken,
+		UserAgent:    userAgent,
+		IPAddress:    ipAddress,
+	}
+
+	output, err := s.refreshUC.Execute(ctx, input)
+	if err != nil {
+		return nil, s.fail("RefreshToken", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("RefreshToken", "ok").Inc()
+
+	// Build response
+	return &user.RefreshTokenResponse{
+		AccessToken: output.AccessToken,
+		TokenType:   output.TokenType,
+		ExpiresAt: &common.Timestamp{
+			Seconds: output.ExpiresAt.Unix(),
+		},
+		RefreshToken: output.RefreshToken,
+		RefreshTokenExpiresAt: &common.Timestamp{
+			Seconds: output.RefreshTokenExpiresAt.Unix(),
+		},
+		SessionId: output.SessionID,
+	}, nil
+}
+
+// ListSessions retrieves the active sessions of a user
+func (s *UserServiceServer) ListSessions(ctx context.Context, req *user.ListSessionsRequest) (*user.ListSessionsResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("ListSessions").Observe(duration)
+	}()
+
+	s.logger.WithField("user_id", req.UserId).Info("ListSessions gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("ListSessions", invalidArgument("user_id", "user_id is required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionManageSessions, req.UserId); err != nil {
+		return nil, s.fail("ListSessions", err)
+	}
+
+	// Execute use case
+	output, err := s.sessionsUC.Execute(ctx, userUseCase.ListSessionsInput{UserID: req.UserId})
+	if err != nil {
+		return nil, s.fail("ListSessions", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("ListSessions", "ok").Inc()
+
+	// Build response
+	sessions := make([]*user.Session, len(output))
+	for i, session := range output {
+		sessions[i] = &user.Session{
+			Id:         session.ID,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IPAddress,
+			CreatedAt:  &common.Timestamp{Seconds: session.CreatedAt.Unix()},
+			LastUsedAt: &common.Timestamp{Seconds: session.LastUsedAt.Unix()},
+			ExpiresAt:  &common.Timestamp{Seconds: session.ExpiresAt.Unix()},
+		}
+	}
+
+	return &user.ListSessionsResponse{Sessions: sessions}, nil
+}
+
+// RevokeSession signs out a single session of a user
+func (s *UserServiceServer) RevokeSession(ctx context.Context, req *user.RevokeSessionRequest) (*user.RevokeSessionResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("RevokeSession").Observe(duration)
+	}()
+
+	s.logger.WithFields(map[string]any{
+		"user_id":    req.UserId,
+		"session_id": req.SessionId,
+	}).Info("RevokeSession gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("RevokeSession", invalidArgument("user_id", "user_id is required"))
+	}
+	if req.SessionId == "" {
+		return nil, s.fail("RevokeSession", invalidArgument("session_id", "session_id is required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionManageSessions, req.UserId); err != nil {
+		return nil, s.fail("RevokeSession", err)
+	}
+
+	// Execute use case
+	input := userUseCase.RevokeSessionInput{
+		UserID:    req.UserId,
+		SessionID: req.SessionId,
+	}
+
+	if err := s.revokeUC.Execute(ctx, input); err != nil {
+		return nil, s.fail("RevokeSession", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("RevokeSession", "ok").Inc()
+
+	return &user.RevokeSessionResponse{}, nil
+}
+
+// RevokeAllSessions signs out every session of a user
+func (s *UserServiceServer) RevokeAllSessions(ctx context.Context, req *user.RevokeAllSessionsRequest) (*user.RevokeAllSessionsResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("RevokeAllSessions").Observe(duration)
+	}()
+
+	s.logger.WithField("user_id", req.UserId).Info("RevokeAllSessions gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("RevokeAllSessions", invalidArgument("user_id", "user_id is required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionManageSessions, req.UserId); err != nil {
+		return nil, s.fail("RevokeAllSessions", err)
+	}
+
+	// Execute use case
+	revoked, err := s.revokeAllUC.Execute(ctx, userUseCase.RevokeAllSessionsInput{UserID: req.UserId})
+	if err != nil {
+		return nil, s.fail("RevokeAllSessions", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("RevokeAllSessions", "ok").Inc()
+
+	return &user.RevokeAllSessionsResponse{RevokedCount: int32(revoked)}, nil
+}
+
+// UpdateUserRoles grants roles to and revokes roles from a user
+func (s *UserServiceServer) UpdateUserRoles(ctx context.Context, req *user.UpdateUserRolesRequest) (*user.UpdateUserRolesResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("UpdateUserRoles").Observe(duration)
+	}()
+
+	s.logger.WithFields(map[string]any{
+		"user_id": req.UserId,
+		"grant":   req.Grant,
+		"revoke":  req.Revoke,
+	}).Info("UpdateUserRoles gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("UpdateUserRoles", invalidArgument("user_id", "user_id is required"))
+	}
+	if len(req.Grant) == 0 && len(req.Revoke) == 0 {
+		return nil, s.fail("UpdateUserRoles", invalidArgument("roles", "grant or revoke is required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionManageRoles, req.UserId); err != nil {
+		return nil, s.fail("UpdateUserRoles", err)
+	}
+
+	// Execute use case
+	input := userUseCase.UpdateUserRolesInput{
+		UserID: req.UserId,
+		Grant:  req.Grant,
+		Revoke: req.Revoke,
+	}
+
+	output, err := s.rolesUC.Execute(ctx, input)
+	if err != nil {
+		return nil, s.fail("UpdateUserRoles", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("UpdateUserRoles", "ok").Inc()
+
+	// Build response
+	return &user.UpdateUserRolesResponse{
+		User: &user.User{
+			Id:            output.ID,
+			Email:         output.Email,
+			Name:          output.Name,
+			Roles:         output.Roles,
+			EmailVerified: output.EmailVerified,
+			PendingEmail:  output.PendingEmail,
+			Etag:          formatETag(output.Version),
+			CreatedAt: &common.Timestamp{
+				Seconds: output.CreatedAt.Unix(),
+			},
+			UpdatedAt: &common.Timestamp{
+				Seconds: output.UpdatedAt.Unix(),
+			},
+		},
+	}, nil
+}
+
+// ChangePassword replaces a user's password after verifying the current one
+func (s *UserServiceServer) ChangePassword(ctx context.Context, req *user.ChangePasswordRequest) (*user.ChangePasswordResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("ChangePassword").Observe(duration)
+	}()
+
+	s.logger.WithField("user_id", req.UserId).Info("ChangePassword gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("ChangePassword", invalidArgument("user_id", "user_id is required"))
+	}
+	if req.CurrentPassword == "" {
+		return nil, s.fail("ChangePassword", invalidArgument("current_password", "current_password is required"))
+	}
+	if req.NewPassword == "" {
+		return nil, s.fail("ChangePassword", invalidArgument("new_password", "new_password is required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionChangePassword, req.UserId); err != nil {
+		return nil, s.fail("ChangePassword", err)
+	}
+
+	// Execute use case
+	input := userUseCase.ChangePasswordInput{
+		UserID:          req.UserId,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	}
+
+	if err := s.passwordUC.Execute(ctx, input); err != nil {
+		return nil, s.fail("ChangePassword", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("ChangePassword", "ok").Inc()
+
+	return &user.ChangePasswordResponse{}, nil
+}
+
+// RequestPasswordReset sends a password reset token to the user's email
+func (s *UserServiceServer) RequestPasswordReset(ctx context.Context, req *user.RequestPasswordResetRequest) (*user.RequestPasswordResetResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("RequestPasswordReset").Observe(duration)
+	}()
+
+	s.logger.WithField("email", req.Email).Info("RequestPasswordReset gRPC request")
+
+	// Validate input
+	if req.Email == "" {
+		return nil, s.fail("RequestPasswordReset", invalidArgument("email", "email is required"))
+	}
+
+	// Execute use case
+	if err := s.resetUC.Execute(ctx, userUseCase.RequestPasswordResetInput{Email: req.Email}); err != nil {
+		return nil, s.fail("RequestPasswordReset", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("RequestPasswordReset", "ok").Inc()
+
+	return &user.RequestPasswordResetResponse{}, nil
+}
+
+// ConfirmPasswordReset sets a new password using a password reset token
+func (s *UserServiceServer) ConfirmPasswordReset(ctx context.Context, req *user.ConfirmPasswordResetRequest) (*user.ConfirmPasswordResetResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("ConfirmPasswordReset").Observe(duration)
+	}()
+
+	s.logger.Info("ConfirmPasswordReset gRPC request")
+
+	// Validate input
+	if req.Token == "" {
+		return nil, s.fail("ConfirmPasswordReset", invalidArgument("token", "token is required"))
+	}
+	if req.NewPassword == "" {
+		return nil, s.fail("ConfirmPasswordReset", invalidArgument("new_password", "new_password is required"))
+	}
+
+	// Execute use case
+	input := userUseCase.ConfirmPasswordResetInput{
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+	}
+
+	if err := s.confirmUC.Execute(ctx, input); err != nil {
+		return nil, s.fail("ConfirmPasswordReset", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("ConfirmPasswordReset", "ok").Inc()
+
+	return &user.ConfirmPasswordResetResponse{}, nil
+}
+
+// VerifyEmail confirms that a user owns their email address
+func (s *UserServiceServer) VerifyEmail(ctx context.Context, req *user.VerifyEmailRequest) (*user.VerifyEmailResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("VerifyEmail").Observe(duration)
+	}()
+
+	s.logger.Info("VerifyEmail gRPC request")
+
+	// Validate input
+	if req.Token == "" {
+		return nil, s.fail("VerifyEmail", invalidArgument("token", "token is required"))
+	}
+
+	// Execute use case
+	if err := s.verifyUC.Execute(ctx, userUseCase.VerifyEmailInput{Token: req.Token}); err != nil {
+		return nil, s.fail("VerifyEmail", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("VerifyEmail", "ok").Inc()
+
+	return &user.VerifyEmailResponse{}, nil
+}
+
+// ChangeEmail switches a user to a new email address once it is verified
+func (s *UserServiceServer) ChangeEmail(ctx context.Context, req *user.ChangeEmailRequest) (*user.ChangeEmailResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("ChangeEmail").Observe(duration)
+	}()
+
+	s.logger.WithFields(map[string]any{
+		"user_id":   req.UserId,
+		"new_email": req.NewEmail,
+	}).Info("ChangeEmail gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("ChangeEmail", invalidArgument("user_id", "user_id is required"))
+	}
+	if req.NewEmail == "" {
+		return nil, s.fail("ChangeEmail", invalidArgument("new_email", "new_email is required"))
+	}
+	if req.CurrentPassword == "" {
+		return nil, s.fail("ChangeEmail", invalidArgument("current_password", "current_password is required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionChangeEmail, req.UserId); err != nil {
+		return nil, s.fail("ChangeEmail", err)
+	}
+
+	// Execute use case
+	input := userUseCase.ChangeEmailInput{
+		UserID:          req.UserId,
+		NewEmail:        req.NewEmail,
+		CurrentPassword: req.CurrentPassword,
+	}
+
+	output, err := s.emailUC.Execute(ctx, input)
+	if err != nil {
+		return nil, s.fail("ChangeEmail", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("ChangeEmail", "ok").Inc()
+
+	// Build response
+	return &user.ChangeEmailResponse{
+		User: &user.User{
+			Id:            output.ID,
+			Email:         output.Email,
+			Name:          output.Name,
+			Roles:         output.Roles,
+			EmailVerified: output.EmailVerified,
+			PendingEmail:  output.PendingEmail,
+			Etag:          formatETag(output.Version),
+			CreatedAt: &common.Timestamp{
+				Seconds: output.CreatedAt.Unix(),
+			},
+			UpdatedAt: &common.Timestamp{
+				Seconds: output.UpdatedAt.Unix(),
+			},
+		},
+	}, nil
+}
+
+// UnlockUser lifts the login delay or lockout of a user
+func (s *UserServiceServer) UnlockUser(ctx context.Context, req *user.UnlockUserRequest) (*user.UnlockUserResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("UnlockUser").Observe(duration)
+	}()
+
+	s.logger.WithField("user_id", req.UserId).Info("UnlockUser gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("UnlockUser", invalidArgument("user_id", "user_id is required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionUnlockUser, req.UserId); err != nil {
+		return nil, s.fail("UnlockUser", err)
+	}
+
+	// Execute use case
+	if err := s.unlockUC.Execute(ctx, userUseCase.UnlockUserInput{UserID: req.UserId}); err != nil {
+		return nil, s.fail("UnlockUser", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("UnlockUser", "ok").Inc()
+
+	return &user.UnlockUserResponse{}, nil
+}
+
+// StartTOTPEnrollment generates a TOTP secret for an authenticator app
+func (s *UserServiceServer) StartTOTPEnrollment(ctx context.Context, req *user.StartTOTPEnrollmentRequest) (*user.StartTOTPEnrollmentResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("StartTOTPEnrollment").Observe(duration)
+	}()
+
+	s.logger.WithField("user_id", req.UserId).Info("StartTOTPEnrollment gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("StartTOTPEnrollment", invalidArgument("user_id", "user_id is required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionManageTwoFactor, req.UserId); err != nil {
+		return nil, s.fail("StartTOTPEnrollment", err)
+	}
+
+	// Execute use case
+	output, err := s.enrollUC.Execute(ctx, userUseCase.StartTOTPEnrollmentInput{UserID: req.UserId})
+	if err != nil {
+		return nil, s.fail("StartTOTPEnrollment", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("StartTOTPEnrollment", "ok").Inc()
+
+	// Build response
+	return &user.StartTOTPEnrollmentResponse{
+		Secret:     output.Secret,
+		OtpauthUri: output.URI,
+	}, nil
+}
+
+// ConfirmTOTPEnrollment enables two-factor authentication
+func (s *UserServiceServer) ConfirmTOTPEnrollment(ctx context.Context, req *user.ConfirmTOTPEnrollmentRequest) (*user.ConfirmTOTPEnrollmentResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("ConfirmTOTPEnrollment").Observe(duration)
+	}()
+
+	s.logger.WithField("user_id", req.UserId).Info("ConfirmTOTPEnrollment gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("ConfirmTOTPEnrollment", invalidArgument("user_id", "user_id is required"))
+	}
+	if req.Code == "" {
+		return nil, s.fail("ConfirmTOTPEnrollment", invalidArgument("code", "code is required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionManageTwoFactor, req.UserId); err != nil {
+		return nil, s.fail("ConfirmTOTPEnrollment", err)
+	}
+
+	// Execute use case
+	input := userUseCase.ConfirmTOTPEnrollmentInput{
+		UserID: req.UserId,
+		Code:   req.Code,
+	}
+
+	output, err := s.enableUC.Execute(ctx, input)
+	if err != nil {
+		return nil, s.fail("ConfirmTOTPEnrollment", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("ConfirmTOTPEnrollment", "ok").Inc()
+
+	// Build response
+	return &user.ConfirmTOTPEnrollmentResponse{
+		RecoveryCodes: output.RecoveryCodes,
+	}, nil
+}
+
+// DisableTOTP turns two-factor authentication off
+func (s *UserServiceServer) DisableTOTP(ctx context.Context, req *user.DisableTOTPRequest) (*user.DisableTOTPResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("DisableTOTP").Observe(duration)
+	}()
+
+	s.logger.WithField("user_id", req.UserId).Info("DisableTOTP gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("DisableTOTP", invalidArgument("user_id", "user_id is required"))
+	}
+	if err := validateSecondFactor(req.Code, req.RecoveryCode); err != nil {
+		return nil, s.fail("DisableTOTP", err)
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionManageTwoFactor, req.UserId); err != nil {
+		return nil, s.fail("DisableTOTP", err)
+	}
+
+	// Execute use case
+	_, ipAddress := clientInfo(ctx, s.proxies)
+	input := userUseCase.DisableTOTPInput{
+		UserID:       req.UserId,
+		Code:         req.Code,
+		RecoveryCode: req.RecoveryCode,
+		IPAddress:    ipAddress,
+	}
+
+	if err := s.disableUC.Execute(ctx, input); err != nil {
+		return nil, s.fail("DisableTOTP", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("DisableTOTP", "ok").Inc()
+
+	return &user.DisableTOTPResponse{}, nil
+}
+
+// VerifyLoginChallenge completes a login that requires a second factor
+func (s *UserServiceServer) VerifyLoginChallenge(ctx context.Context, req *user.VerifyLoginChallengeRequest) (*user.VerifyLoginChallengeResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("VerifyLoginChallenge").Observe(duration)
+	}()
+
+	s.logger.Info("VerifyLoginChallenge gRPC request")
+
+	// Validate input
+	if req.ChallengeToken == "" {
+		return nil, s.fail("VerifyLoginChallenge", invalidArgument("challenge_token", "challenge_token is required"))
+	}
+	if err := validateSecondFactor(req.Code, req.RecoveryCode); err != nil {
+		return nil, s.fail("VerifyLoginChallenge", err)
+	}
+
+	// Execute use case
+	userAgent, ipAddress := clientInfo(ctx, s.proxies)
+	input := userUseCase.VerifyLoginChallengeInput{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+		RecoveryCode:   req.RecoveryCode,
+		UserAgent:      userAgent,
+		IPAddress:      ipAddress,
+	}
+
+	output, err := s.challengeUC.Execute(ctx, input)
+	if err != nil {
+		return nil, s.fail("VerifyLoginChallenge", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("VerifyLoginChallenge", "ok").Inc()
+
+	// Build response
+	return &user.VerifyLoginChallengeResponse{
+		AccessToken: output.AccessToken,
+		TokenType:   output.TokenType,
+		ExpiresAt: &common.Timestamp{
+			Seconds: output.ExpiresAt.Unix(),
+		},
+		RefreshToken: output.RefreshToken,
+		RefreshTokenExpiresAt: &common.Timestamp{
+			Seconds: output.RefreshTokenExpiresAt.Unix(),
+		},
+		SessionId: output.SessionID,
+	}, nil
+}
+
+// RequestMagicLink sends a passwordless login link to the user's email
+func (s *UserServiceServer) RequestMagicLink(ctx context.Context, req *user.RequestMagicLinkRequest) (*user.RequestMagicLinkResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("RequestMagicLink").Observe(duration)
+	}()
+
+	s.logger.WithField("email", req.Email).Info("RequestMagicLink gRPC request")
+
+	// Validate input
+	if req.Email == "" {
+		return nil, s.fail("RequestMagicLink", invalidArgument("email", "email is required"))
+	}
+
+	// Execute use case
+	if err := s.magicLinkUC.Execute(ctx, userUseCase.RequestMagicLinkInput{Email: req.Email}); err != nil {
+		return nil, s.fail("RequestMagicLink", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("RequestMagicLink", "ok").Inc()
+
+	return &user.RequestMagicLinkResponse{}, nil
+}
+
+// ConsumeMagicLink logs in with the token of a magic link
+func (s *UserServiceServer) ConsumeMagicLink(ctx context.Context, req *user.ConsumeMagicLinkRequest) (*user.ConsumeMagicLinkResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("ConsumeMagicLink").Observe(duration)
+	}()
+
+	s.logger.Info("ConsumeMagicLink gRPC request")
+
+	// Validate input
+	if req.Token == "" {
+		return nil, s.fail("ConsumeMagicLink", invalidArgument("token", "token is required"))
+	}
+
+	// Execute use case
+	userAgent, ipAddress := clientInfo(ctx, s.proxies)
+	input := userUseCase.ConsumeMagicLinkInput{
+		Token:     req.Token,
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+	}
+
+	output, err := s.consumeUC.Execute(ctx, input)
+	if err != nil {
+		return nil, s.fail("ConsumeMagicLink", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("ConsumeMagicLink", "ok").Inc()
+
+	// Build response
+	if output.TwoFactorRequired {
+		return &user.ConsumeMagicLinkResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    output.ChallengeToken,
+			ChallengeExpiresAt: &common.Timestamp{
+				Seconds: output.ChallengeExpiresAt.Unix(),
+			},
+		}, nil
+	}
+	return &user.ConsumeMagicLinkResponse{
+		AccessToken: output.AccessToken,
+		TokenType:   output.TokenType,
+		ExpiresAt: &common.Timestamp{
+			Seconds: output.ExpiresAt.Unix(),
+		},
+		RefreshToken: output.RefreshToken,
+		RefreshTokenExpiresAt: &common.Timestamp{
+			Seconds: output.RefreshTokenExpiresAt.Unix(),
+		},
+		SessionId: output.SessionID,
+	}, nil
+}
+
+// BeginPasskeyRegistration returns the options of a passkey registration ceremony
+func (s *UserServiceServer) BeginPasskeyRegistration(ctx context.Context, req *user.BeginPasskeyRegistrationRequest) (*user.BeginPasskeyRegistrationResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("BeginPasskeyRegistration").Observe(duration)
+	}()
+
+	s.logger.WithField("user_id", req.UserId).Info("BeginPasskeyRegistration gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("BeginPasskeyRegistration", invalidArgument("user_id", "user_id is required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionManagePasskeys, req.UserId); err != nil {
+		return nil, s.fail("BeginPasskeyRegistration", err)
+	}
+
+	// Execute use case
+	output, err := s.beginRegisterUC.Execute(ctx, userUseCase.BeginPasskeyRegistrationInput{UserID: req.UserId})
+	if err != nil {
+		return nil, s.fail("BeginPasskeyRegistration", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("BeginPasskeyRegistration", "ok").Inc()
+
+	// Build response
+	excludeCredentialIDs := make([]string, len(output.ExcludeCredentialIDs))
+	for i, id := range output.ExcludeCredentialIDs {
+		excludeCredentialIDs[i] = base64.RawURLEncoding.EncodeToString(id)
+	}
+	algorithms := make([]int32, len(output.Algorithms))
+	for i, alg := range output.Algorithms {
+		algorithms[i] = int32(alg)
+	}
+
+	return &user.BeginPasskeyRegistrationResponse{
+		Challenge:            output.Challenge,
+		RpId:                 output.RPID,
+		RpName:               output.RPName,
+		UserHandle:           base64.RawURLEncoding.EncodeToString([]byte(output.UserHandle)),
+		UserName:             output.UserName,
+		UserDisplayName:      output.UserDisplayName,
+		ExcludeCredentialIds: excludeCredentialIDs,
+		Algorithms:           algorithms,
+	}, nil
+}
+
+// FinishPasskeyRegistration stores a passkey of the user
+func (s *UserServiceServer) FinishPasskeyRegistration(ctx context.Context, req *user.FinishPasskeyRegistrationRequest) (*user.FinishPasskeyRegistrationResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("FinishPasskeyRegistration").Observe(duration)
+	}()
+
+	s.logger.WithField("user_id", req.UserId).Info("FinishPasskeyRegistration gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("FinishPasskeyRegistration", invalidArgument("user_id", "user_id is required"))
+	}
+	clientDataJSON, err := decodeBase64URL("client_data_json", req.ClientDataJson)
+	if err != nil {
+		return nil, s.fail("FinishPasskeyRegistration", err)
+	}
+	attestationObject, err := decodeBase64URL("attestation_object", req.AttestationObject)
+	if err != nil {
+		return nil, s.fail("FinishPasskeyRegistration", err)
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionManagePasskeys, req.UserId); err != nil {
+		return nil, s.fail("FinishPasskeyRegistration", err)
+	}
+
+	// Execute use case
+	input := userUseCase.FinishPasskeyRegistrationInput{
+		UserID:            req.UserId,
+		Name:              req.Name,
+		ClientDataJSON:    clientDataJSON,
+		AttestationObject: attestationObject,
+		Transports:        req.Transports,
+	}
+
+	output, err := s.finishRegisterUC.Execute(ctx, input)
+	if err != nil {
+		return nil, s.fail("FinishPasskeyRegistration", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("FinishPasskeyRegistration", "ok").Inc()
+
+	// Build response
+	return &user.FinishPasskeyRegistrationResponse{
+		Passkey: toProtoPasskey(output.Passkey),
+	}, nil
+}
+
+// BeginPasskeyLogin returns the options of a passkey authentication ceremony
+func (s *UserServiceServer) BeginPasskeyLogin(ctx context.Context, req *user.BeginPasskeyLoginRequest) (*user.BeginPasskeyLoginResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("BeginPasskeyLogin").Observe(duration)
+	}()
+
+	s.logger.Info("BeginPasskeyLogin gRPC request")
+
+	// Execute use case
+	output, err := s.beginPasskeyUC.Execute(ctx)
+	if err != nil {
+		return nil, s.fail("BeginPasskeyLogin", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("BeginPasskeyLogin", "ok").Inc()
+
+	// Build response
+	return &user.BeginPasskeyLoginResponse{
+		Challenge: output.Challenge,
+		RpId:      output.RPID,
+	}, nil
+}
+
+// FinishPasskeyLogin authenticates a user with a passkey
+func (s *UserServiceServer) FinishPasskeyLogin(ctx context.Context, req *user.FinishPasskeyLoginRequest) (*user.FinishPasskeyLoginResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("FinishPasskeyLogin").Observe(duration)
+	}()
+
+	s.logger.Info("FinishPasskeyLogin gRPC request")
+
+	// Validate input
+	credentialID, err := decodeBase64URL("credential_id", req.CredentialId)
+	if err != nil {
+		return nil, s.fail("FinishPasskeyLogin", err)
+	}
+	clientDataJSON, err := decodeBase64URL("client_data_json", req.ClientDataJson)
+	if err != nil {
+		return nil, s.fail("FinishPasskeyLogin", err)
+	}
+	authenticatorData, err := decodeBase64URL("authenticator_data", req.AuthenticatorData)
+	if err != nil {
+		return nil, s.fail("FinishPasskeyLogin", err)
+	}
+	signature, err := decodeBase64URL("signature", req.Signature)
+	if err != nil {
+		return nil, s.fail("FinishPasskeyLogin", err)
+	}
+	var userHandle []byte
+	if req.UserHandle != "" {
+		if userHandle, err = decodeBase64URL("user_handle", req.UserHandle); err != nil {
+			return nil, s.fail("FinishPasskeyLogin", err)
+		}
+	}
+
+	// Execute use case
+	userAgent, ipAddress := clientInfo(ctx, s.proxies)
+	input := userUseCase.FinishPasskeyLoginInput{
+		CredentialID:      credentialID,
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authenticatorData,
+		Signature:         signature,
+		UserHandle:        string(userHandle),
+		UserAgent:         userAgent,
+		IPAddress:         ipAddress,
+	}
+
+	output, err := s.finishPasskeyUC.Execute(ctx, input)
+	if err != nil {
+		return nil, s.fail("FinishPasskeyLogin", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("FinishPasskeyLogin", "ok").Inc()
+
+	// Build response
+	return &user.FinishPasskeyLoginResponse{
+		AccessToken: output.AccessToken,
+		TokenType:   output.TokenType,
+		ExpiresAt: &common.Timestamp{
+			Seconds: output.ExpiresAt.Unix(),
+		},
+		RefreshToken: output.RefreshToken,
+		RefreshTokenExpiresAt: &common.Timestamp{
+			Seconds: output.RefreshTokenExpiresAt.Unix(),
+		},
+		SessionId: output.SessionID,
+	}, nil
+}
+
+// CreateAPIKey creates an API key acting as a user
+func (s *UserServiceServer) CreateAPIKey(ctx context.Context, req *user.CreateAPIKeyRequest) (*user.CreateAPIKeyResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("CreateAPIKey").Observe(duration)
+	}()
+
+	s.logger.WithField("user_id", req.UserId).Info("CreateAPIKey gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("CreateAPIKey", invalidArgument("user_id", "user_id is required"))
+	}
+	if req.Name == "" {
+		return nil, s.fail("CreateAPIKey", invalidArgument("name", "name is required"))
+	}
+	if len(req.Scopes) == 0 {
+		return nil, s.fail("CreateAPIKey", invalidArgument("scopes", "scopes are required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionManageAPIKeys, req.UserId); err != nil {
+		return nil, s.fail("CreateAPIKey", err)
+	}
+
+	// Execute use case
+	principal, _ := auth.PrincipalFromContext(ctx)
+	input := userUseCase.CreateAPIKeyInput{
+		UserID:         req.UserId,
+		Name:           req.Name,
+		Scopes:         req.Scopes,
+		CallerAPIKeyID: principal.APIKeyID,
+	}
+	if req.ExpiresAt != nil {
+		expiresAt := time.Unix(req.ExpiresAt.Seconds, int64(req.ExpiresAt.Nanos))
+		input.ExpiresAt = &expiresAt
+	}
+
+	output, err := s.createKeyUC.Execute(ctx, input)
+	if err != nil {
+		return nil, s.fail("CreateAPIKey", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("CreateAPIKey", "ok").Inc()
+
+	// Build response
+	return &user.CreateAPIKeyResponse{
+		Key:    output.Key,
+		ApiKey: toProtoAPIKey(output.APIKey),
+	}, nil
+}
+
+// ListAPIKeys lists the API keys of a user
+func (s *UserServiceServer) ListAPIKeys(ctx context.Context, req *user.ListAPIKeysRequest) (*user.ListAPIKeysResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("ListAPIKeys").Observe(duration)
+	}()
+
+	s.logger.WithField("user_id", req.UserId).Info("ListAPIKeys gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("ListAPIKeys", invalidArgument("user_id", "user_id is required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionManageAPIKeys, req.UserId); err != nil {
+		return nil, s.fail("ListAPIKeys", err)
+	}
+
+	// Execute use case
+	output, err := s.listKeysUC.Execute(ctx, userUseCase.ListAPIKeysInput{UserID: req.UserId})
+	if err != nil {
+		return nil, s.fail("ListAPIKeys", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("ListAPIKeys", "ok").Inc()
+
+	// Build response
+	keys := make([]*user.APIKey, len(output))
+	for i, key := range output {
+		keys[i] = toProtoAPIKey(key)
+	}
+
+	return &user.ListAPIKeysResponse{ApiKeys: keys}, nil
+}
+
+// RevokeAPIKey revokes an API key of a user
+func (s *UserServiceServer) RevokeAPIKey(ctx context.Context, req *user.RevokeAPIKeyRequest) (*user.RevokeAPIKeyResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("RevokeAPIKey").Observe(duration)
+	}()
+
+	s.logger.WithFields(map[string]any{
+		"user_id":    req.UserId,
+		"api_key_id": req.ApiKeyId,
+	}).Info("RevokeAPIKey gRPC request")
+
+	// Validate input
+	if req.UserId == "" {
+		return nil, s.fail("RevokeAPIKey", invalidArgument("user_id", "user_id is required"))
+	}
+	if req.ApiKeyId == "" {
+		return nil, s.fail("RevokeAPIKey", invalidArgument("api_key_id", "api_key_id is required"))
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionManageAPIKeys, req.UserId); err != nil {
+		return nil, s.fail("RevokeAPIKey", err)
+	}
+
+	// Execute use case
+	input := userUseCase.RevokeAPIKeyInput{
+		UserID:   req.UserId,
+		APIKeyID: req.ApiKeyId,
+	}
+
+	if err := s.revokeKeyUC.Execute(ctx, input); err != nil {
+		return nil, s.fail("RevokeAPIKey", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("RevokeAPIKey", "ok").Inc()
+
+	return &user.RevokeAPIKeyResponse{}, nil
+}
+
+// GetMe retrieves the authenticated user
+func (s *UserServiceServer) GetMe(ctx context.Context, req *user.GetMeRequest) (*user.GetMeResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("GetMe").Observe(duration)
+	}()
+
+	// Resolve caller
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, s.fail("GetMe", domainUser.ErrUnauthorized)
+	}
+
+	s.logger.WithField("user_id", principal.UserID).Info("GetMe gRPC request")
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionReadUser, principal.UserID); err != nil {
+		return nil, s.fail("GetMe", err)
+	}
+
+	// Execute use cases
+	output, err := s.getUserUC.Execute(ctx, userUseCase.GetUserInput{UserID: principal.UserID, IncludePendingEmail: true})
+	if err != nil {
+		return nil, s.fail("GetMe", err)
+	}
+
+	security, err := s.securityUC.Execute(ctx, userUseCase.GetSecuritySettingsInput{UserID: principal.UserID})
+	if err != nil {
+		return nil, s.fail("GetMe", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("GetMe", "ok").Inc()
+	// No validators: the security settings change without a new user version,
+	// so an ETag or Last-Modified of the user would not cover the response
+
+	// Build response
+	return &user.GetMeResponse{
+		User: &user.User{
+			Id:            output.ID,
+			Email:         output.Email,
+			Name:          output.Name,
+			Roles:         output.Roles,
+			EmailVerified: output.EmailVerified,
+			PendingEmail:  output.PendingEmail,
+			Etag:          formatETag(output.Version),
+			CreatedAt: &common.Timestamp{
+				Seconds: output.CreatedAt.Unix(),
+			},
+			UpdatedAt: &common.Timestamp{
+				Seconds: output.UpdatedAt.Unix(),
+			},
+		},
+		Security: toProtoSecuritySettings(security),
+	}, nil
+}
+
+// UpdateMe updates the profile of the authenticated user
+func (s *UserServiceServer) UpdateMe(ctx context.Context, req *user.UpdateMeRequest) (*user.UpdateMeResponse, error) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.GRPCRequestDuration.WithLabelValues("UpdateMe").Observe(duration)
+	}()
+
+	// Resolve caller
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, s.fail("UpdateMe", domainUser.ErrUnauthorized)
+	}
+
+	s.logger.WithFields(map[string]any{
+		"user_id":     principal.UserID,
+		"name":        req.Name,
+		"update_mask": req.UpdateMask.GetPaths(),
+	}).Info("UpdateMe gRPC request")
+
+	// Validate input
+	version, err := parseETag(requestETag(ctx, req.Etag))
+	if err != nil {
+		return nil, s.fail("UpdateMe", err)
+	}
+
+	// Authorize caller
+	if err := s.authorize(ctx, domainUser.ActionUpdateUser, principal.UserID); err != nil {
+		return nil, s.fail("UpdateMe", err)
+	}
+
+	// Execute use cases
+	input := userUseCase.UpdateUserInput{
+		UserID:     principal.UserID,
+		Name:       req.Name,
+		UpdateMask: req.UpdateMask.GetPaths(),
+		Version:    version,
+	}
+
+	output, err := s.updateUserUC.Execute(ctx, input)
+	if err != nil {
+		return nil, s.fail("UpdateMe", err)
+	}
+
+	security, err := s.securityUC.Execute(ctx, userUseCase.GetSecuritySettingsInput{UserID: principal.UserID})
+	if err != nil {
+		return nil, s.fail("UpdateMe", err)
+	}
+
+	s.metrics.GRPCRequestsTotal.WithLabelValues("UpdateMe", "ok").Inc()
+	// No validators: the security settings change without a new user version,
+	// so an ETag or Last-Modified of the user would not cover the response
+
+	// Build response
+	return &user.UpdateMeResponse{
+		User: &user.User{
+			Id:            output.ID,
+			Email:         output.Email,
+			Name:          output.Name,
+			Roles:         output.Roles,
+			EmailVerified: output.EmailVerified,
+			PendingEmail:  output.PendingEmail,
+			Etag:          formatETag(output.Version),
+			CreatedAt: &common.Timestamp{
+				Seconds: output.CreatedAt.Unix(),
+			},
+			UpdatedAt: &common.Timestamp{
+				Seconds: output.UpdatedAt.Unix(),
+			},
+		},
+		Security: toProtoSecuritySettings(security),
+	}, nil
+}
+
+// toProtoSecuritySettings converts security settings to their protobuf representation
+func toProtoSecuritySettings(o *userUseCase.GetSecuritySettingsOutput) *user.SecuritySettings {
+	return &user.SecuritySettings{
+		TwoFactorEnabled: o.TwoFactorEnabled,
+		Passkeys:         int32(o.Passkeys),
+	}
+}
+
+// toProtoAPIKey converts a domain API key to its protobuf representation
+func toProtoAPIKey(k *domainUser.APIKey) *user.APIKey {
+	key := &user.APIKey{
+		Id:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    k.Scopes,
+		CreatedAt: &common.Timestamp{Seconds: k.CreatedAt.Unix()},
+	}
+	if k.ExpiresAt != nil {
+		key.ExpiresAt = &common.Timestamp{Seconds: k.ExpiresAt.Unix()}
+	}
+	return key
+}
+
+// toProtoPasskey converts a domain passkey to its protobuf representation
+func toProtoPasskey(p *domainUser.Passkey) *user.Passkey {
+	passkey := &user.Passkey{
+		Id:         p.ID,
+		Name:       p.Name,
+		Transports: p.Transports,
+		CreatedAt:  &common.Timestamp{Seconds: p.CreatedAt.Unix()},
+	}
+	if p.LastUsedAt != nil {
+		passkey.LastUsedAt = &common.Timestamp{Seconds: p.LastUsedAt.Unix()}
+	}
+	return passkey
+}
+
+// decodeBase64URL decodes a required base64url value of a WebAuthn response,
+// as produced by browsers, with or without padding
+func decodeBase64URL(field, value string) ([]byte, error) {
+	if value == "" {
+		return nil, invalidArgument(field, field+" is required")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, invalidArgument(field, field+" must be base64url encoded")
+	}
+	return data, nil
+}
+
+// validateSecondFactor requires exactly one of a TOTP code and a recovery code
+func validateSecondFactor(code, recoveryCode string) error {
+	if code == "" && recoveryCode == "" {
+		return invalidArgument("code", "code or recovery_code is required")
+	}
+	if code != "" && recoveryCode != "" {
+		return invalidArgument("recovery_code", "only one of code and recovery_code may be set")
+	}
+	return nil
 }
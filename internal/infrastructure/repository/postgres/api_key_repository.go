@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+)
+
+// APIKeyRepository implements user.APIKeyRepository interface using PostgreSQL
+type APIKeyRepository struct {
+	queries *sqlc.Queries
+}
+
+// NewAPIKeyRepository creates a new PostgreSQL API key repository
+func NewAPIKeyRepository(db *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{
+		queries: sqlc.New(db),
+	}
+}
+
+// Create inserts a new API key into the database
+func (r *APIKeyRepository) Create(ctx context.Context, k *user.APIKey) error {
+	params := sqlc.CreateAPIKeyParams{
+		ID:        k.ID,
+		UserID:    k.UserID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		KeyHash:   k.KeyHash,
+		Scopes:    k.Scopes,
+		CreatedAt: toTimestamp(k.CreatedAt),
+	}
+	if k.ExpiresAt != nil {
+		params.ExpiresAt = toTimestamp(*k.ExpiresAt)
+	}
+
+	if _, err := r.queries.CreateAPIKey(ctx, params); err != nil {
+		if isUniqueViolation(err) {
+			return user.ErrAPIKeyAlreadyExists
+		}
+		if isForeignKeyViolation(err) {
+			return user.ErrUserNotFound
+		}
+		return translateError("create api key", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an API key by ID
+func (r *APIKeyRepository) GetByID(ctx context.Context, id string) (*user.APIKey, error) {
+	row, err := r.queries.GetAPIKey(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrAPIKeyNotFound
+		}
+		return nil, translateError("get api key", err)
+	}
+	return toDomainAPIKey(row), nil
+}
+
+// GetByHash retrieves an API key by the hash of the key
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*user.APIKey, error) {
+	row, err := r.queries.GetAPIKeyByHash(ctx, keyHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrInvalidAPIKey
+		}
+		return nil, translateError("get api key", err)
+	}
+	return toDomainAPIKey(row), nil
+}
+
+// ListByUser retrieves the API keys of a user that are not revoked, newest first
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID string) ([]*user.APIKey, error) {
+	rows, err := r.queries.ListUserAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, translateError("list api keys", err)
+	}
+
+	keys := make([]*user.APIKey, len(rows))
+	for i, row := range rows {
+		keys[i] = toDomainAPIKey(row)
+	}
+	return keys, nil
+}
+
+// Revoke marks the API key revoked
+func (r *APIKeyRepository) Revoke(ctx context.Context, k *user.APIKey, at time.Time) error {
+	rows, err := r.queries.RevokeAPIKey(ctx, sqlc.RevokeAPIKeyParams{
+		ID:        k.ID,
+		RevokedAt: toTimestamp(at),
+	})
+	if err != nil {
+		return translateError("revoke api key", err)
+	}
+	if rows == 0 {
+		return user.ErrAPIKeyNotFound
+	}
+
+	k.RevokedAt = &at
+	return nil
+}
+
+func toDomainAPIKey(row sqlc.ApiKey) *user.APIKey {
+	k := &user.APIKey{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Name:      row.Name,
+		Prefix:    row.Prefix,
+		KeyHash:   row.KeyHash,
+		Scopes:    row.Scopes,
+		CreatedAt: row.CreatedAt.Time,
+	}
+	if row.ExpiresAt.Valid {
+		expiresAt := row.ExpiresAt.Time
+		k.ExpiresAt = &expiresAt
+	}
+	if row.RevokedAt.Valid {
+		revokedAt := row.RevokedAt.Time
+		k.RevokedAt = &revokedAt
+	}
+	return k
+}
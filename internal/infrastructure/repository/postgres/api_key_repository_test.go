@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyRepository_ErrorTranslation(t *testing.T) {
+	key := &user.APIKey{ID: "key-1", UserID: "user-1", Scopes: []string{"/user.UserService/GetUser"}}
+
+	t.Run("get unknown id", func(t *testing.T) {
+		repo := &APIKeyRepository{queries: sqlc.New(&fakeDB{err: pgx.ErrNoRows})}
+		_, err := repo.GetByID(context.Background(), "unknown")
+		assert.ErrorIs(t, err, user.ErrAPIKeyNotFound)
+	})
+
+	t.Run("get unknown hash", func(t *testing.T) {
+		repo := &APIKeyRepository{queries: sqlc.New(&fakeDB{err: pgx.ErrNoRows})}
+		_, err := repo.GetByHash(context.Background(), "unknown")
+		assert.ErrorIs(t, err, user.ErrInvalidAPIKey)
+	})
+
+	t.Run("create for missing user", func(t *testing.T) {
+		repo := &APIKeyRepository{queries: sqlc.New(&fakeDB{err: &pgconn.PgError{Code: "23503"}})}
+		assert.ErrorIs(t, repo.Create(context.Background(), key), user.ErrUserNotFound)
+	})
+
+	t.Run("create duplicate key", func(t *testing.T) {
+		repo := &APIKeyRepository{queries: sqlc.New(&fakeDB{err: &pgconn.PgError{Code: "23505"}})}
+		err := repo.Create(context.Background(), key)
+		assert.ErrorIs(t, err, user.ErrAPIKeyAlreadyExists)
+		assert.NotErrorIs(t, err, user.ErrUserAlreadyExists)
+	})
+
+	t.Run("revoke revoked key", func(t *testing.T) {
+		repo := &APIKeyRepository{queries: sqlc.New(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 0")})}
+		assert.ErrorIs(t, repo.Revoke(context.Background(), key, time.Now()), user.ErrAPIKeyNotFound)
+		assert.Nil(t, key.RevokedAt)
+	})
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+)
+
+// EmailVerificationRepository implements user.EmailVerificationRepository interface using PostgreSQL
+type EmailVerificationRepository struct {
+	queries *sqlc.Queries
+}
+
+// NewEmailVerificationRepository creates a new PostgreSQL email verification token repository
+func NewEmailVerificationRepository(db *pgxpool.Pool) *EmailVerificationRepository {
+	return &EmailVerificationRepository{
+		queries: sqlc.New(db),
+	}
+}
+
+// Create inserts a new email verification token into the database
+func (r *EmailVerificationRepository) Create(ctx context.Context, t *user.EmailVerificationToken) error {
+	params := sqlc.CreateEmailVerificationTokenParams{
+		TokenHash: t.TokenHash,
+		UserID:    t.UserID,
+		Email:     t.Email,
+		CreatedAt: toTimestamp(t.CreatedAt),
+		ExpiresAt: toTimestamp(t.ExpiresAt),
+	}
+
+	if _, err := r.queries.CreateEmailVerificationToken(ctx, params); err != nil {
+		if isForeignKeyViolation(err) {
+			return user.ErrUserNotFound
+		}
+		return translateError("create email verification token", err)
+	}
+
+	return nil
+}
+
+// GetByHash retrieves an email verification token by the hash of its value
+func (r *EmailVerificationRepository) GetByHash(ctx context.Context, tokenHash string) (*user.EmailVerificationToken, error) {
+	row, err := r.queries.GetEmailVerificationToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrInvalidVerificationToken
+		}
+		return nil, translateError("get email verification token", err)
+	}
+
+	return &user.EmailVerificationToken{
+		TokenHash: row.TokenHash,
+		UserID:    row.UserID,
+		Email:     row.Email,
+		CreatedAt: row.CreatedAt.Time,
+		ExpiresAt: row.ExpiresAt.Time,
+		UsedAt:    fromNullTimestamp(row.UsedAt),
+	}, nil
+}
+
+// Consume marks the token used and invalidates the user's other outstanding tokens
+func (r *EmailVerificationRepository) Consume(ctx context.Context, t *user.EmailVerificationToken, at time.Time) error {
+	rows, err := r.queries.UseEmailVerificationToken(ctx, sqlc.UseEmailVerificationTokenParams{
+		TokenHash: t.TokenHash,
+		UsedAt:    toTimestamp(at),
+	})
+	if err != nil {
+		return translateError("use email verification token", err)
+	}
+	if rows == 0 {
+		return user.ErrInvalidVerificationToken
+	}
+
+	if _, err := r.queries.InvalidateEmailVerificationTokens(ctx, sqlc.InvalidateEmailVerificationTokensParams{
+		UserID: t.UserID,
+		UsedAt: toTimestamp(at),
+	}); err != nil {
+		return translateError("invalidate email verification tokens", err)
+	}
+
+	t.UsedAt = &at
+	return nil
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+)
+
+// PostgreSQL error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+)
+
+// translateError converts a database error into a domain error when it has a
+// domain meaning, otherwise annotates it with the failed operation.
+// Connectivity failures are marked with user.ErrStorageUnavailable.
+func translateError(op string, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return user.ErrUserNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return fmt.Errorf("failed to %s: %w", op, user.ErrUserAlreadyExists)
+	}
+
+	if isUnavailable(err) {
+		return fmt.Errorf("failed to %s: %w: %w", op, user.ErrStorageUnavailable, err)
+	}
+	return fmt.Errorf("failed to %s: %w", op, err)
+}
+
+// isForeignKeyViolation reports whether err was caused by a missing referenced row
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation
+}
+
+// isUniqueViolation reports whether err was caused by a duplicate key
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+// isUnavailable reports whether err means the database could not be reached
+// or refused to serve the query, as opposed to rejecting the query itself
+func isUnavailable(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Class 08 (connection exception), class 53 (insufficient resources)
+		// and 57P01-57P03 (server shutting down or not accepting connections)
+		return strings.HasPrefix(pgErr.Code, "08") ||
+			strings.HasPrefix(pgErr.Code, "53") ||
+			strings.HasPrefix(pgErr.Code, "57P0")
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return pgconn.Timeout(err) || pgconn.SafeToRetry(err)
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+)
+
+// LoginChallengeRepository implements user.LoginChallengeRepository interface using PostgreSQL
+type LoginChallengeRepository struct {
+	queries *sqlc.Queries
+}
+
+// NewLoginChallengeRepository creates a new PostgreSQL login challenge repository
+func NewLoginChallengeRepository(db *pgxpool.Pool) *LoginChallengeRepository {
+	return &LoginChallengeRepository{
+		queries: sqlc.New(db),
+	}
+}
+
+// Create inserts a new login challenge into the database
+func (r *LoginChallengeRepository) Create(ctx context.Context, c *user.LoginChallenge) error {
+	params := sqlc.CreateLoginChallengeParams{
+		TokenHash: c.TokenHash,
+		UserID:    c.UserID,
+		CreatedAt: toTimestamp(c.CreatedAt),
+		ExpiresAt: toTimestamp(c.ExpiresAt),
+	}
+
+	if _, err := r.queries.CreateLoginChallenge(ctx, params); err != nil {
+		if isForeignKeyViolation(err) {
+			return user.ErrUserNotFound
+		}
+		return translateError("create login challenge", err)
+	}
+
+	return nil
+}
+
+// GetByHash retrieves a login challenge by the hash of its token
+func (r *LoginChallengeRepository) GetByHash(ctx context.Context, tokenHash string) (*user.LoginChallenge, error) {
+	row, err := r.queries.GetLoginChallenge(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrInvalidLoginChallenge
+		}
+		return nil, translateError("get login challenge", err)
+	}
+
+	return &user.LoginChallenge{
+		TokenHash: row.TokenHash,
+		UserID:    row.UserID,
+		CreatedAt: row.CreatedAt.Time,
+		ExpiresAt: row.ExpiresAt.Time,
+		UsedAt:    fromNullTimestamp(row.UsedAt),
+	}, nil
+}
+
+// Consume marks the challenge used
+func (r *LoginChallengeRepository) Consume(ctx context.Context, c *user.LoginChallenge, at time.Time) error {
+	rows, err := r.queries.UseLoginChallenge(ctx, sqlc.UseLoginChallengeParams{
+		TokenHash: c.TokenHash,
+		UsedAt:    toTimestamp(at),
+	})
+	if err != nil {
+		return translateError("use login challenge", err)
+	}
+	if rows == 0 {
+		return user.ErrInvalidLoginChallenge
+	}
+
+	c.UsedAt = &at
+	return nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+)
+
+// LoginFailureRepository implements user.LoginFailureRepository interface using PostgreSQL
+type LoginFailureRepository struct {
+	queries *sqlc.Queries
+}
+
+// NewLoginFailureRepository creates a new PostgreSQL login failure repository
+func NewLoginFailureRepository(db *pgxpool.Pool) *LoginFailureRepository {
+	return &LoginFailureRepository{
+		queries: sqlc.New(db),
+	}
+}
+
+// Get retrieves the failed logins of a subject key
+func (r *LoginFailureRepository) Get(ctx context.Context, subject user.LoginSubject, key string) (*user.LoginFailures, error) {
+	row, err := r.queries.GetLoginFailures(ctx, sqlc.GetLoginFailuresParams{
+		Subject:    string(subject),
+		SubjectKey: key,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &user.LoginFailures{Subject: subject, Key: key}, nil
+		}
+		return nil, translateError("get login failures", err)
+	}
+	return toDomainLoginFailures(row), nil
+}
+
+// RecordFailure counts a failed login in a single upsert, so that concurrent
+// failures on different replicas are all counted
+func (r *LoginFailureRepository) RecordFailure(ctx context.Context, subject user.LoginSubject, key string, at, windowStart time.Time) (*user.LoginFailures, error) {
+	row, err := r.queries.RecordLoginFailure(ctx, sqlc.RecordLoginFailureParams{
+		Subject:     string(subject),
+		SubjectKey:  key,
+		FailedAt:    toTimestamp(at),
+		WindowStart: toTimestamp(windowStart),
+	})
+	if err != nil {
+		return nil, translateError("record login failure", err)
+	}
+	return toDomainLoginFailures(row), nil
+}
+
+// Lock stores when the lockout of a subject key ends
+func (r *LoginFailureRepository) Lock(ctx context.Context, f *user.LoginFailures) error {
+	if _, err := r.queries.LockLoginFailures(ctx, sqlc.LockLoginFailuresParams{
+		Subject:     string(f.Subject),
+		SubjectKey:  f.Key,
+		LockedUntil: toNullTimestamp(f.LockedUntil),
+	}); err != nil {
+		return translateError("lock login failures", err)
+	}
+	return nil
+}
+
+// Reset deletes the failed logins and lockout of a subject key
+func (r *LoginFailureRepository) Reset(ctx context.Context, subject user.LoginSubject, key string) error {
+	if err := r.queries.DeleteLoginFailures(ctx, sqlc.DeleteLoginFailuresParams{
+		Subject:    string(subject),
+		SubjectKey: key,
+	}); err != nil {
+		return translateError("delete login failures", err)
+	}
+	return nil
+}
+
+func toDomainLoginFailures(row sqlc.LoginFailure) *user.LoginFailures {
+	f := &user.LoginFailures{
+		Subject:      user.LoginSubject(row.Subject),
+		Key:          row.SubjectKey,
+		Count:        int(row.Failures),
+		LastFailedAt: row.LastFailedAt.Time,
+	}
+	if row.LockedUntil.Valid {
+		lockedUntil := row.LockedUntil.Time
+		f.LockedUntil = &lockedUntil
+	}
+	return f
+}
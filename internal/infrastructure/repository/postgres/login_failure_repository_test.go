@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginFailureRepository_ErrorTranslation(t *testing.T) {
+	t.Run("get without failures", func(t *testing.T) {
+		repo := &LoginFailureRepository{queries: sqlc.New(&fakeDB{err: pgx.ErrNoRows})}
+		failures, err := repo.Get(context.Background(), user.LoginSubjectIP, "192.0.2.1")
+		require.NoError(t, err)
+		assert.Equal(t, user.LoginSubjectIP, failures.Subject)
+		assert.Equal(t, "192.0.2.1", failures.Key)
+		assert.Zero(t, failures.Count)
+		assert.Nil(t, failures.LockedUntil)
+	})
+
+	t.Run("record with broken connection", func(t *testing.T) {
+		repo := &LoginFailureRepository{queries: sqlc.New(&fakeDB{err: &pgconn.PgError{Code: "08006"}})}
+		_, err := repo.RecordFailure(context.Background(), user.LoginSubjectEmail, "test@example.com", time.Now(), time.Now().Add(-time.Hour))
+		assert.ErrorIs(t, err, user.ErrStorageUnavailable)
+	})
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+)
+
+// MagicLinkRepository implements user.MagicLinkRepository interface using PostgreSQL
+type MagicLinkRepository struct {
+	queries *sqlc.Queries
+}
+
+// NewMagicLinkRepository creates a new PostgreSQL magic link token repository
+func NewMagicLinkRepository(db *pgxpool.Pool) *MagicLinkRepository {
+	return &MagicLinkRepository{
+		queries: sqlc.New(db),
+	}
+}
+
+// Create inserts a new magic link token into the database
+func (r *MagicLinkRepository) Create(ctx context.Context, t *user.MagicLinkToken) error {
+	params := sqlc.CreateMagicLinkTokenParams{
+		TokenHash: t.TokenHash,
+		UserID:    t.UserID,
+		CreatedAt: toTimestamp(t.CreatedAt),
+		ExpiresAt: toTimestamp(t.ExpiresAt),
+	}
+
+	if _, err := r.queries.CreateMagicLinkToken(ctx, params); err != nil {
+		if isForeignKeyViolation(err) {
+			return user.ErrUserNotFound
+		}
+		return translateError("create magic link token", err)
+	}
+
+	return nil
+}
+
+// GetByHash retrieves a magic link token by the hash of its value
+func (r *MagicLinkRepository) GetByHash(ctx context.Context, tokenHash string) (*user.MagicLinkToken, error) {
+	row, err := r.queries.GetMagicLinkToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrInvalidMagicLink
+		}
+		return nil, translateError("get magic link token", err)
+	}
+
+	t := &user.MagicLinkToken{
+		TokenHash: row.TokenHash,
+		UserID:    row.UserID,
+		CreatedAt: row.CreatedAt.Time,
+		ExpiresAt: row.ExpiresAt.Time,
+	}
+	if row.UsedAt.Valid {
+		usedAt := row.UsedAt.Time
+		t.UsedAt = &usedAt
+	}
+	return t, nil
+}
+
+// Consume marks the token used and invalidates the user's other outstanding tokens
+func (r *MagicLinkRepository) Consume(ctx context.Context, t *user.MagicLinkToken, at time.Time) error {
+	rows, err := r.queries.UseMagicLinkToken(ctx, sqlc.UseMagicLinkTokenParams{
+		TokenHash: t.TokenHash,
+		UsedAt:    toTimestamp(at),
+	})
+	if err != nil {
+		return translateError("use magic link token", err)
+	}
+	if rows == 0 {
+		return user.ErrInvalidMagicLink
+	}
+
+	if _, err := r.queries.InvalidateMagicLinkTokens(ctx, sqlc.InvalidateMagicLinkTokensParams{
+		UserID: t.UserID,
+		UsedAt: toTimestamp(at),
+	}); err != nil {
+		return translateError("invalidate magic link tokens", err)
+	}
+
+	t.UsedAt = &at
+	return nil
+}
+
+// RecordRequest counts a token request of the user in a single upsert, so
+// that concurrent requests cannot exceed the rate limit
+func (r *MagicLinkRepository) RecordRequest(ctx context.Context, userID string, at, windowStart time.Time) (int, error) {
+	requests, err := r.queries.RecordMagicLinkRequest(ctx, sqlc.RecordMagicLinkRequestParams{
+		UserID:      userID,
+		RequestedAt: toTimestamp(at),
+		WindowStart: toTimestamp(windowStart),
+	})
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return 0, user.ErrUserNotFound
+		}
+		return 0, translateError("record magic link request", err)
+	}
+	return int(requests), nil
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMagicLinkRepository_ErrorTranslation(t *testing.T) {
+	token := &user.MagicLinkToken{TokenHash: "hash", UserID: "user-1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	t.Run("get unknown token", func(t *testing.T) {
+		repo := &MagicLinkRepository{queries: sqlc.New(&fakeDB{err: pgx.ErrNoRows})}
+		_, err := repo.GetByHash(context.Background(), "unknown")
+		assert.ErrorIs(t, err, user.ErrInvalidMagicLink)
+	})
+
+	t.Run("create for missing user", func(t *testing.T) {
+		repo := &MagicLinkRepository{queries: sqlc.New(&fakeDB{err: &pgconn.PgError{Code: "23503"}})}
+		assert.ErrorIs(t, repo.Create(context.Background(), token), user.ErrUserNotFound)
+	})
+
+	t.Run("consume already used token", func(t *testing.T) {
+		repo := &MagicLinkRepository{queries: sqlc.New(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 0")})}
+		assert.ErrorIs(t, repo.Consume(context.Background(), token, time.Now()), user.ErrInvalidMagicLink)
+		assert.Nil(t, token.UsedAt)
+	})
+
+	t.Run("consume usable token", func(t *testing.T) {
+		repo := &MagicLinkRepository{queries: sqlc.New(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 1")})}
+		assert.NoError(t, repo.Consume(context.Background(), token, time.Now()))
+		assert.NotNil(t, token.UsedAt)
+	})
+
+	t.Run("record request when database is unreachable", func(t *testing.T) {
+		repo := &MagicLinkRepository{queries: sqlc.New(&fakeDB{err: &pgconn.PgError{Code: "08006"}})}
+		_, err := repo.RecordRequest(context.Background(), "user-1", time.Now(), time.Now().Add(-time.Hour))
+		assert.ErrorIs(t, err, user.ErrStorageUnavailable)
+	})
+
+	t.Run("record request", func(t *testing.T) {
+		repo := &MagicLinkRepository{queries: sqlc.New(&fakeDB{row: []any{int32(2)}})}
+		requests, err := repo.RecordRequest(context.Background(), "user-1", time.Now(), time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, requests)
+	})
+}
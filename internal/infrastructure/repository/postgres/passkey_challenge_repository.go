@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+)
+
+// PasskeyChallengeRepository implements user.PasskeyChallengeRepository interface using PostgreSQL
+type PasskeyChallengeRepository struct {
+	queries *sqlc.Queries
+}
+
+// NewPasskeyChallengeRepository creates a new PostgreSQL passkey challenge repository
+func NewPasskeyChallengeRepository(db *pgxpool.Pool) *PasskeyChallengeRepository {
+	return &PasskeyChallengeRepository{
+		queries: sqlc.New(db),
+	}
+}
+
+// Create inserts a new passkey challenge into the database
+func (r *PasskeyChallengeRepository) Create(ctx context.Context, c *user.PasskeyChallenge) error {
+	params := sqlc.CreatePasskeyChallengeParams{
+		ChallengeHash: c.ChallengeHash,
+		UserID:        pgtype.Text{String: c.UserID, Valid: c.UserID != ""},
+		Ceremony:      string(c.Ceremony),
+		CreatedAt:     toTimestamp(c.CreatedAt),
+		ExpiresAt:     toTimestamp(c.ExpiresAt),
+	}
+
+	if _, err := r.queries.CreatePasskeyChallenge(ctx, params); err != nil {
+		if isForeignKeyViolation(err) {
+			return user.ErrUserNotFound
+		}
+		return translateError("create passkey challenge", err)
+	}
+
+	return nil
+}
+
+// GetByHash retrieves a passkey challenge by the hash of its value
+func (r *PasskeyChallengeRepository) GetByHash(ctx context.Context, challengeHash string) (*user.PasskeyChallenge, error) {
+	row, err := r.queries.GetPasskeyChallenge(ctx, challengeHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrInvalidPasskeyChallenge
+		}
+		return nil, translateError("get passkey challenge", err)
+	}
+
+	c := &user.PasskeyChallenge{
+		ChallengeHash: row.ChallengeHash,
+		UserID:        row.UserID.String,
+		Ceremony:      user.PasskeyCeremony(row.Ceremony),
+		CreatedAt:     row.CreatedAt.Time,
+		ExpiresAt:     row.ExpiresAt.Time,
+	}
+	if row.UsedAt.Valid {
+		usedAt := row.UsedAt.Time
+		c.UsedAt = &usedAt
+	}
+	return c, nil
+}
+
+// Consume marks the challenge used
+func (r *PasskeyChallengeRepository) Consume(ctx context.Context, c *user.PasskeyChallenge, at time.Time) error {
+	rows, err := r.queries.UsePasskeyChallenge(ctx, sqlc.UsePasskeyChallengeParams{
+		ChallengeHash: c.ChallengeHash,
+		UsedAt:        toTimestamp(at),
+	})
+	if err != nil {
+		return translateError("use passkey challenge", err)
+	}
+	if rows == 0 {
+		return user.ErrInvalidPasskeyChallenge
+	}
+
+	c.UsedAt = &at
+	return nil
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+)
+
+// PasskeyRepository implements user.PasskeyRepository interface using PostgreSQL
+type PasskeyRepository struct {
+	queries *sqlc.Queries
+}
+
+// NewPasskeyRepository creates a new PostgreSQL passkey repository
+func NewPasskeyRepository(db *pgxpool.Pool) *PasskeyRepository {
+	return &PasskeyRepository{
+		queries: sqlc.New(db),
+	}
+}
+
+// Create inserts a new passkey into the database
+func (r *PasskeyRepository) Create(ctx context.Context, p *user.Passkey) error {
+	params := sqlc.CreatePasskeyParams{
+		ID:           p.ID,
+		UserID:       p.UserID,
+		Name:         p.Name,
+		CredentialID: p.CredentialID,
+		PublicKey:    p.PublicKey,
+		SignCount:    int64(p.SignCount),
+		Transports:   p.Transports,
+		CreatedAt:    toTimestamp(p.CreatedAt),
+	}
+	if params.Transports == nil {
+		params.Transports = []string{}
+	}
+
+	if _, err := r.queries.CreatePasskey(ctx, params); err != nil {
+		if isUniqueViolation(err) {
+			return user.ErrPasskeyAlreadyRegistered
+		}
+		if isForeignKeyViolation(err) {
+			return user.ErrUserNotFound
+		}
+		return translateError("create passkey", err)
+	}
+
+	return nil
+}
+
+// GetByCredentialID retrieves a passkey by the ID of its WebAuthn credential
+func (r *PasskeyRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*user.Passkey, error) {
+	row, err := r.queries.GetPasskeyByCredentialID(ctx, credentialID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrPasskeyNotFound
+		}
+		return nil, translateError("get passkey", err)
+	}
+	return toDomainPasskey(row), nil
+}
+
+// ListByUser retrieves the passkeys of a user, oldest first
+func (r *PasskeyRepository) ListByUser(ctx context.Context, userID string) ([]*user.Passkey, error) {
+	rows, err := r.queries.ListUserPasskeys(ctx, userID)
+	if err != nil {
+		return nil, translateError("list passkeys", err)
+	}
+
+	passkeys := make([]*user.Passkey, len(rows))
+	for i, row := range rows {
+		passkeys[i] = toDomainPasskey(row)
+	}
+	return passkeys, nil
+}
+
+// Use stores the signature counter and last use of a passkey
+func (r *PasskeyRepository) Use(ctx context.Context, p *user.Passkey) error {
+	params := sqlc.UsePasskeyParams{
+		ID:        p.ID,
+		SignCount: int64(p.SignCount),
+	}
+	if p.LastUsedAt != nil {
+		params.LastUsedAt = toTimestamp(*p.LastUsedAt)
+	}
+
+	rows, err := r.queries.UsePasskey(ctx, params)
+	if err != nil {
+		return translateError("use passkey", err)
+	}
+	if rows == 0 {
+		return user.ErrPasskeyNotFound
+	}
+	return nil
+}
+
+func toDomainPasskey(row sqlc.Passkey) *user.Passkey {
+	p := &user.Passkey{
+		ID:           row.ID,
+		UserID:       row.UserID,
+		Name:         row.Name,
+		CredentialID: row.CredentialID,
+		PublicKey:    row.PublicKey,
+		SignCount:    uint32(row.SignCount),
+		Transports:   row.Transports,
+		CreatedAt:    row.CreatedAt.Time,
+	}
+	if row.LastUsedAt.Valid {
+		lastUsedAt := row.LastUsedAt.Time
+		p.LastUsedAt = &lastUsedAt
+	}
+	return p
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasskeyRepository_ErrorTranslation(t *testing.T) {
+	passkey := &user.Passkey{ID: "passkey-1", UserID: "user-1", CredentialID: []byte{1}, PublicKey: []byte{2}}
+
+	t.Run("get unknown credential", func(t *testing.T) {
+		repo := &PasskeyRepository{queries: sqlc.New(&fakeDB{err: pgx.ErrNoRows})}
+		_, err := repo.GetByCredentialID(context.Background(), []byte{9})
+		assert.ErrorIs(t, err, user.ErrPasskeyNotFound)
+	})
+
+	t.Run("create duplicate credential", func(t *testing.T) {
+		repo := &PasskeyRepository{queries: sqlc.New(&fakeDB{err: &pgconn.PgError{Code: "23505"}})}
+		err := repo.Create(context.Background(), passkey)
+		assert.ErrorIs(t, err, user.ErrPasskeyAlreadyRegistered)
+		assert.NotErrorIs(t, err, user.ErrUserAlreadyExists)
+	})
+
+	t.Run("create for missing user", func(t *testing.T) {
+		repo := &PasskeyRepository{queries: sqlc.New(&fakeDB{err: &pgconn.PgError{Code: "23503"}})}
+		assert.ErrorIs(t, repo.Create(context.Background(), passkey), user.ErrUserNotFound)
+	})
+
+	t.Run("use deleted passkey", func(t *testing.T) {
+		repo := &PasskeyRepository{queries: sqlc.New(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 0")})}
+		assert.ErrorIs(t, repo.Use(context.Background(), passkey), user.ErrPasskeyNotFound)
+	})
+}
+
+func TestPasskeyChallengeRepository_ErrorTranslation(t *testing.T) {
+	challenge := user.NewPasskeyChallenge("hash", "", user.PasskeyLogin, time.Minute)
+
+	t.Run("get unknown challenge", func(t *testing.T) {
+		repo := &PasskeyChallengeRepository{queries: sqlc.New(&fakeDB{err: pgx.ErrNoRows})}
+		_, err := repo.GetByHash(context.Background(), "unknown")
+		assert.ErrorIs(t, err, user.ErrInvalidPasskeyChallenge)
+	})
+
+	t.Run("consume used challenge", func(t *testing.T) {
+		repo := &PasskeyChallengeRepository{queries: sqlc.New(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 0")})}
+		assert.ErrorIs(t, repo.Consume(context.Background(), challenge, time.Now()), user.ErrInvalidPasskeyChallenge)
+		assert.Nil(t, challenge.UsedAt)
+	})
+}
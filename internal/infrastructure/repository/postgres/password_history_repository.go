@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+)
+
+// PasswordHistoryRepository implements user.PasswordHistoryRepository interface using PostgreSQL
+type PasswordHistoryRepository struct {
+	queries *sqlc.Queries
+}
+
+// NewPasswordHistoryRepository creates a new PostgreSQL password history repository
+func NewPasswordHistoryRepository(db *pgxpool.Pool) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{
+		queries: sqlc.New(db),
+	}
+}
+
+// Add records a replaced password hash and removes the user's hashes beyond the newest keep
+func (r *PasswordHistoryRepository) Add(ctx context.Context, userID, hash string, at time.Time, keep int) error {
+	if err := r.queries.AddPasswordHistory(ctx, sqlc.AddPasswordHistoryParams{
+		UserID:       userID,
+		PasswordHash: hash,
+		CreatedAt:    toTimestamp(at),
+	}); err != nil {
+		if isForeignKeyViolation(err) {
+			return user.ErrUserNotFound
+		}
+		return translateError("add password history", err)
+	}
+
+	if _, err := r.queries.PrunePasswordHistory(ctx, sqlc.PrunePasswordHistoryParams{
+		UserID: userID,
+		Limit:  int32(keep),
+	}); err != nil {
+		return translateError("prune password history", err)
+	}
+
+	return nil
+}
+
+// ListRecent retrieves the user's newest replaced password hashes
+func (r *PasswordHistoryRepository) ListRecent(ctx context.Context, userID string, limit int) ([]string, error) {
+	hashes, err := r.queries.ListPasswordHistory(ctx, sqlc.ListPasswordHistoryParams{
+		UserID: userID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, translateError("list password history", err)
+	}
+	return hashes, nil
+}
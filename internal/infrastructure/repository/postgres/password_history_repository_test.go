@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordHistoryRepository_ErrorTranslation(t *testing.T) {
+	t.Run("add for missing user", func(t *testing.T) {
+		repo := &PasswordHistoryRepository{queries: sqlc.New(&fakeDB{err: &pgconn.PgError{Code: "23503"}})}
+		err := repo.Add(context.Background(), "missing", "hash", time.Now(), 4)
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+	})
+
+	t.Run("list with broken connection", func(t *testing.T) {
+		repo := &PasswordHistoryRepository{queries: sqlc.New(&fakeDB{err: &pgconn.PgError{Code: "08006"}})}
+		_, err := repo.ListRecent(context.Background(), "user-1", 4)
+		assert.ErrorIs(t, err, user.ErrStorageUnavailable)
+	})
+}
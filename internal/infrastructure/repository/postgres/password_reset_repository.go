@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+)
+
+// PasswordResetRepository implements user.PasswordResetRepository interface using PostgreSQL
+type PasswordResetRepository struct {
+	queries *sqlc.Queries
+}
+
+// NewPasswordResetRepository creates a new PostgreSQL password reset token repository
+func NewPasswordResetRepository(db *pgxpool.Pool) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		queries: sqlc.New(db),
+	}
+}
+
+// Create inserts a new password reset token into the database
+func (r *PasswordResetRepository) Create(ctx context.Context, t *user.PasswordResetToken) error {
+	params := sqlc.CreatePasswordResetTokenParams{
+		TokenHash: t.TokenHash,
+		UserID:    t.UserID,
+		CreatedAt: toTimestamp(t.CreatedAt),
+		ExpiresAt: toTimestamp(t.ExpiresAt),
+	}
+
+	if _, err := r.queries.CreatePasswordResetToken(ctx, params); err != nil {
+		if isForeignKeyViolation(err) {
+			return user.ErrUserNotFound
+		}
+		return translateError("create password reset token", err)
+	}
+
+	return nil
+}
+
+// GetByHash retrieves a password reset token by the hash of its value
+func (r *PasswordResetRepository) GetByHash(ctx context.Context, tokenHash string) (*user.PasswordResetToken, error) {
+	row, err := r.queries.GetPasswordResetToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrInvalidResetToken
+		}
+		return nil, translateError("get password reset token", err)
+	}
+
+	t := &user.PasswordResetToken{
+		TokenHash: row.TokenHash,
+		UserID:    row.UserID,
+		CreatedAt: row.CreatedAt.Time,
+		ExpiresAt: row.ExpiresAt.Time,
+	}
+	if row.UsedAt.Valid {
+		usedAt := row.UsedAt.Time
+		t.UsedAt = &usedAt
+	}
+	return t, nil
+}
+
+// Consume marks the token used and invalidates the user's other outstanding tokens
+func (r *PasswordResetRepository) Consume(ctx context.Context, t *user.PasswordResetToken, at time.Time) error {
+	rows, err := r.queries.UsePasswordResetToken(ctx, sqlc.UsePasswordResetTokenParams{
+		TokenHash: t.TokenHash,
+		UsedAt:    toTimestamp(at),
+	})
+	if err != nil {
+		return translateError("use password reset token", err)
+	}
+	if rows == 0 {
+		return user.ErrInvalidResetToken
+	}
+
+	if _, err := r.queries.InvalidatePasswordResetTokens(ctx, sqlc.InvalidatePasswordResetTokensParams{
+		UserID: t.UserID,
+		UsedAt: toTimestamp(at),
+	}); err != nil {
+		return translateError("invalidate password reset tokens", err)
+	}
+
+	t.UsedAt = &at
+	return nil
+}
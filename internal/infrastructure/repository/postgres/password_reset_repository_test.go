@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetRepository_ErrorTranslation(t *testing.T) {
+	token := &user.PasswordResetToken{TokenHash: "hash", UserID: "user-1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	t.Run("get unknown token", func(t *testing.T) {
+		repo := &PasswordResetRepository{queries: sqlc.New(&fakeDB{err: pgx.ErrNoRows})}
+		_, err := repo.GetByHash(context.Background(), "unknown")
+		assert.ErrorIs(t, err, user.ErrInvalidResetToken)
+	})
+
+	t.Run("create for missing user", func(t *testing.T) {
+		repo := &PasswordResetRepository{queries: sqlc.New(&fakeDB{err: &pgconn.PgError{Code: "23503"}})}
+		assert.ErrorIs(t, repo.Create(context.Background(), token), user.ErrUserNotFound)
+	})
+
+	t.Run("consume already used token", func(t *testing.T) {
+		repo := &PasswordResetRepository{queries: sqlc.New(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 0")})}
+		assert.ErrorIs(t, repo.Consume(context.Background(), token, time.Now()), user.ErrInvalidResetToken)
+		assert.Nil(t, token.UsedAt)
+	})
+
+	t.Run("consume usable token", func(t *testing.T) {
+		repo := &PasswordResetRepository{queries: sqlc.New(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 1")})}
+		assert.NoError(t, repo.Consume(context.Background(), token, time.Now()))
+		assert.NotNil(t, token.UsedAt)
+	})
+}
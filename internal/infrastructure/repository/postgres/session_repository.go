@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+)
+
+// SessionRepository implements user.SessionRepository interface using PostgreSQL
+type SessionRepository struct {
+	queries *sqlc.Queries
+}
+
+// NewSessionRepository creates a new PostgreSQL session repository
+func NewSessionRepository(db *pgxpool.Pool) *SessionRepository {
+	return &SessionRepository{
+		queries: sqlc.New(db),
+	}
+}
+
+// Create inserts a new session into the database
+func (r *SessionRepository) Create(ctx context.Context, s *user.Session) error {
+	params := sqlc.CreateSessionParams{
+		ID:               s.ID,
+		UserID:           s.UserID,
+		RefreshTokenHash: s.RefreshTokenHash,
+		UserAgent:        s.UserAgent,
+		IpAddress:        s.IPAddress,
+		CreatedAt:        toTimestamp(s.CreatedAt),
+		LastUsedAt:       toTimestamp(s.LastUsedAt),
+		ExpiresAt:        toTimestamp(s.ExpiresAt),
+	}
+
+	if _, err := r.queries.CreateSession(ctx, params); err != nil {
+		if isForeignKeyViolation(err) {
+			return user.ErrUserNotFound
+		}
+		return translateError("create session", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a session by its ID
+func (r *SessionRepository) GetByID(ctx context.Context, id string) (*user.Session, error) {
+	row, err := r.queries.GetSessionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrSessionNotFound
+		}
+		return nil, translateError("get session", err)
+	}
+
+	return toDomainSession(row), nil
+}
+
+// Rotate stores the new refresh token hash if the session still holds previousHash
+func (r *SessionRepository) Rotate(ctx context.Context, s *user.Session, previousHash string) error {
+	rows, err := r.queries.RotateSession(ctx, sqlc.RotateSessionParams{
+		RefreshTokenHash: s.RefreshTokenHash,
+		UserAgent:        s.UserAgent,
+		IpAddress:        s.IPAddress,
+		LastUsedAt:       toTimestamp(s.LastUsedAt),
+		ExpiresAt:        toTimestamp(s.ExpiresAt),
+		ID:               s.ID,
+		PreviousHash:     previousHash,
+	})
+	if err != nil {
+		return translateError("rotate session", err)
+	}
+	if rows == 0 {
+		return user.ErrSessionNotFound
+	}
+	return nil
+}
+
+// ListActive retrieves the user's sessions that are neither revoked nor expired at now
+func (r *SessionRepository) ListActive(ctx context.Context, userID string, now time.Time) ([]*user.Session, error) {
+	rows, err := r.queries.ListActiveSessions(ctx, sqlc.ListActiveSessionsParams{
+		UserID:    userID,
+		ExpiresAt: toTimestamp(now),
+	})
+	if err != nil {
+		return nil, translateError("list sessions", err)
+	}
+
+	sessions := make([]*user.Session, len(rows))
+	for i, row := range rows {
+		sessions[i] = toDomainSession(row)
+	}
+	return sessions, nil
+}
+
+// Revoke marks a single active session of the user as revoked
+func (r *SessionRepository) Revoke(ctx context.Context, userID, sessionID string, at time.Time) error {
+	rows, err := r.queries.RevokeSession(ctx, sqlc.RevokeSessionParams{
+		ID:        sessionID,
+		UserID:    userID,
+		RevokedAt: toTimestamp(at),
+	})
+	if err != nil {
+		return translateError("revoke session", err)
+	}
+	if rows == 0 {
+		return user.ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeAll marks every active session of the user as revoked and returns how many were revoked
+func (r *SessionRepository) RevokeAll(ctx context.Context, userID string, at time.Time) (int64, error) {
+	rows, err := r.queries.RevokeUserSessions(ctx, sqlc.RevokeUserSessionsParams{
+		UserID:    userID,
+		RevokedAt: toTimestamp(at),
+	})
+	if err != nil {
+		return 0, translateError("revoke sessions", err)
+	}
+	return rows, nil
+}
+
+func toDomainSession(row sqlc.Session) *user.Session {
+	s := &user.Session{
+		ID:               row.ID,
+		UserID:           row.UserID,
+		RefreshTokenHash: row.RefreshTokenHash,
+		UserAgent:        row.UserAgent,
+		IPAddress:        row.IpAddress,
+		CreatedAt:        row.CreatedAt.Time,
+		LastUsedAt:       row.LastUsedAt.Time,
+		ExpiresAt:        row.ExpiresAt.Time,
+	}
+	if row.RevokedAt.Valid {
+		revokedAt := row.RevokedAt.Time
+		s.RevokedAt = &revokedAt
+	}
+	return s
+}
+
+func toTimestamp(t time.Time) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: t, Valid: true}
+}
+
+// toNullTimestamp converts an optional time, storing nil as NULL
+func toNullTimestamp(t *time.Time) pgtype.Timestamp {
+	if t == nil {
+		return pgtype.Timestamp{}
+	}
+	return toTimestamp(*t)
+}
+
+// fromNullTimestamp converts a nullable column into an optional time
+func fromNullTimestamp(ts pgtype.Timestamp) *time.Time {
+	if !ts.Valid {
+		return nil
+	}
+	t := ts.Time
+	return &t
+}
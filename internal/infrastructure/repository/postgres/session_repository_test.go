@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionRepository_ErrorTranslation(t *testing.T) {
+	s := &user.Session{ID: "session-1", UserID: "user-1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	t.Run("get missing session", func(t *testing.T) {
+		repo := &SessionRepository{queries: sqlc.New(&fakeDB{err: pgx.ErrNoRows})}
+		_, err := repo.GetByID(context.Background(), "missing")
+		assert.ErrorIs(t, err, user.ErrSessionNotFound)
+	})
+
+	t.Run("create for missing user", func(t *testing.T) {
+		repo := &SessionRepository{queries: sqlc.New(&fakeDB{err: &pgconn.PgError{Code: "23503"}})}
+		assert.ErrorIs(t, repo.Create(context.Background(), s), user.ErrUserNotFound)
+	})
+
+	t.Run("rotate with stale token hash", func(t *testing.T) {
+		repo := &SessionRepository{queries: sqlc.New(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 0")})}
+		assert.ErrorIs(t, repo.Rotate(context.Background(), s, "stale"), user.ErrSessionNotFound)
+	})
+
+	t.Run("revoke already revoked session", func(t *testing.T) {
+		repo := &SessionRepository{queries: sqlc.New(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 0")})}
+		err := repo.Revoke(context.Background(), "user-1", "session-1", time.Now())
+		assert.ErrorIs(t, err, user.ErrSessionNotFound)
+	})
+
+	t.Run("revoke all with broken connection", func(t *testing.T) {
+		repo := &SessionRepository{queries: sqlc.New(&fakeDB{err: &pgconn.PgError{Code: "08006"}})}
+		_, err := repo.RevokeAll(context.Background(), "user-1", time.Now())
+		assert.ErrorIs(t, err, user.ErrStorageUnavailable)
+	})
+}
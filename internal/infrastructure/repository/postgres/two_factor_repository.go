@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+)
+
+// SecretCipher encrypts secrets before they are stored. The associated data
+// binds a ciphertext to its row, so that it does not decrypt when copied.
+type SecretCipher interface {
+	Encrypt(plaintext, associatedData []byte) (string, error)
+	Decrypt(ciphertext string, associatedData []byte) ([]byte, error)
+}
+
+// TwoFactorRepository implements user.TwoFactorRepository interface using PostgreSQL
+type TwoFactorRepository struct {
+	queries *sqlc.Queries
+	cipher  SecretCipher
+}
+
+// NewTwoFactorRepository creates a new PostgreSQL two-factor repository that
+// encrypts TOTP secrets with cipher
+func NewTwoFactorRepository(db *pgxpool.Pool, cipher SecretCipher) *TwoFactorRepository {
+	return &TwoFactorRepository{
+		queries: sqlc.New(db),
+		cipher:  cipher,
+	}
+}
+
+// GetTOTP retrieves and decrypts the TOTP secret of a user
+func (r *TwoFactorRepository) GetTOTP(ctx context.Context, userID string) (*user.TOTP, error) {
+	row, err := r.queries.GetUserTOTP(ctx, userID)
+	if err != nil {
+		return nil, translateError("get totp secret", err)
+	}
+	if !row.TotpSecret.Valid {
+		return nil, user.ErrTwoFactorNotEnrolled
+	}
+
+	secret, err := r.cipher.Decrypt(row.TotpSecret.String, []byte(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt totp secret: %w", err)
+	}
+
+	return &user.TOTP{
+		UserID:       userID,
+		Secret:       string(secret),
+		EnabledAt:    fromNullTimestamp(row.TotpEnabledAt),
+		LastUsedStep: row.TotpLastUsedStep,
+	}, nil
+}
+
+// SaveTOTP encrypts and stores the secret of an unconfirmed enrollment
+func (r *TwoFactorRepository) SaveTOTP(ctx context.Context, t *user.TOTP) error {
+	encrypted, err := r.cipher.Encrypt([]byte(t.Secret), []byte(t.UserID))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt totp secret: %w", err)
+	}
+
+	rows, err := r.queries.SetUserTOTPSecret(ctx, sqlc.SetUserTOTPSecretParams{
+		ID:         t.UserID,
+		TotpSecret: pgtype.Text{String: encrypted, Valid: true},
+	})
+	if err != nil {
+		return translateError("save totp secret", err)
+	}
+	if rows == 0 {
+		return user.ErrTwoFactorAlreadyEnabled
+	}
+	return nil
+}
+
+// EnableTOTP replaces the recovery codes of the user and confirms the enrollment.
+// Codes stored for an enrollment that then fails to confirm are never usable,
+// as they are only checked while two-factor authentication is enabled.
+func (r *TwoFactorRepository) EnableTOTP(ctx context.Context, t *user.TOTP, recoveryCodeHashes []string) error {
+	if err := r.queries.ReplaceRecoveryCodes(ctx, sqlc.ReplaceRecoveryCodesParams{
+		UserID:     t.UserID,
+		CodeHashes: recoveryCodeHashes,
+		CreatedAt:  toTimestamp(time.Now()),
+	}); err != nil {
+		if isForeignKeyViolation(err) {
+			return user.ErrUserNotFound
+		}
+		return translateError("replace recovery codes", err)
+	}
+
+	rows, err := r.queries.EnableUserTOTP(ctx, sqlc.EnableUserTOTPParams{
+		ID:               t.UserID,
+		TotpEnabledAt:    toNullTimestamp(t.EnabledAt),
+		TotpLastUsedStep: t.LastUsedStep,
+	})
+	if err != nil {
+		return translateError("enable totp", err)
+	}
+	if rows == 0 {
+		return user.ErrTwoFactorAlreadyEnabled
+	}
+	return nil
+}
+
+// UseTOTPStep stores the time step of an accepted code unless a code of that
+// or a later step was accepted concurrently
+func (r *TwoFactorRepository) UseTOTPStep(ctx context.Context, t *user.TOTP) error {
+	rows, err := r.queries.UseUserTOTPStep(ctx, sqlc.UseUserTOTPStepParams{
+		ID:               t.UserID,
+		TotpLastUsedStep: t.LastUsedStep,
+	})
+	if err != nil {
+		return translateError("use totp step", err)
+	}
+	if rows == 0 {
+		return user.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code used
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string, at time.Time) error {
+	rows, err := r.queries.UseRecoveryCode(ctx, sqlc.UseRecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: codeHash,
+		UsedAt:   toTimestamp(at),
+	})
+	if err != nil {
+		return translateError("use recovery code", err)
+	}
+	if rows == 0 {
+		return user.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// DisableTOTP removes the TOTP secret and the recovery codes of a user
+func (r *TwoFactorRepository) DisableTOTP(ctx context.Context, userID string) error {
+	rows, err := r.queries.DisableUserTOTP(ctx, userID)
+	if err != nil {
+		return translateError("disable totp", err)
+	}
+	if rows == 0 {
+		return user.ErrUserNotFound
+	}
+
+	if err := r.queries.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return translateError("delete recovery codes", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+	"github.com/memclutter/go-microservices-template/pkg/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactorRepository_ErrorTranslation(t *testing.T) {
+	cipher, err := encryption.NewCipher(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", encryption.KeySize))))
+	require.NoError(t, err)
+	totp := &user.TOTP{UserID: "user-1", Secret: "SECRET", LastUsedStep: 100}
+
+	newRepo := func(db *fakeDB) *TwoFactorRepository {
+		return &TwoFactorRepository{queries: sqlc.New(db), cipher: cipher}
+	}
+
+	t.Run("get for unknown user", func(t *testing.T) {
+		_, err := newRepo(&fakeDB{err: pgx.ErrNoRows}).GetTOTP(context.Background(), "missing")
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+	})
+
+	t.Run("get without secret", func(t *testing.T) {
+		_, err := newRepo(&fakeDB{}).GetTOTP(context.Background(), "user-1")
+		assert.ErrorIs(t, err, user.ErrTwoFactorNotEnrolled)
+	})
+
+	t.Run("save while enabled", func(t *testing.T) {
+		err := newRepo(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 0")}).SaveTOTP(context.Background(), totp)
+		assert.ErrorIs(t, err, user.ErrTwoFactorAlreadyEnabled)
+	})
+
+	t.Run("enable twice", func(t *testing.T) {
+		err := newRepo(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 0")}).EnableTOTP(context.Background(), totp, []string{"hash"})
+		assert.ErrorIs(t, err, user.ErrTwoFactorAlreadyEnabled)
+	})
+
+	t.Run("reuse totp step", func(t *testing.T) {
+		err := newRepo(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 0")}).UseTOTPStep(context.Background(), totp)
+		assert.ErrorIs(t, err, user.ErrInvalidTwoFactorCode)
+	})
+
+	t.Run("use unknown recovery code", func(t *testing.T) {
+		err := newRepo(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 0")}).UseRecoveryCode(context.Background(), "user-1", "hash", time.Now())
+		assert.ErrorIs(t, err, user.ErrInvalidTwoFactorCode)
+	})
+
+	t.Run("disable for unknown user", func(t *testing.T) {
+		err := newRepo(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 0")}).DisableTOTP(context.Background(), "missing")
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+	})
+}
+
+func TestLoginChallengeRepository_ErrorTranslation(t *testing.T) {
+	challenge := &user.LoginChallenge{TokenHash: "hash", UserID: "user-1", ExpiresAt: time.Now().Add(time.Minute)}
+
+	t.Run("get unknown challenge", func(t *testing.T) {
+		repo := &LoginChallengeRepository{queries: sqlc.New(&fakeDB{err: pgx.ErrNoRows})}
+		_, err := repo.GetByHash(context.Background(), "unknown")
+		assert.ErrorIs(t, err, user.ErrInvalidLoginChallenge)
+	})
+
+	t.Run("consume already used challenge", func(t *testing.T) {
+		repo := &LoginChallengeRepository{queries: sqlc.New(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 0")})}
+		assert.ErrorIs(t, repo.Consume(context.Background(), challenge, time.Now()), user.ErrInvalidLoginChallenge)
+		assert.Nil(t, challenge.UsedAt)
+	})
+
+	t.Run("consume usable challenge", func(t *testing.T) {
+		repo := &LoginChallengeRepository{queries: sqlc.New(&fakeDB{tag: pgconn.NewCommandTag("UPDATE 1")})}
+		assert.NoError(t, repo.Consume(context.Background(), challenge, time.Now()))
+		assert.NotNil(t, challenge.UsedAt)
+	})
+}
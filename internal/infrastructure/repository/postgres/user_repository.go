@@ -2,9 +2,7 @@ package postgres
 
 import (
 	"context"
-	"database/sql"
 	"errors"
-	"fmt"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -36,13 +34,18 @@ func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 		Password:  u.Password,
 		CreatedAt: pgtype.Timestamp{Time: u.CreatedAt, Valid: true},
 		UpdatedAt: pgtype.Timestamp{Time: u.UpdatedAt, Valid: true},
+		Roles:     u.RoleNames(),
 	}
 
-	_, err := r.queries.CreateUser(ctx, params)
+	row, err := r.queries.CreateUser(ctx, params)
 	if err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
+		return translateError("create user", err)
 	}
 
+	// Read back what was stored, which has the precision of the database
+	u.CreatedAt = row.CreatedAt.Time
+	u.UpdatedAt = row.UpdatedAt.Time
+	u.Version = row.Version
 	return nil
 }
 
@@ -50,70 +53,151 @@ func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*user.User, error) {
 	row, err := r.queries.GetUserByID(ctx, id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, user.ErrUserNotFound
-		}
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, translateError("get user", err)
 	}
 
-	return &user.User{
-		ID:        row.ID,
-		Email:     row.Email,
-		Name:      row.Name,
-		Password:  row.Password,
-		CreatedAt: row.CreatedAt.Time,
-		UpdatedAt: row.UpdatedAt.Time,
-	}, nil
+	return toDomainUser(row), nil
+}
+
+// GetByIDs retrieves the users with the given IDs in no particular order,
+// leaving out IDs without a user
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []string) ([]*user.User, error) {
+	rows, err := r.queries.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, translateError("get users", err)
+	}
+
+	return toDomainUsers(rows), nil
 }
 
 // GetByEmail retrieves a user by their email
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	row, err := r.queries.GetUserByEmail(ctx, email)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, user.ErrUserNotFound
-		}
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, translateError("get user", err)
 	}
 
-	return &user.User{
-		ID:        row.ID,
-		Email:     row.Email,
-		Name:      row.Name,
-		Password:  row.Password,
-		CreatedAt: row.CreatedAt.Time,
-		UpdatedAt: row.UpdatedAt.Time,
-	}, nil
+	return toDomainUser(row), nil
 }
 
-// Update updates an existing user
+// Update updates an existing user if it was not changed since it was read
 func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 	params := sqlc.UpdateUserParams{
 		ID:        u.ID,
 		Name:      u.Name,
 		UpdatedAt: pgtype.Timestamp{Time: u.UpdatedAt, Valid: true},
+		Version:   u.Version,
 	}
 
-	_, err := r.queries.UpdateUser(ctx, params)
+	row, err := r.queries.UpdateUser(ctx, params)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return r.missingOrChanged(ctx, "update user", u.ID)
+	}
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return user.ErrUserNotFound
-		}
-		return fmt.Errorf("failed to update user: %w", err)
+		return translateError("update user", err)
+	}
+
+	// The updated_at trigger sets the time of the write
+	u.UpdatedAt = row.UpdatedAt.Time
+	u.Version = row.Version
+	return nil
+}
+
+// UpdateRoles replaces the roles of an existing user
+func (r *UserRepository) UpdateRoles(ctx context.Context, u *user.User) error {
+	params := sqlc.UpdateUserRolesParams{
+		ID:        u.ID,
+		Roles:     u.RoleNames(),
+		UpdatedAt: pgtype.Timestamp{Time: u.UpdatedAt, Valid: true},
+	}
+
+	row, err := r.queries.UpdateUserRoles(ctx, params)
+	if err != nil {
+		return translateError("update user roles", err)
+	}
+
+	// The updated_at trigger sets the time of the write
+	u.UpdatedAt = row.UpdatedAt.Time
+	u.Version = row.Version
+	return nil
+}
+
+// UpdatePassword replaces the password hash of an existing user
+func (r *UserRepository) UpdatePassword(ctx context.Context, u *user.User) error {
+	params := sqlc.UpdateUserPasswordParams{
+		ID:        u.ID,
+		Password:  u.Password,
+		UpdatedAt: pgtype.Timestamp{Time: u.UpdatedAt, Valid: true},
+	}
+
+	row, err := r.queries.UpdateUserPassword(ctx, params)
+	if err != nil {
+		return translateError("update user password", err)
+	}
+
+	// The updated_at trigger sets the time of the write
+	u.UpdatedAt = row.UpdatedAt.Time
+	u.Version = row.Version
+	return nil
+}
+
+// UpdateEmail saves the email address, its verification state and the pending email address
+func (r *UserRepository) UpdateEmail(ctx context.Context, u *user.User) error {
+	params := sqlc.UpdateUserEmailParams{
+		ID:              u.ID,
+		Email:           u.Email,
+		EmailVerifiedAt: toNullTimestamp(u.EmailVerifiedAt),
+		PendingEmail:    pgtype.Text{String: u.PendingEmail, Valid: u.PendingEmail != ""},
+		UpdatedAt:       pgtype.Timestamp{Time: u.UpdatedAt, Valid: true},
+	}
+
+	row, err := r.queries.UpdateUserEmail(ctx, params)
+	if err != nil {
+		return translateError("update user email", err)
 	}
 
+	// The updated_at trigger sets the time of the write
+	u.UpdatedAt = row.UpdatedAt.Time
+	u.Version = row.Version
 	return nil
 }
 
-// Delete removes a user by ID
-func (r *UserRepository) Delete(ctx context.Context, id string) error {
-	err := r.queries.DeleteUser(ctx, id)
+// EmailExists reports whether email is taken as an address or a pending address
+func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	exists, err := r.queries.EmailExists(ctx, email)
 	if err != nil {
-		return fmt.Errorf("failed to delete user: %w", err)
+		return false, translateError("check email", err)
+	}
+	return exists, nil
+}
+
+// Delete removes a user by ID, at the given version unless it is zero
+func (r *UserRepository) Delete(ctx context.Context, id string, version int64) error {
+	rows, err := r.queries.DeleteUser(ctx, sqlc.DeleteUserParams{
+		ID:      id,
+		Version: version,
+	})
+	if err != nil {
+		return translateError("delete user", err)
+	}
+	if rows == 0 {
+		if version == 0 {
+			return user.ErrUserNotFound
+		}
+		return r.missingOrChanged(ctx, "delete user", id)
 	}
 	return nil
 }
 
+// missingOrChanged tells why a write conditional on the version of a user
+// matched no row: ErrUserNotFound if the user is gone, ErrVersionMismatch otherwise
+func (r *UserRepository) missingOrChanged(ctx context.Context, op, id string) error {
+	if _, err := r.queries.GetUserByID(ctx, id); err != nil {
+		return translateError(op, err)
+	}
+	return user.ErrVersionMismatch
+}
+
 // List retrieves users with pagination
 func (r *UserRepository) List(ctx context.Context, limit, offset int32) ([]*user.User, error) {
 	rows, err := r.queries.ListUsers(ctx, sqlc.ListUsersParams{
@@ -121,20 +205,66 @@ func (r *UserRepository) List(ctx context.Context, limit, offset int32) ([]*user
 		Offset: int32(offset),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list users: %w", err)
+		return nil, translateError("list users", err)
+	}
+
+	return toDomainUsers(rows), nil
+}
+
+// ListAfter retrieves users that come after the cursor in (created_at, id) order
+func (r *UserRepository) ListAfter(ctx context.Context, cursor user.PageCursor, limit int32) ([]*user.User, error) {
+	rows, err := r.queries.ListUsersAfter(ctx, sqlc.ListUsersAfterParams{
+		CursorCreatedAt: pgtype.Timestamp{Time: cursor.CreatedAt, Valid: true},
+		CursorID:        cursor.ID,
+		PageSize:        limit,
+	})
+	if err != nil {
+		return nil, translateError("list users", err)
 	}
 
+	return toDomainUsers(rows), nil
+}
+
+// Count returns the total number of users
+func (r *UserRepository) Count(ctx context.Context) (int64, error) {
+	count, err := r.queries.CountUsers(ctx)
+	if err != nil {
+		return 0, translateError("count users", err)
+	}
+	return count, nil
+}
+
+func toDomainUser(row sqlc.User) *user.User {
+	return &user.User{
+		ID:        row.ID,
+		Email:     row.Email,
+		Name:      row.Name,
+		Password:  row.Password,
+		Roles:     toDomainRoles(row.Roles),
+		CreatedAt: row.CreatedAt.Time,
+		UpdatedAt: row.UpdatedAt.Time,
+
+		EmailVerifiedAt: fromNullTimestamp(row.EmailVerifiedAt),
+		PendingEmail:    row.PendingEmail.String,
+
+		TwoFactorEnabledAt: fromNullTimestamp(row.TotpEnabledAt),
+
+		Version: row.Version,
+	}
+}
+
+func toDomainRoles(names []string) []user.Role {
+	roles := make([]user.Role, len(names))
+	for i, name := range names {
+		roles[i] = user.Role(name)
+	}
+	return roles
+}
+
+func toDomainUsers(rows []sqlc.User) []*user.User {
 	users := make([]*user.User, len(rows))
 	for i, row := range rows {
-		users[i] = &user.User{
-			ID:        row.ID,
-			Email:     row.Email,
-			Name:      row.Name,
-			Password:  row.Password,
-			CreatedAt: row.CreatedAt.Time,
-			UpdatedAt: row.UpdatedAt.Time,
-		}
+		users[i] = toDomainUser(row)
 	}
-
-	return users, nil
+	return users
 }
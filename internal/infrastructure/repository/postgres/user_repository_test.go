@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/internal/infrastructure/repository/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDB is a sqlc.DBTX that fails every query with err, or returns row
+// from QueryRow when err is nil
+type fakeDB struct {
+	err error
+	tag pgconn.CommandTag
+	row []any
+}
+
+func (f *fakeDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return f.tag, f.err
+}
+
+func (f *fakeDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, f.err
+}
+
+func (f *fakeDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return fakeRow{err: f.err, values: f.row}
+}
+
+type fakeRow struct {
+	err    error
+	values []any
+}
+
+// Scan copies the non-nil values into the destinations in column order
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, v := range r.values {
+		if v != nil {
+			reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+		}
+	}
+	return nil
+}
+
+func newTestRepository(db sqlc.DBTX) *UserRepository {
+	return &UserRepository{queries: sqlc.New(db)}
+}
+
+func TestUserRepository_ErrorTranslation(t *testing.T) {
+	u := &user.User{ID: "user-1", Email: "test@example.com", Name: "Test", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+
+	uniqueViolation := &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"}
+	connectionFailure := &pgconn.PgError{Code: "08006"}
+	tooManyConnections := &pgconn.PgError{Code: "53300"}
+	syntaxError := &pgconn.PgError{Code: "42601"}
+
+	tests := []struct {
+		name    string
+		dbErr   error
+		call    func(*UserRepository) error
+		wantErr error
+	}{
+		{
+			name:    "create with duplicate email",
+			dbErr:   uniqueViolation,
+			call:    func(r *UserRepository) error { return r.Create(context.Background(), u) },
+			wantErr: user.ErrUserAlreadyExists,
+		},
+		{
+			name:  "get by id without rows",
+			dbErr: pgx.ErrNoRows,
+			call: func(r *UserRepository) error {
+				_, err := r.GetByID(context.Background(), "missing")
+				return err
+			},
+			wantErr: user.ErrUserNotFound,
+		},
+		{
+			name:  "get by email without rows",
+			dbErr: pgx.ErrNoRows,
+			call: func(r *UserRepository) error {
+				_, err := r.GetByEmail(context.Background(), "missing@example.com")
+				return err
+			},
+			wantErr: user.ErrUserNotFound,
+		},
+		{
+			name:    "update without rows",
+			dbErr:   pgx.ErrNoRows,
+			call:    func(r *UserRepository) error { return r.Update(context.Background(), u) },
+			wantErr: user.ErrUserNotFound,
+		},
+		{
+			name:  "get by id with broken connection",
+			dbErr: connectionFailure,
+			call: func(r *UserRepository) error {
+				_, err := r.GetByID(context.Background(), "user-1")
+				return err
+			},
+			wantErr: user.ErrStorageUnavailable,
+		},
+		{
+			name:  "get by ids with broken connection",
+			dbErr: connectionFailure,
+			call: func(r *UserRepository) error {
+				_, err := r.GetByIDs(context.Background(), []string{"user-1", "user-2"})
+				return err
+			},
+			wantErr: user.ErrStorageUnavailable,
+		},
+		{
+			name:  "list with too many connections",
+			dbErr: tooManyConnections,
+			call: func(r *UserRepository) error {
+				_, err := r.List(context.Background(), 10, 0)
+				return err
+			},
+			wantErr: user.ErrStorageUnavailable,
+		},
+		{
+			name:  "count with connect error",
+			dbErr: &pgconn.ConnectError{},
+			call: func(r *UserRepository) error {
+				_, err := r.Count(context.Background())
+				return err
+			},
+			wantErr: user.ErrStorageUnavailable,
+		},
+		{
+			name:    "update email to a pending address of another user",
+			dbErr:   &pgconn.PgError{Code: "23505", ConstraintName: "users_pending_email_key"},
+			call:    func(r *UserRepository) error { return r.UpdateEmail(context.Background(), u) },
+			wantErr: user.ErrUserAlreadyExists,
+		},
+		{
+			name:    "delete with timeout",
+			dbErr:   context.DeadlineExceeded,
+			call:    func(r *UserRepository) error { return r.Delete(context.Background(), "user-1", 0) },
+			wantErr: user.ErrStorageUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(&fakeDB{err: tt.dbErr})
+			assert.ErrorIs(t, tt.call(repo), tt.wantErr)
+		})
+	}
+
+	t.Run("unrelated database error is wrapped", func(t *testing.T) {
+		repo := newTestRepository(&fakeDB{err: syntaxError})
+		err := repo.Create(context.Background(), u)
+		assert.ErrorIs(t, err, syntaxError)
+		assert.False(t, errors.Is(err, user.ErrUserAlreadyExists))
+		assert.False(t, errors.Is(err, user.ErrStorageUnavailable))
+	})
+}
+
+func TestUserRepository_DeleteMissing(t *testing.T) {
+	repo := newTestRepository(&fakeDB{tag: pgconn.NewCommandTag("DELETE 0")})
+	assert.ErrorIs(t, repo.Delete(context.Background(), "missing", 0), user.ErrUserNotFound)
+
+	repo = newTestRepository(&fakeDB{tag: pgconn.NewCommandTag("DELETE 1")})
+	assert.NoError(t, repo.Delete(context.Background(), "user-1", 0))
+}
+
+func TestUserRepository_DeleteChanged(t *testing.T) {
+	// The user still exists, so the stored version differs
+	repo := newTestRepository(&fakeDB{tag: pgconn.NewCommandTag("DELETE 0")})
+	assert.ErrorIs(t, repo.Delete(context.Background(), "user-1", 3), user.ErrVersionMismatch)
+
+	repo = newTestRepository(&fakeDB{err: pgx.ErrNoRows})
+	assert.ErrorIs(t, repo.Delete(context.Background(), "missing", 3), user.ErrUserNotFound)
+}
+
+func TestUserRepository_WritesReadBackTimestamps(t *testing.T) {
+	createdAt := time.Date(2025, 10, 30, 19, 0, 0, 123456000, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+	// Columns of users up to version, see sqlc.User
+	row := []any{
+		"user-1", "test@example.com", "Test", "hash",
+		pgtype.Timestamp{Time: createdAt, Valid: true},
+		pgtype.Timestamp{Time: updatedAt, Valid: true},
+		nil, nil, nil, nil, nil, nil,
+		int64(4),
+	}
+
+	writes := map[string]func(*UserRepository, *user.User) error{
+		"create":          func(r *UserRepository, u *user.User) error { return r.Create(context.Background(), u) },
+		"update":          func(r *UserRepository, u *user.User) error { return r.Update(context.Background(), u) },
+		"update roles":    func(r *UserRepository, u *user.User) error { return r.UpdateRoles(context.Background(), u) },
+		"update password": func(r *UserRepository, u *user.User) error { return r.UpdatePassword(context.Background(), u) },
+		"update email":    func(r *UserRepository, u *user.User) error { return r.UpdateEmail(context.Background(), u) },
+	}
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			u := &user.User{ID: "user-1", Email: "test@example.com", Name: "Test", CreatedAt: createdAt, UpdatedAt: time.Now(), Version: 3}
+
+			assert.NoError(t, write(newTestRepository(&fakeDB{row: row}), u))
+			assert.Equal(t, updatedAt, u.UpdatedAt)
+			assert.Equal(t, createdAt, u.CreatedAt)
+			assert.Equal(t, int64(4), u.Version)
+		})
+	}
+}
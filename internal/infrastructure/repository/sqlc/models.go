@@ -8,11 +8,124 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type User struct {
+type ApiKey struct {
 	ID        string           `json:"id"`
-	Email     string           `json:"email"`
+	UserID    string           `json:"user_id"`
 	Name      string           `json:"name"`
-	Password  string           `json:"password"`
+	Prefix    string           `json:"prefix"`
+	KeyHash   string           `json:"key_hash"`
+	Scopes    []string         `json:"scopes"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	RevokedAt pgtype.Timestamp `json:"revoked_at"`
+}
+
+type EmailVerificationToken struct {
+	TokenHash string           `json:"token_hash"`
+	UserID    string           `json:"user_id"`
+	Email     string           `json:"email"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	UsedAt    pgtype.Timestamp `json:"used_at"`
+}
+
+type LoginChallenge struct {
+	TokenHash string           `json:"token_hash"`
+	UserID    string           `json:"user_id"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
-	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	UsedAt    pgtype.Timestamp `json:"used_at"`
+}
+
+type LoginFailure struct {
+	Subject      string           `json:"subject"`
+	SubjectKey   string           `json:"subject_key"`
+	Failures     int32            `json:"failures"`
+	LastFailedAt pgtype.Timestamp `json:"last_failed_at"`
+	LockedUntil  pgtype.Timestamp `json:"locked_until"`
+}
+
+type MagicLinkRequest struct {
+	UserID          string           `json:"user_id"`
+	Requests        int32            `json:"requests"`
+	WindowStartedAt pgtype.Timestamp `json:"window_started_at"`
+}
+
+type MagicLinkToken struct {
+	TokenHash string           `json:"token_hash"`
+	UserID    string           `json:"user_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	UsedAt    pgtype.Timestamp `json:"used_at"`
+}
+
+type Passkey struct {
+	ID           string           `json:"id"`
+	UserID       string           `json:"user_id"`
+	Name         string           `json:"name"`
+	CredentialID []byte           `json:"credential_id"`
+	PublicKey    []byte           `json:"public_key"`
+	SignCount    int64            `json:"sign_count"`
+	Transports   []string         `json:"transports"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	LastUsedAt   pgtype.Timestamp `json:"last_used_at"`
+}
+
+type PasskeyChallenge struct {
+	ChallengeHash string           `json:"challenge_hash"`
+	UserID        pgtype.Text      `json:"user_id"`
+	Ceremony      string           `json:"ceremony"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	ExpiresAt     pgtype.Timestamp `json:"expires_at"`
+	UsedAt        pgtype.Timestamp `json:"used_at"`
+}
+
+type PasswordHistory struct {
+	ID           int64            `json:"id"`
+	UserID       string           `json:"user_id"`
+	PasswordHash string           `json:"password_hash"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	TokenHash string           `json:"token_hash"`
+	UserID    string           `json:"user_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	UsedAt    pgtype.Timestamp `json:"used_at"`
+}
+
+type RecoveryCode struct {
+	UserID    string           `json:"user_id"`
+	CodeHash  string           `json:"code_hash"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UsedAt    pgtype.Timestamp `json:"used_at"`
+}
+
+type Session struct {
+	ID               string           `json:"id"`
+	UserID           string           `json:"user_id"`
+	RefreshTokenHash string           `json:"refresh_token_hash"`
+	UserAgent        string           `json:"user_agent"`
+	IpAddress        string           `json:"ip_address"`
+	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	LastUsedAt       pgtype.Timestamp `json:"last_used_at"`
+	ExpiresAt        pgtype.Timestamp `json:"expires_at"`
+	RevokedAt        pgtype.Timestamp `json:"revoked_at"`
+}
+
+type User struct {
+	ID               string           `json:"id"`
+	Email            string           `json:"email"`
+	Name             string           `json:"name"`
+	Password         string           `json:"password"`
+	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Roles            []string         `json:"roles"`
+	EmailVerifiedAt  pgtype.Timestamp `json:"email_verified_at"`
+	PendingEmail     pgtype.Text      `json:"pending_email"`
+	TotpSecret       pgtype.Text      `json:"totp_secret"`
+	TotpEnabledAt    pgtype.Timestamp `json:"totp_enabled_at"`
+	TotpLastUsedStep int64            `json:"totp_last_used_step"`
+	Version          int64            `json:"version"`
 }
@@ -9,12 +9,68 @@ import (
 )
 
 type Querier interface {
+	AddPasswordHistory(ctx context.Context, arg AddPasswordHistoryParams) error
+	CountUsers(ctx context.Context) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) (EmailVerificationToken, error)
+	CreateLoginChallenge(ctx context.Context, arg CreateLoginChallengeParams) (LoginChallenge, error)
+	CreateMagicLinkToken(ctx context.Context, arg CreateMagicLinkTokenParams) (MagicLinkToken, error)
+	CreatePasskey(ctx context.Context, arg CreatePasskeyParams) (Passkey, error)
+	CreatePasskeyChallenge(ctx context.Context, arg CreatePasskeyChallengeParams) (PasskeyChallenge, error)
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteUser(ctx context.Context, id string) error
+	DeleteLoginFailures(ctx context.Context, arg DeleteLoginFailuresParams) error
+	DeleteRecoveryCodes(ctx context.Context, userID string) error
+	DeleteUser(ctx context.Context, arg DeleteUserParams) (int64, error)
+	DisableUserTOTP(ctx context.Context, id string) (int64, error)
+	EmailExists(ctx context.Context, email string) (bool, error)
+	EnableUserTOTP(ctx context.Context, arg EnableUserTOTPParams) (int64, error)
+	GetAPIKey(ctx context.Context, id string) (ApiKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	GetEmailVerificationToken(ctx context.Context, tokenHash string) (EmailVerificationToken, error)
+	GetLoginChallenge(ctx context.Context, tokenHash string) (LoginChallenge, error)
+	GetLoginFailures(ctx context.Context, arg GetLoginFailuresParams) (LoginFailure, error)
+	GetMagicLinkToken(ctx context.Context, tokenHash string) (MagicLinkToken, error)
+	GetPasskeyByCredentialID(ctx context.Context, credentialID []byte) (Passkey, error)
+	GetPasskeyChallenge(ctx context.Context, challengeHash string) (PasskeyChallenge, error)
+	GetPasswordResetToken(ctx context.Context, tokenHash string) (PasswordResetToken, error)
+	GetSessionByID(ctx context.Context, id string) (Session, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id string) (User, error)
+	GetUserTOTP(ctx context.Context, id string) (GetUserTOTPRow, error)
+	GetUsersByIDs(ctx context.Context, ids []string) ([]User, error)
+	InvalidateEmailVerificationTokens(ctx context.Context, arg InvalidateEmailVerificationTokensParams) (int64, error)
+	InvalidateMagicLinkTokens(ctx context.Context, arg InvalidateMagicLinkTokensParams) (int64, error)
+	InvalidatePasswordResetTokens(ctx context.Context, arg InvalidatePasswordResetTokensParams) (int64, error)
+	ListActiveSessions(ctx context.Context, arg ListActiveSessionsParams) ([]Session, error)
+	ListPasswordHistory(ctx context.Context, arg ListPasswordHistoryParams) ([]string, error)
+	ListUserAPIKeys(ctx context.Context, userID string) ([]ApiKey, error)
+	ListUserPasskeys(ctx context.Context, userID string) ([]Passkey, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	ListUsersAfter(ctx context.Context, arg ListUsersAfterParams) ([]User, error)
+	LockLoginFailures(ctx context.Context, arg LockLoginFailuresParams) (int64, error)
+	PrunePasswordHistory(ctx context.Context, arg PrunePasswordHistoryParams) (int64, error)
+	RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (LoginFailure, error)
+	RecordMagicLinkRequest(ctx context.Context, arg RecordMagicLinkRequestParams) (int32, error)
+	ReplaceRecoveryCodes(ctx context.Context, arg ReplaceRecoveryCodesParams) error
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
+	RevokeSession(ctx context.Context, arg RevokeSessionParams) (int64, error)
+	RevokeUserSessions(ctx context.Context, arg RevokeUserSessionsParams) (int64, error)
+	RotateSession(ctx context.Context, arg RotateSessionParams) (int64, error)
+	SetUserTOTPSecret(ctx context.Context, arg SetUserTOTPSecretParams) (int64, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error)
+	UseEmailVerificationToken(ctx context.Context, arg UseEmailVerificationTokenParams) (int64, error)
+	UseLoginChallenge(ctx context.Context, arg UseLoginChallengeParams) (int64, error)
+	UseMagicLinkToken(ctx context.Context, arg UseMagicLinkTokenParams) (int64, error)
+	UsePasskey(ctx context.Context, arg UsePasskeyParams) (int64, error)
+	UsePasskeyChallenge(ctx context.Context, arg UsePasskeyChallengeParams) (int64, error)
+	UsePasswordResetToken(ctx context.Context, arg UsePasswordResetTokenParams) (int64, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
+	UseUserTOTPStep(ctx context.Context, arg UseUserTOTPStepParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
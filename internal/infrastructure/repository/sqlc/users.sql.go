@@ -11,10 +11,21 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countUsers = `-- name: CountUsers :one
+SELECT COUNT(*) FROM users
+`
+
+func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, email, name, password, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, email, name, password, created_at, updated_at
+INSERT INTO users (id, email, name, password, created_at, updated_at, roles)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, email, name, password, created_at, updated_at, roles, email_verified_at, pending_email, totp_secret, totp_enabled_at, totp_last_used_step, version
 `
 
 type CreateUserParams struct {
@@ -24,6 +35,7 @@ type CreateUserParams struct {
 	Password  string           `json:"password"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	Roles     []string         `json:"roles"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -34,6 +46,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.Password,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.Roles,
 	)
 	var i User
 	err := row.Scan(
@@ -43,22 +56,51 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Roles,
+		&i.EmailVerifiedAt,
+		&i.PendingEmail,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastUsedStep,
+		&i.Version,
 	)
 	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
+const deleteUser = `-- name: DeleteUser :execrows
 DELETE FROM users
-WHERE id = $1
+WHERE id = $1 AND ($2::bigint = 0 OR version = $2)
+`
+
+type DeleteUserParams struct {
+	ID      string `json:"id"`
+	Version int64  `json:"version"`
+}
+
+func (q *Queries) DeleteUser(ctx context.Context, arg DeleteUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUser, arg.ID, arg.Version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const emailExists = `-- name: EmailExists :one
+SELECT EXISTS(
+    SELECT 1 FROM users
+    WHERE email = $1 OR pending_email = $1
+)
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, id string) error {
-	_, err := q.db.Exec(ctx, deleteUser, id)
-	return err
+func (q *Queries) EmailExists(ctx context.Context, email string) (bool, error) {
+	row := q.db.QueryRow(ctx, emailExists, email)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, name, password, created_at, updated_at FROM users
+SELECT id, email, name, password, created_at, updated_at, roles, email_verified_at, pending_email, totp_secret, totp_enabled_at, totp_last_used_step, version FROM users
 WHERE email = $1 LIMIT 1
 `
 
@@ -72,12 +114,19 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Roles,
+		&i.EmailVerifiedAt,
+		&i.PendingEmail,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastUsedStep,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, email, name, password, created_at, updated_at FROM users
+SELECT id, email, name, password, created_at, updated_at, roles, email_verified_at, pending_email, totp_secret, totp_enabled_at, totp_last_used_step, version FROM users
 WHERE id = $1 LIMIT 1
 `
 
@@ -91,13 +140,59 @@ func (q *Queries) GetUserByID(ctx context.Context, id string) (User, error) {
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Roles,
+		&i.EmailVerifiedAt,
+		&i.PendingEmail,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastUsedStep,
+		&i.Version,
 	)
 	return i, err
 }
 
+const getUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, email, name, password, created_at, updated_at, roles, email_verified_at, pending_email, totp_secret, totp_enabled_at, totp_last_used_step, version FROM users
+WHERE id = ANY($1::varchar[])
+`
+
+func (q *Queries) GetUsersByIDs(ctx context.Context, ids []string) ([]User, error) {
+	rows, err := q.db.Query(ctx, getUsersByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Name,
+			&i.Password,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Roles,
+			&i.EmailVerifiedAt,
+			&i.PendingEmail,
+			&i.TotpSecret,
+			&i.TotpEnabledAt,
+			&i.TotpLastUsedStep,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsers = `-- name: ListUsers :many
-SELECT id, email, name, password, created_at, updated_at FROM users
-ORDER BY created_at DESC
+SELECT id, email, name, password, created_at, updated_at, roles, email_verified_at, pending_email, totp_secret, totp_enabled_at, totp_last_used_step, version FROM users
+ORDER BY created_at DESC, id DESC
 LIMIT $1 OFFSET $2
 `
 
@@ -122,6 +217,60 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.Password,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Roles,
+			&i.EmailVerifiedAt,
+			&i.PendingEmail,
+			&i.TotpSecret,
+			&i.TotpEnabledAt,
+			&i.TotpLastUsedStep,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersAfter = `-- name: ListUsersAfter :many
+SELECT id, email, name, password, created_at, updated_at, roles, email_verified_at, pending_email, totp_secret, totp_enabled_at, totp_last_used_step, version FROM users
+WHERE (created_at, id) < ($1::timestamp, $2::varchar)
+ORDER BY created_at DESC, id DESC
+LIMIT $3
+`
+
+type ListUsersAfterParams struct {
+	CursorCreatedAt pgtype.Timestamp `json:"cursor_created_at"`
+	CursorID        string           `json:"cursor_id"`
+	PageSize        int32            `json:"page_size"`
+}
+
+func (q *Queries) ListUsersAfter(ctx context.Context, arg ListUsersAfterParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersAfter, arg.CursorCreatedAt, arg.CursorID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Name,
+			&i.Password,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Roles,
+			&i.EmailVerifiedAt,
+			&i.PendingEmail,
+			&i.TotpSecret,
+			&i.TotpEnabledAt,
+			&i.TotpLastUsedStep,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -135,19 +284,135 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
-SET name = $2, updated_at = $3
-WHERE id = $1
-RETURNING id, email, name, password, created_at, updated_at
+SET name = $2, updated_at = $3, version = version + 1
+WHERE id = $1 AND version = $4
+RETURNING id, email, name, password, created_at, updated_at, roles, email_verified_at, pending_email, totp_secret, totp_enabled_at, totp_last_used_step, version
 `
 
 type UpdateUserParams struct {
 	ID        string           `json:"id"`
 	Name      string           `json:"name"`
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	Version   int64            `json:"version"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, updateUser, arg.ID, arg.Name, arg.UpdatedAt)
+	row := q.db.QueryRow(ctx, updateUser,
+		arg.ID,
+		arg.Name,
+		arg.UpdatedAt,
+		arg.Version,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Roles,
+		&i.EmailVerifiedAt,
+		&i.PendingEmail,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastUsedStep,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateUserEmail = `-- name: UpdateUserEmail :one
+UPDATE users
+SET email = $2, email_verified_at = $3, pending_email = $4, updated_at = $5, version = version + 1
+WHERE id = $1
+RETURNING id, email, name, password, created_at, updated_at, roles, email_verified_at, pending_email, totp_secret, totp_enabled_at, totp_last_used_step, version
+`
+
+type UpdateUserEmailParams struct {
+	ID              string           `json:"id"`
+	Email           string           `json:"email"`
+	EmailVerifiedAt pgtype.Timestamp `json:"email_verified_at"`
+	PendingEmail    pgtype.Text      `json:"pending_email"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserEmail,
+		arg.ID,
+		arg.Email,
+		arg.EmailVerifiedAt,
+		arg.PendingEmail,
+		arg.UpdatedAt,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Roles,
+		&i.EmailVerifiedAt,
+		&i.PendingEmail,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastUsedStep,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE users
+SET password = $2, updated_at = $3, version = version + 1
+WHERE id = $1
+RETURNING id, email, name, password, created_at, updated_at, roles, email_verified_at, pending_email, totp_secret, totp_enabled_at, totp_last_used_step, version
+`
+
+type UpdateUserPasswordParams struct {
+	ID        string           `json:"id"`
+	Password  string           `json:"password"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserPassword, arg.ID, arg.Password, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Roles,
+		&i.EmailVerifiedAt,
+		&i.PendingEmail,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastUsedStep,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateUserRoles = `-- name: UpdateUserRoles :one
+UPDATE users
+SET roles = $2, updated_at = $3, version = version + 1
+WHERE id = $1
+RETURNING id, email, name, password, created_at, updated_at, roles, email_verified_at, pending_email, totp_secret, totp_enabled_at, totp_last_used_step, version
+`
+
+type UpdateUserRolesParams struct {
+	ID        string           `json:"id"`
+	Roles     []string         `json:"roles"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserRoles, arg.ID, arg.Roles, arg.UpdatedAt)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -156,6 +421,13 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Roles,
+		&i.EmailVerifiedAt,
+		&i.PendingEmail,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastUsedStep,
+		&i.Version,
 	)
 	return i, err
 }
@@ -0,0 +1,43 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// AuthenticateAPIKeyUseCase handles authenticating requests made with API keys
+type AuthenticateAPIKeyUseCase struct {
+	keys   user.APIKeyRepository
+	logger *logger.Logger
+}
+
+// NewAuthenticateAPIKeyUseCase creates a new use case instance
+func NewAuthenticateAPIKeyUseCase(keys user.APIKeyRepository, logger *logger.Logger) *AuthenticateAPIKeyUseCase {
+	return &AuthenticateAPIKeyUseCase{
+		keys:   keys,
+		logger: logger,
+	}
+}
+
+// Execute returns the stored key matching key. Unknown, expired and revoked
+// keys return user.ErrInvalidAPIKey.
+func (uc *AuthenticateAPIKeyUseCase) Execute(ctx context.Context, key string) (*user.APIKey, error) {
+	apiKey, err := uc.keys.GetByHash(ctx, auth.HashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidAPIKey) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to get API key from database")
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	if !apiKey.IsActive(time.Now()) {
+		return nil, user.ErrInvalidAPIKey
+	}
+	return apiKey, nil
+}
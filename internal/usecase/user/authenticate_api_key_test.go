@@ -0,0 +1,51 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticateAPIKeyUseCase_Execute(t *testing.T) {
+	hash := auth.HashAPIKey("mst_secret")
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		stored  *user.APIKey
+		repoErr error
+		wantErr error
+	}{
+		{name: "active key", stored: &user.APIKey{ID: "key-1", UserID: "user-1"}},
+		{name: "unknown key", repoErr: user.ErrInvalidAPIKey, wantErr: user.ErrInvalidAPIKey},
+		{name: "expired key", stored: &user.APIKey{ID: "key-1", ExpiresAt: &past}, wantErr: user.ErrInvalidAPIKey},
+		{name: "revoked key", stored: &user.APIKey{ID: "key-1", RevokedAt: &past}, wantErr: user.ErrInvalidAPIKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := new(MockAPIKeyRepository)
+			if tt.stored != nil {
+				keys.On("GetByHash", mock.Anything, hash).Return(tt.stored, nil)
+			} else {
+				keys.On("GetByHash", mock.Anything, hash).Return(nil, tt.repoErr)
+			}
+
+			uc := NewAuthenticateAPIKeyUseCase(keys, logger.New("test"))
+			key, err := uc.Execute(context.Background(), "mst_secret")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "key-1", key.ID)
+		})
+	}
+}
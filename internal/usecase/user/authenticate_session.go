@@ -0,0 +1,49 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// AuthenticateSessionUseCase handles checking that the session an access
+// token was issued for is still active, so that revoking a session, signing
+// out everywhere or changing the password also ends its access tokens
+type AuthenticateSessionUseCase struct {
+	sessions user.SessionRepository
+	logger   *logger.Logger
+}
+
+// NewAuthenticateSessionUseCase creates a new use case instance
+func NewAuthenticateSessionUseCase(sessions user.SessionRepository, logger *logger.Logger) *AuthenticateSessionUseCase {
+	return &AuthenticateSessionUseCase{
+		sessions: sessions,
+		logger:   logger,
+	}
+}
+
+// Execute returns nil if the session exists, belongs to the user and is
+// active. Unknown, foreign, revoked and expired sessions return
+// user.ErrSessionRevoked.
+func (uc *AuthenticateSessionUseCase) Execute(ctx context.Context, userID, sessionID string) error {
+	if sessionID == "" {
+		return user.ErrSessionRevoked
+	}
+
+	session, err := uc.sessions.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, user.ErrSessionNotFound) {
+			return user.ErrSessionRevoked
+		}
+		uc.logger.WithError(err).Error("Failed to get session from database")
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.UserID != userID || !session.IsActive(time.Now()) {
+		return user.ErrSessionRevoked
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthenticateSessionUseCase_Execute(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		stored  *user.Session
+		repoErr error
+		wantErr error
+	}{
+		{name: "active session", stored: &user.Session{ID: "session-1", UserID: "user-1", ExpiresAt: future}},
+		{name: "unknown session", repoErr: user.ErrSessionNotFound, wantErr: user.ErrSessionRevoked},
+		{name: "revoked session", stored: &user.Session{ID: "session-1", UserID: "user-1", ExpiresAt: future, RevokedAt: &past}, wantErr: user.ErrSessionRevoked},
+		{name: "expired session", stored: &user.Session{ID: "session-1", UserID: "user-1", ExpiresAt: past}, wantErr: user.ErrSessionRevoked},
+		{name: "session of another user", stored: &user.Session{ID: "session-1", UserID: "user-2", ExpiresAt: future}, wantErr: user.ErrSessionRevoked},
+		{name: "storage failure", repoErr: user.ErrStorageUnavailable, wantErr: user.ErrStorageUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := new(MockSessionRepository)
+			if tt.stored != nil {
+				sessions.On("GetByID", mock.Anything, "session-1").Return(tt.stored, nil)
+			} else {
+				sessions.On("GetByID", mock.Anything, "session-1").Return(nil, tt.repoErr)
+			}
+
+			uc := NewAuthenticateSessionUseCase(sessions, logger.New("test"))
+			err := uc.Execute(context.Background(), "user-1", "session-1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("token without session", func(t *testing.T) {
+		uc := NewAuthenticateSessionUseCase(new(MockSessionRepository), logger.New("test"))
+		assert.ErrorIs(t, uc.Execute(context.Background(), "user-1", ""), user.ErrSessionRevoked)
+	})
+}
@@ -0,0 +1,55 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// AuthorizeInput describes an action an authenticated user wants to perform
+type AuthorizeInput struct {
+	ActorID      string
+	Action       user.Action
+	TargetUserID string
+}
+
+// AuthorizeUseCase checks the current roles of a user against the domain policy
+type AuthorizeUseCase struct {
+	repo   user.Repository
+	logger *logger.Logger
+}
+
+// NewAuthorizeUseCase creates a new use case instance
+func NewAuthorizeUseCase(repo user.Repository, logger *logger.Logger) *AuthorizeUseCase {
+	return &AuthorizeUseCase{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Execute returns user.ErrPermissionDenied when the actor may not perform the action,
+// and user.ErrUnauthorized when the actor's account no longer exists
+func (uc *AuthorizeUseCase) Execute(ctx context.Context, input AuthorizeInput) error {
+	actor, err := uc.repo.GetByID(ctx, input.ActorID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return user.ErrUnauthorized
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := user.Authorize(actor, input.Action, input.TargetUserID); err != nil {
+		uc.logger.WithFields(map[string]any{
+			"user_id": input.ActorID,
+			"action":  input.Action,
+			"target":  input.TargetUserID,
+		}).Info("Permission denied")
+		return err
+	}
+
+	return nil
+}
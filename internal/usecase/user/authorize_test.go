@@ -0,0 +1,63 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthorizeUseCase_Execute(t *testing.T) {
+	admin := &user.User{ID: "admin-1", Roles: []user.Role{user.RoleUser, user.RoleAdmin}}
+	member := &user.User{ID: "user-1", Roles: []user.Role{user.RoleUser}}
+
+	tests := []struct {
+		name    string
+		actor   *user.User
+		input   AuthorizeInput
+		repoErr error
+		wantErr error
+	}{
+		{
+			name:  "admin deletes user",
+			actor: admin,
+			input: AuthorizeInput{ActorID: "admin-1", Action: user.ActionDeleteUser, TargetUserID: "user-1"},
+		},
+		{
+			name:    "user deletes other user",
+			actor:   member,
+			input:   AuthorizeInput{ActorID: "user-1", Action: user.ActionDeleteUser, TargetUserID: "user-2"},
+			wantErr: user.ErrPermissionDenied,
+		},
+		{
+			name:    "deleted actor",
+			input:   AuthorizeInput{ActorID: "gone", Action: user.ActionReadUser, TargetUserID: "gone"},
+			repoErr: user.ErrUserNotFound,
+			wantErr: user.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			if tt.repoErr != nil {
+				repo.On("GetByID", mock.Anything, tt.input.ActorID).Return(nil, tt.repoErr)
+			} else {
+				repo.On("GetByID", mock.Anything, tt.input.ActorID).Return(tt.actor, nil)
+			}
+
+			uc := NewAuthorizeUseCase(repo, logger.New("test"))
+			err := uc.Execute(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
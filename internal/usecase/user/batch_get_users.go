@@ -0,0 +1,106 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// ErrTooManyUserIDs is returned when a batch asks for more users than allowed
+var ErrTooManyUserIDs = errors.New("too many user IDs")
+
+// BatchGetUsersInput represents the users to retrieve
+type BatchGetUsersInput struct {
+	UserIDs []string
+	// IncludePendingEmail returns the pending email addresses, which only
+	// admins may see
+	IncludePendingEmail bool
+}
+
+// BatchGetUsersOutput holds the users that exist and the IDs that have no
+// user, both in the order they were requested. Repeated IDs appear once.
+type BatchGetUsersOutput struct {
+	Users      []*GetUserOutput
+	MissingIDs []string
+}
+
+// BatchGetUsersUseCase handles retrieving several users in one query
+type BatchGetUsersUseCase struct {
+	repo   user.Repository
+	maxIDs int
+	logger *logger.Logger
+}
+
+// NewBatchGetUsersUseCase creates a new use case. maxIDs caps the number of
+// distinct IDs of one batch.
+func NewBatchGetUsersUseCase(repo user.Repository, maxIDs int, logger *logger.Logger) *BatchGetUsersUseCase {
+	return &BatchGetUsersUseCase{
+		repo:   repo,
+		maxIDs: maxIDs,
+		logger: logger,
+	}
+}
+
+// Execute retrieves the users with the given IDs
+func (uc *BatchGetUsersUseCase) Execute(ctx context.Context, input BatchGetUsersInput) (*BatchGetUsersOutput, error) {
+	uc.logger.WithField("count", len(input.UserIDs)).Debug("Getting users in batch")
+
+	// 1. Drop repeated IDs, keeping the request order
+	ids := make([]string, 0, len(input.UserIDs))
+	seen := make(map[string]bool, len(input.UserIDs))
+	for _, id := range input.UserIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > uc.maxIDs {
+		return nil, ErrTooManyUserIDs
+	}
+
+	// 2. Fetch all users in one query
+	users, err := uc.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to get users from database")
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	// 3. Put them in request order
+	byID := make(map[string]*user.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	output := &BatchGetUsersOutput{
+		Users:      make([]*GetUserOutput, 0, len(users)),
+		MissingIDs: []string{},
+	}
+	for _, id := range ids {
+		u, ok := byID[id]
+		if !ok {
+			output.MissingIDs = append(output.MissingIDs, id)
+			continue
+		}
+		result := &GetUserOutput{
+			ID:    u.ID,
+			Email: u.Email,
+			Name:  u.Name,
+			Roles: u.RoleNames(),
+
+			EmailVerified: u.IsEmailVerified(),
+			Version:       u.Version,
+
+			CreatedAt: u.CreatedAt,
+			UpdatedAt: u.UpdatedAt,
+		}
+		if input.IncludePendingEmail {
+			result.PendingEmail = u.PendingEmail
+		}
+		output.Users = append(output.Users, result)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,120 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBatchGetUsersUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       BatchGetUsersInput
+		setup       func(*MockRepository)
+		wantIDs     []string
+		wantMissing []string
+		wantErr     error
+	}{
+		{
+			name:  "users in request order",
+			input: BatchGetUsersInput{UserIDs: []string{"user-2", "user-1", "user-3"}},
+			setup: func(repo *MockRepository) {
+				repo.On("GetByIDs", mock.Anything, []string{"user-2", "user-1", "user-3"}).Return([]*user.User{
+					{ID: "user-1", Email: "one@example.com"},
+					{ID: "user-3", Email: "three@example.com"},
+					{ID: "user-2", Email: "two@example.com"},
+				}, nil)
+			},
+			wantIDs:     []string{"user-2", "user-1", "user-3"},
+			wantMissing: []string{},
+		},
+		{
+			name:  "missing users reported separately",
+			input: BatchGetUsersInput{UserIDs: []string{"missing-1", "user-1", "missing-2"}},
+			setup: func(repo *MockRepository) {
+				repo.On("GetByIDs", mock.Anything, []string{"missing-1", "user-1", "missing-2"}).Return([]*user.User{
+					{ID: "user-1", Email: "one@example.com"},
+				}, nil)
+			},
+			wantIDs:     []string{"user-1"},
+			wantMissing: []string{"missing-1", "missing-2"},
+		},
+		{
+			name:  "repeated ids fetched once",
+			input: BatchGetUsersInput{UserIDs: []string{"user-1", "user-2", "user-1", "user-1"}},
+			setup: func(repo *MockRepository) {
+				repo.On("GetByIDs", mock.Anything, []string{"user-1", "user-2"}).Return([]*user.User{
+					{ID: "user-1", Email: "one@example.com"},
+					{ID: "user-2", Email: "two@example.com"},
+				}, nil)
+			},
+			wantIDs:     []string{"user-1", "user-2"},
+			wantMissing: []string{},
+		},
+		{
+			name:    "too many ids",
+			input:   BatchGetUsersInput{UserIDs: []string{"user-1", "user-2", "user-3", "user-4"}},
+			setup:   func(repo *MockRepository) {},
+			wantErr: ErrTooManyUserIDs,
+		},
+		{
+			name:  "storage failure",
+			input: BatchGetUsersInput{UserIDs: []string{"user-1"}},
+			setup: func(repo *MockRepository) {
+				repo.On("GetByIDs", mock.Anything, []string{"user-1"}).Return(nil, user.ErrStorageUnavailable)
+			},
+			wantErr: user.ErrStorageUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup mocks
+			repo := new(MockRepository)
+			tt.setup(repo)
+
+			// Create use case
+			uc := NewBatchGetUsersUseCase(repo, 3, logger.New("test"))
+
+			// Execute
+			result, err := uc.Execute(context.Background(), tt.input)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				ids := make([]string, len(result.Users))
+				for i, u := range result.Users {
+					ids[i] = u.ID
+				}
+				assert.Equal(t, tt.wantIDs, ids)
+				assert.Equal(t, tt.wantMissing, result.MissingIDs)
+			}
+
+			// Verify mocks
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBatchGetUsersUseCase_PendingEmail(t *testing.T) {
+	repo := new(MockRepository)
+	repo.On("GetByIDs", mock.Anything, []string{"user-1"}).Return([]*user.User{
+		{ID: "user-1", Email: "one@example.com", PendingEmail: "new@example.com"},
+	}, nil)
+	uc := NewBatchGetUsersUseCase(repo, 3, logger.New("test"))
+
+	result, err := uc.Execute(context.Background(), BatchGetUsersInput{UserIDs: []string{"user-1"}})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Users[0].PendingEmail)
+
+	result, err = uc.Execute(context.Background(), BatchGetUsersInput{UserIDs: []string{"user-1"}, IncludePendingEmail: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "new@example.com", result.Users[0].PendingEmail)
+}
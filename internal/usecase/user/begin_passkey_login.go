@@ -0,0 +1,64 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/memclutter/go-microservices-template/pkg/webauthn"
+)
+
+// BeginPasskeyLoginOutput represents the options passed to
+// navigator.credentials.get() by the client
+type BeginPasskeyLoginOutput struct {
+	Challenge string
+	RPID      string
+}
+
+// BeginPasskeyLoginUseCase handles starting passkey authentication ceremonies
+type BeginPasskeyLoginUseCase struct {
+	challenges   user.PasskeyChallengeRepository
+	rp           *webauthn.RelyingParty
+	challengeTTL time.Duration
+	logger       *logger.Logger
+}
+
+// NewBeginPasskeyLoginUseCase creates a new use case instance
+func NewBeginPasskeyLoginUseCase(
+	challenges user.PasskeyChallengeRepository,
+	rp *webauthn.RelyingParty,
+	challengeTTL time.Duration,
+	logger *logger.Logger,
+) *BeginPasskeyLoginUseCase {
+	return &BeginPasskeyLoginUseCase{
+		challenges:   challenges,
+		rp:           rp,
+		challengeTTL: challengeTTL,
+		logger:       logger,
+	}
+}
+
+// Execute creates a login challenge for the ceremony completed by
+// FinishPasskeyLogin. Passkeys are discoverable, so the challenge is not
+// bound to a user and no credentials are listed for the client.
+func (uc *BeginPasskeyLoginUseCase) Execute(ctx context.Context) (*BeginPasskeyLoginOutput, error) {
+	uc.logger.Info("Beginning passkey login")
+
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+	c := user.NewPasskeyChallenge(auth.HashSecretToken(challenge), "", user.PasskeyLogin, uc.challengeTTL)
+	if err := uc.challenges.Create(ctx, c); err != nil {
+		uc.logger.WithError(err).Error("Failed to create passkey challenge")
+		return nil, fmt.Errorf("failed to create passkey challenge: %w", err)
+	}
+
+	return &BeginPasskeyLoginOutput{
+		Challenge: challenge,
+		RPID:      uc.rp.ID(),
+	}, nil
+}
@@ -0,0 +1,114 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/memclutter/go-microservices-template/pkg/webauthn"
+)
+
+// BeginPasskeyRegistrationInput represents the user registering a passkey
+type BeginPasskeyRegistrationInput struct {
+	UserID string
+}
+
+// BeginPasskeyRegistrationOutput represents the options passed to
+// navigator.credentials.create() by the client
+type BeginPasskeyRegistrationOutput struct {
+	Challenge string
+	RPID      string
+	RPName    string
+	// UserHandle identifies the user to the authenticator and is returned
+	// with assertions of the passkey
+	UserHandle      string
+	UserName        string
+	UserDisplayName string
+	// ExcludeCredentialIDs lists the user's passkeys, so that an
+	// authenticator does not register a second one
+	ExcludeCredentialIDs [][]byte
+	// Algorithms lists the accepted COSE algorithms in order of preference
+	Algorithms []int
+}
+
+// BeginPasskeyRegistrationUseCase handles starting passkey registration ceremonies
+type BeginPasskeyRegistrationUseCase struct {
+	repo         user.Repository
+	passkeys     user.PasskeyRepository
+	challenges   user.PasskeyChallengeRepository
+	rp           *webauthn.RelyingParty
+	challengeTTL time.Duration
+	logger       *logger.Logger
+}
+
+// NewBeginPasskeyRegistrationUseCase creates a new use case instance
+func NewBeginPasskeyRegistrationUseCase(
+	repo user.Repository,
+	passkeys user.PasskeyRepository,
+	challenges user.PasskeyChallengeRepository,
+	rp *webauthn.RelyingParty,
+	challengeTTL time.Duration,
+	logger *logger.Logger,
+) *BeginPasskeyRegistrationUseCase {
+	return &BeginPasskeyRegistrationUseCase{
+		repo:         repo,
+		passkeys:     passkeys,
+		challenges:   challenges,
+		rp:           rp,
+		challengeTTL: challengeTTL,
+		logger:       logger,
+	}
+}
+
+// Execute creates a registration challenge for the user and returns the
+// options of the ceremony, completed by FinishPasskeyRegistration
+func (uc *BeginPasskeyRegistrationUseCase) Execute(ctx context.Context, input BeginPasskeyRegistrationInput) (*BeginPasskeyRegistrationOutput, error) {
+	uc.logger.WithField("user_id", input.UserID).Info("Beginning passkey registration")
+
+	// 1. Load existing user
+	u, err := uc.repo.GetByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 2. Load registered passkeys
+	passkeys, err := uc.passkeys.ListByUser(ctx, u.ID)
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to list passkeys")
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	excludeCredentialIDs := make([][]byte, len(passkeys))
+	for i, p := range passkeys {
+		excludeCredentialIDs[i] = p.CredentialID
+	}
+
+	// 3. Create challenge
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+	c := user.NewPasskeyChallenge(auth.HashSecretToken(challenge), u.ID, user.PasskeyRegistration, uc.challengeTTL)
+	if err := uc.challenges.Create(ctx, c); err != nil {
+		uc.logger.WithError(err).Error("Failed to create passkey challenge")
+		return nil, fmt.Errorf("failed to create passkey challenge: %w", err)
+	}
+
+	return &BeginPasskeyRegistrationOutput{
+		Challenge:            challenge,
+		RPID:                 uc.rp.ID(),
+		RPName:               uc.rp.Name(),
+		UserHandle:           u.ID,
+		UserName:             u.Email,
+		UserDisplayName:      u.Name,
+		ExcludeCredentialIDs: excludeCredentialIDs,
+		Algorithms:           webauthn.SupportedAlgorithms,
+	}, nil
+}
@@ -0,0 +1,132 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// ChangeEmailInput represents input data for changing an email address
+type ChangeEmailInput struct {
+	UserID          string
+	NewEmail        string
+	CurrentPassword string
+}
+
+// ChangeEmailUseCase handles requesting an email address change
+type ChangeEmailUseCase struct {
+	repo            user.Repository
+	domainService   user.Service
+	verifications   user.EmailVerificationRepository
+	hasher          user.PasswordHasher
+	eventPub        EventPublisher
+	verificationTTL time.Duration
+	logger          *logger.Logger
+}
+
+// NewChangeEmailUseCase creates a new use case instance
+func NewChangeEmailUseCase(
+	repo user.Repository,
+	domainService user.Service,
+	verifications user.EmailVerificationRepository,
+	hasher user.PasswordHasher,
+	eventPub EventPublisher,
+	verificationTTL time.Duration,
+	logger *logger.Logger,
+) *ChangeEmailUseCase {
+	return &ChangeEmailUseCase{
+		repo:            repo,
+		domainService:   domainService,
+		verifications:   verifications,
+		hasher:          hasher,
+		eventPub:        eventPub,
+		verificationTTL: verificationTTL,
+		logger:          logger,
+	}
+}
+
+// Execute holds the new address as pending and sends a verification token to it.
+// The email changes only once the token is passed to VerifyEmail.
+func (uc *ChangeEmailUseCase) Execute(ctx context.Context, input ChangeEmailInput) (*GetUserOutput, error) {
+	uc.logger.WithFields(map[string]any{
+		"user_id":   input.UserID,
+		"new_email": input.NewEmail,
+	}).Info("Changing user email")
+
+	// 1. Load existing user
+	u, err := uc.repo.GetByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 2. Verify current password
+	if err := u.CheckPassword(input.CurrentPassword, uc.hasher); err != nil {
+		return nil, user.ErrIncorrectPassword
+	}
+
+	// 3. Apply domain change (with validation)
+	if err := u.RequestEmailChange(input.NewEmail); err != nil {
+		return nil, fmt.Errorf("invalid email: %w", err)
+	}
+
+	// 4. Check the new email is neither in use nor pending elsewhere
+	isUnique, err := uc.domainService.IsEmailUnique(ctx, input.NewEmail)
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to check email uniqueness")
+		return nil, fmt.Errorf("failed to check email: %w", err)
+	}
+	if !isUnique {
+		return nil, user.ErrUserAlreadyExists
+	}
+
+	// 5. Save to repository
+	if err := uc.repo.UpdateEmail(ctx, u); err != nil {
+		if errors.Is(err, user.ErrUserNotFound) || errors.Is(err, user.ErrUserAlreadyExists) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to update user email in database")
+		return nil, fmt.Errorf("failed to update email: %w", err)
+	}
+
+	// 6. Send verification token to the new address
+	if err := requestEmailVerification(ctx, uc.verifications, uc.eventPub, u, u.PendingEmail, uc.verificationTTL); err != nil {
+		uc.logger.WithError(err).Error("Failed to request email verification")
+		return nil, err
+	}
+
+	// 7. Notify the current address
+	event := user.EmailChangeRequestedEvent{
+		UserID:      u.ID,
+		Email:       u.Email,
+		NewEmail:    u.PendingEmail,
+		RequestedAt: u.UpdatedAt,
+	}
+	if err := uc.eventPub.Publish(ctx, user.EventTypeEmailChangeRequested, event); err != nil {
+		// Don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to publish email change requested event")
+	}
+
+	uc.logger.WithField("user_id", u.ID).Info("User email change requested")
+
+	return &GetUserOutput{
+		ID:    u.ID,
+		Email: u.Email,
+		Name:  u.Name,
+		Roles: u.RoleNames(),
+
+		EmailVerified: u.IsEmailVerified(),
+		PendingEmail:  u.PendingEmail,
+		Version:       u.Version,
+
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}, nil
+}
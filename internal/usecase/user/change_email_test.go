@@ -0,0 +1,95 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeEmailUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   ChangeEmailInput
+		setup   func(*MockRepository, *MockDomainService, *MockEmailVerificationRepository, *MockEventPublisher)
+		wantErr error
+	}{
+		{
+			name:  "successful change request",
+			input: ChangeEmailInput{UserID: "user-1", NewEmail: "new@example.com", CurrentPassword: "password123"},
+			setup: func(repo *MockRepository, ds *MockDomainService, verifications *MockEmailVerificationRepository, pub *MockEventPublisher) {
+				ds.On("IsEmailUnique", mock.Anything, "new@example.com").Return(true, nil)
+				repo.On("UpdateEmail", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+					return u.Email == "old@example.com" && u.PendingEmail == "new@example.com"
+				})).Return(nil)
+				verifications.On("Create", mock.Anything, mock.MatchedBy(func(v *user.EmailVerificationToken) bool {
+					return v.Email == "new@example.com"
+				})).Return(nil)
+				pub.On("Publish", mock.Anything, user.EventTypeVerificationRequested, mock.MatchedBy(func(e user.VerificationRequestedEvent) bool {
+					return e.Email == "new@example.com" && e.Token != ""
+				})).Return(nil)
+				pub.On("Publish", mock.Anything, user.EventTypeEmailChangeRequested, mock.MatchedBy(func(e user.EmailChangeRequestedEvent) bool {
+					return e.Email == "old@example.com" && e.NewEmail == "new@example.com"
+				})).Return(nil)
+			},
+		},
+		{
+			name:    "incorrect password",
+			input:   ChangeEmailInput{UserID: "user-1", NewEmail: "new@example.com", CurrentPassword: "wrong-password"},
+			wantErr: user.ErrIncorrectPassword,
+		},
+		{
+			name:    "same email",
+			input:   ChangeEmailInput{UserID: "user-1", NewEmail: "old@example.com", CurrentPassword: "password123"},
+			wantErr: user.ErrEmailUnchanged,
+		},
+		{
+			name:  "email taken or pending elsewhere",
+			input: ChangeEmailInput{UserID: "user-1", NewEmail: "taken@example.com", CurrentPassword: "password123"},
+			setup: func(repo *MockRepository, ds *MockDomainService, verifications *MockEmailVerificationRepository, pub *MockEventPublisher) {
+				ds.On("IsEmailUnique", mock.Anything, "taken@example.com").Return(false, nil)
+			},
+			wantErr: user.ErrUserAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing, err := user.NewUser("old@example.com", "Test User", "password123", testPolicy, testHasher)
+			require.NoError(t, err)
+			existing.ID = "user-1"
+
+			repo := new(MockRepository)
+			ds := new(MockDomainService)
+			verifications := new(MockEmailVerificationRepository)
+			pub := new(MockEventPublisher)
+			repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil)
+			if tt.setup != nil {
+				tt.setup(repo, ds, verifications, pub)
+			}
+
+			uc := NewChangeEmailUseCase(repo, ds, verifications, testHasher, pub, time.Hour, logger.New("test"))
+			result, err := uc.Execute(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				repo.AssertNotCalled(t, "UpdateEmail", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "old@example.com", result.Email)
+				assert.Equal(t, "new@example.com", result.PendingEmail)
+			}
+
+			repo.AssertExpectations(t)
+			ds.AssertExpectations(t)
+			verifications.AssertExpectations(t)
+			pub.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// ChangePasswordInput represents input data for changing a password
+type ChangePasswordInput struct {
+	UserID          string
+	CurrentPassword string
+	NewPassword     string
+}
+
+// ChangePasswordUseCase handles password change business flow
+type ChangePasswordUseCase struct {
+	repo           user.Repository
+	sessions       user.SessionRepository
+	history        user.PasswordHistoryRepository
+	hasher         user.PasswordHasher
+	passwordPolicy user.PasswordPolicy
+	eventPub       EventPublisher
+	logger         *logger.Logger
+}
+
+// NewChangePasswordUseCase creates a new use case instance
+func NewChangePasswordUseCase(
+	repo user.Repository,
+	sessions user.SessionRepository,
+	history user.PasswordHistoryRepository,
+	hasher user.PasswordHasher,
+	passwordPolicy user.PasswordPolicy,
+	eventPub EventPublisher,
+	logger *logger.Logger,
+) *ChangePasswordUseCase {
+	return &ChangePasswordUseCase{
+		repo:           repo,
+		sessions:       sessions,
+		history:        history,
+		hasher:         hasher,
+		passwordPolicy: passwordPolicy,
+		eventPub:       eventPub,
+		logger:         logger,
+	}
+}
+
+// Execute verifies the current password, stores the new one and signs the
+// user out of every session
+func (uc *ChangePasswordUseCase) Execute(ctx context.Context, input ChangePasswordInput) error {
+	uc.logger.WithField("user_id", input.UserID).Info("Changing user password")
+
+	// 1. Load existing user
+	u, err := uc.repo.GetByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 2. Verify current password
+	if err := u.CheckPassword(input.CurrentPassword, uc.hasher); err != nil {
+		return user.ErrIncorrectPassword
+	}
+
+	// 3. Apply domain change (with validation against recent passwords)
+	previousHashes, err := listPreviousPasswords(ctx, uc.history, uc.passwordPolicy, u.ID)
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to get password history from database")
+		return err
+	}
+	oldHash := u.Password
+	if err := u.ChangePassword(input.NewPassword, previousHashes, uc.passwordPolicy, uc.hasher); err != nil {
+		return fmt.Errorf("invalid password: %w", err)
+	}
+
+	// 4. Save to repository
+	if err := uc.repo.UpdatePassword(ctx, u); err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to update user password in database")
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if err := recordPreviousPassword(ctx, uc.history, uc.passwordPolicy, u.ID, oldHash, u.UpdatedAt); err != nil {
+		// The password is changed already, so don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to record password history")
+	}
+
+	// 5. Revoke sessions started with the old password
+	if err := revokeSessionsAfterPasswordChange(ctx, uc.sessions, u.ID); err != nil {
+		uc.logger.WithError(err).Error("Failed to revoke sessions after password change")
+		return err
+	}
+
+	// 6. Publish domain event
+	event := user.UserPasswordChangedEvent{
+		UserID:    u.ID,
+		ChangedAt: u.UpdatedAt,
+	}
+	if err := uc.eventPub.Publish(ctx, user.EventTypeUserPasswordChanged, event); err != nil {
+		// Don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to publish user password changed event")
+	}
+
+	uc.logger.WithField("user_id", u.ID).Info("User password changed successfully")
+
+	return nil
+}
+
+// listPreviousPasswords returns the replaced password hashes of the user that
+// policy checks new passwords against, newest first
+func listPreviousPasswords(ctx context.Context, history user.PasswordHistoryRepository, policy user.PasswordPolicy, userID string) ([]string, error) {
+	limit := policy.PreviousPasswordLimit()
+	if limit == 0 {
+		return nil, nil
+	}
+	hashes, err := history.ListRecent(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list password history: %w", err)
+	}
+	return hashes, nil
+}
+
+// recordPreviousPassword keeps a replaced password hash for as long as policy
+// needs it for reuse checks
+func recordPreviousPassword(ctx context.Context, history user.PasswordHistoryRepository, policy user.PasswordPolicy, userID, hash string, at time.Time) error {
+	keep := policy.PreviousPasswordLimit()
+	if keep == 0 {
+		return nil
+	}
+	if err := history.Add(ctx, userID, hash, at, keep); err != nil {
+		return fmt.Errorf("failed to record password history: %w", err)
+	}
+	return nil
+}
+
+// revokeSessionsAfterPasswordChange signs the user out everywhere so that
+// whoever knew the old password loses access
+func revokeSessionsAfterPasswordChange(ctx context.Context, sessions user.SessionRepository, userID string) error {
+	if _, err := sessions.RevokeAll(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPasswordHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordHistoryRepository) Add(ctx context.Context, userID, hash string, at time.Time, keep int) error {
+	args := m.Called(ctx, userID, hash, at, keep)
+	return args.Error(0)
+}
+
+func (m *MockPasswordHistoryRepository) ListRecent(ctx context.Context, userID string, limit int) ([]string, error) {
+	args := m.Called(ctx, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func TestChangePasswordUseCase_Execute(t *testing.T) {
+	previousHash, err := testHasher.Hash("previous-password")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		input   ChangePasswordInput
+		setup   func(*MockRepository, *MockSessionRepository, *MockPasswordHistoryRepository, *MockEventPublisher)
+		wantErr error
+	}{
+		{
+			name:  "successful password change",
+			input: ChangePasswordInput{UserID: "user-1", CurrentPassword: "password123", NewPassword: "new-password456"},
+			setup: func(repo *MockRepository, sessions *MockSessionRepository, history *MockPasswordHistoryRepository, pub *MockEventPublisher) {
+				repo.On("UpdatePassword", mock.Anything, mock.AnythingOfType("*user.User")).Return(nil)
+				history.On("Add", mock.Anything, "user-1", mock.AnythingOfType("string"), mock.Anything, 2).Return(nil)
+				sessions.On("RevokeAll", mock.Anything, "user-1", mock.Anything).Return(int64(2), nil)
+				pub.On("Publish", mock.Anything, user.EventTypeUserPasswordChanged, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:  "incorrect current password",
+			input: ChangePasswordInput{UserID: "user-1", CurrentPassword: "wrong-password", NewPassword: "new-password456"},
+			setup: func(repo *MockRepository, sessions *MockSessionRepository, history *MockPasswordHistoryRepository, pub *MockEventPublisher) {
+			},
+			wantErr: user.ErrIncorrectPassword,
+		},
+		{
+			name:  "weak new password",
+			input: ChangePasswordInput{UserID: "user-1", CurrentPassword: "password123", NewPassword: "short"},
+			setup: func(repo *MockRepository, sessions *MockSessionRepository, history *MockPasswordHistoryRepository, pub *MockEventPublisher) {
+			},
+			wantErr: user.ErrWeakPassword,
+		},
+		{
+			name:  "recently used new password",
+			input: ChangePasswordInput{UserID: "user-1", CurrentPassword: "password123", NewPassword: "previous-password"},
+			setup: func(repo *MockRepository, sessions *MockSessionRepository, history *MockPasswordHistoryRepository, pub *MockEventPublisher) {
+			},
+			wantErr: user.ErrWeakPassword,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing, err := user.NewUser("test@example.com", "Test User", "password123", testPolicy, testHasher)
+			require.NoError(t, err)
+			existing.ID = "user-1"
+
+			repo := new(MockRepository)
+			sessions := new(MockSessionRepository)
+			history := new(MockPasswordHistoryRepository)
+			pub := new(MockEventPublisher)
+			repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil)
+			history.On("ListRecent", mock.Anything, "user-1", 2).Return([]string{previousHash}, nil).Maybe()
+			tt.setup(repo, sessions, history, pub)
+
+			uc := NewChangePasswordUseCase(repo, sessions, history, testHasher, testPolicy, pub, logger.New("test"))
+			err = uc.Execute(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.NoError(t, existing.CheckPassword("password123", testHasher))
+			} else {
+				require.NoError(t, err)
+				assert.NoError(t, existing.CheckPassword(tt.input.NewPassword, testHasher))
+			}
+
+			repo.AssertExpectations(t)
+			sessions.AssertExpectations(t)
+			history.AssertExpectations(t)
+			pub.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// ConfirmPasswordResetInput represents a reset token and the new password
+type ConfirmPasswordResetInput struct {
+	Token       string
+	NewPassword string
+}
+
+// ConfirmPasswordResetUseCase handles setting a new password with a reset token
+type ConfirmPasswordResetUseCase struct {
+	repo           user.Repository
+	resets         user.PasswordResetRepository
+	sessions       user.SessionRepository
+	history        user.PasswordHistoryRepository
+	hasher         user.PasswordHasher
+	passwordPolicy user.PasswordPolicy
+	eventPub       EventPublisher
+	logger         *logger.Logger
+}
+
+// NewConfirmPasswordResetUseCase creates a new use case instance
+func NewConfirmPasswordResetUseCase(
+	repo user.Repository,
+	resets user.PasswordResetRepository,
+	sessions user.SessionRepository,
+	history user.PasswordHistoryRepository,
+	hasher user.PasswordHasher,
+	passwordPolicy user.PasswordPolicy,
+	eventPub EventPublisher,
+	logger *logger.Logger,
+) *ConfirmPasswordResetUseCase {
+	return &ConfirmPasswordResetUseCase{
+		repo:           repo,
+		resets:         resets,
+		sessions:       sessions,
+		history:        history,
+		hasher:         hasher,
+		passwordPolicy: passwordPolicy,
+		eventPub:       eventPub,
+		logger:         logger,
+	}
+}
+
+// Execute consumes the reset token, sets the new password and signs the user
+// out of every session. Unknown, used and expired tokens all return
+// user.ErrInvalidResetToken.
+func (uc *ConfirmPasswordResetUseCase) Execute(ctx context.Context, input ConfirmPasswordResetInput) error {
+	uc.logger.Info("Confirming password reset")
+
+	// 1. Look up reset token
+	resetToken, err := uc.resets.GetByHash(ctx, auth.HashSecretToken(input.Token))
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidResetToken) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to get password reset token from database")
+		return fmt.Errorf("failed to get password reset token: %w", err)
+	}
+	now := time.Now()
+	if !resetToken.IsUsable(now) {
+		return user.ErrInvalidResetToken
+	}
+
+	// 2. Load user and apply domain change (with validation)
+	// The password is validated before the token is consumed, so that a
+	// weak password does not burn the token
+	u, err := uc.repo.GetByID(ctx, resetToken.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return user.ErrInvalidResetToken
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	previousHashes, err := listPreviousPasswords(ctx, uc.history, uc.passwordPolicy, u.ID)
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to get password history from database")
+		return err
+	}
+	oldHash := u.Password
+	if err := u.ChangePassword(input.NewPassword, previousHashes, uc.passwordPolicy, uc.hasher); err != nil {
+		return fmt.Errorf("invalid password: %w", err)
+	}
+
+	// 3. Consume token
+	// A concurrent confirmation with the same token loses here
+	if err := uc.resets.Consume(ctx, resetToken, now); err != nil {
+		if errors.Is(err, user.ErrInvalidResetToken) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to consume password reset token")
+		return fmt.Errorf("failed to consume password reset token: %w", err)
+	}
+
+	// 4. Save to repository
+	if err := uc.repo.UpdatePassword(ctx, u); err != nil {
+		uc.logger.WithError(err).Error("Failed to update user password in database")
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if err := recordPreviousPassword(ctx, uc.history, uc.passwordPolicy, u.ID, oldHash, u.UpdatedAt); err != nil {
+		// The password is changed already, so don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to record password history")
+	}
+
+	// 5. Revoke sessions started with the old password
+	if err := revokeSessionsAfterPasswordChange(ctx, uc.sessions, u.ID); err != nil {
+		uc.logger.WithError(err).Error("Failed to revoke sessions after password reset")
+		return err
+	}
+
+	// 6. Publish domain event
+	event := user.UserPasswordChangedEvent{
+		UserID:    u.ID,
+		ChangedAt: u.UpdatedAt,
+	}
+	if err := uc.eventPub.Publish(ctx, user.EventTypeUserPasswordChanged, event); err != nil {
+		// Don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to publish user password changed event")
+	}
+
+	uc.logger.WithField("user_id", u.ID).Info("Password reset successfully")
+
+	return nil
+}
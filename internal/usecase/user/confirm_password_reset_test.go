@@ -0,0 +1,120 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmPasswordResetUseCase_Execute(t *testing.T) {
+	const token = "reset-token"
+	tokenHash := auth.HashSecretToken(token)
+	usedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name       string
+		resetToken *user.PasswordResetToken
+		password   string
+		setup      func(*MockRepository, *MockPasswordResetRepository, *MockSessionRepository, *MockPasswordHistoryRepository, *MockEventPublisher)
+		wantErr    error
+	}{
+		{
+			name:       "successful reset",
+			resetToken: user.NewPasswordResetToken(tokenHash, "user-1", time.Hour),
+			password:   "new-password456",
+			setup: func(repo *MockRepository, resets *MockPasswordResetRepository, sessions *MockSessionRepository, history *MockPasswordHistoryRepository, pub *MockEventPublisher) {
+				resets.On("Consume", mock.Anything, mock.AnythingOfType("*user.PasswordResetToken"), mock.Anything).Return(nil)
+				repo.On("UpdatePassword", mock.Anything, mock.AnythingOfType("*user.User")).Return(nil)
+				history.On("Add", mock.Anything, "user-1", mock.AnythingOfType("string"), mock.Anything, 2).Return(nil)
+				sessions.On("RevokeAll", mock.Anything, "user-1", mock.Anything).Return(int64(1), nil)
+				pub.On("Publish", mock.Anything, user.EventTypeUserPasswordChanged, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:       "expired token",
+			resetToken: user.NewPasswordResetToken(tokenHash, "user-1", -time.Minute),
+			password:   "new-password456",
+			wantErr:    user.ErrInvalidResetToken,
+		},
+		{
+			name:       "used token",
+			resetToken: &user.PasswordResetToken{TokenHash: tokenHash, UserID: "user-1", ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt},
+			password:   "new-password456",
+			wantErr:    user.ErrInvalidResetToken,
+		},
+		{
+			name:       "weak password keeps token",
+			resetToken: user.NewPasswordResetToken(tokenHash, "user-1", time.Hour),
+			password:   "short",
+			wantErr:    user.ErrWeakPassword,
+		},
+		{
+			name:       "current password keeps token",
+			resetToken: user.NewPasswordResetToken(tokenHash, "user-1", time.Hour),
+			password:   "password123",
+			wantErr:    user.ErrWeakPassword,
+		},
+		{
+			name:       "token consumed concurrently",
+			resetToken: user.NewPasswordResetToken(tokenHash, "user-1", time.Hour),
+			password:   "new-password456",
+			setup: func(repo *MockRepository, resets *MockPasswordResetRepository, sessions *MockSessionRepository, history *MockPasswordHistoryRepository, pub *MockEventPublisher) {
+				resets.On("Consume", mock.Anything, mock.Anything, mock.Anything).Return(user.ErrInvalidResetToken)
+			},
+			wantErr: user.ErrInvalidResetToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing, err := user.NewUser("test@example.com", "Test User", "password123", testPolicy, testHasher)
+			require.NoError(t, err)
+			existing.ID = "user-1"
+
+			repo := new(MockRepository)
+			resets := new(MockPasswordResetRepository)
+			sessions := new(MockSessionRepository)
+			history := new(MockPasswordHistoryRepository)
+			pub := new(MockEventPublisher)
+			resets.On("GetByHash", mock.Anything, tokenHash).Return(tt.resetToken, nil)
+			repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil).Maybe()
+			history.On("ListRecent", mock.Anything, "user-1", 2).Return([]string{}, nil).Maybe()
+			if tt.setup != nil {
+				tt.setup(repo, resets, sessions, history, pub)
+			}
+
+			uc := NewConfirmPasswordResetUseCase(repo, resets, sessions, history, testHasher, testPolicy, pub, logger.New("test"))
+			err = uc.Execute(context.Background(), ConfirmPasswordResetInput{Token: token, NewPassword: tt.password})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.NoError(t, existing.CheckPassword(tt.password, testHasher))
+			}
+
+			repo.AssertExpectations(t)
+			resets.AssertExpectations(t)
+			sessions.AssertExpectations(t)
+			history.AssertExpectations(t)
+			pub.AssertExpectations(t)
+		})
+	}
+
+	t.Run("unknown token", func(t *testing.T) {
+		resets := new(MockPasswordResetRepository)
+		resets.On("GetByHash", mock.Anything, auth.HashSecretToken("unknown")).Return(nil, user.ErrInvalidResetToken)
+
+		uc := NewConfirmPasswordResetUseCase(new(MockRepository), resets, new(MockSessionRepository), new(MockPasswordHistoryRepository), testHasher, testPolicy, new(MockEventPublisher), logger.New("test"))
+		err := uc.Execute(context.Background(), ConfirmPasswordResetInput{Token: "unknown", NewPassword: "new-password456"})
+		assert.ErrorIs(t, err, user.ErrInvalidResetToken)
+	})
+}
@@ -0,0 +1,99 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// ConfirmTOTPEnrollmentInput represents a code from the authenticator app the
+// secret was added to
+type ConfirmTOTPEnrollmentInput struct {
+	UserID string
+	Code   string
+}
+
+// ConfirmTOTPEnrollmentOutput represents the one-time recovery codes, shown
+// to the user only once
+type ConfirmTOTPEnrollmentOutput struct {
+	RecoveryCodes []string
+}
+
+// ConfirmTOTPEnrollmentUseCase handles enabling two-factor authentication
+type ConfirmTOTPEnrollmentUseCase struct {
+	twoFactor user.TwoFactorRepository
+	eventPub  EventPublisher
+	logger    *logger.Logger
+}
+
+// NewConfirmTOTPEnrollmentUseCase creates a new use case instance
+func NewConfirmTOTPEnrollmentUseCase(
+	twoFactor user.TwoFactorRepository,
+	eventPub EventPublisher,
+	logger *logger.Logger,
+) *ConfirmTOTPEnrollmentUseCase {
+	return &ConfirmTOTPEnrollmentUseCase{
+		twoFactor: twoFactor,
+		eventPub:  eventPub,
+		logger:    logger,
+	}
+}
+
+// Execute enables two-factor authentication once the code proves the user
+// added the secret to their authenticator app, and issues recovery codes
+// that replace any earlier ones
+func (uc *ConfirmTOTPEnrollmentUseCase) Execute(ctx context.Context, input ConfirmTOTPEnrollmentInput) (*ConfirmTOTPEnrollmentOutput, error) {
+	uc.logger.WithField("user_id", input.UserID).Info("Confirming TOTP enrollment")
+
+	// 1. Load pending secret
+	userTOTP, err := uc.twoFactor.GetTOTP(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrTwoFactorNotEnrolled) || errors.Is(err, user.ErrUserNotFound) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to get TOTP secret")
+		return nil, fmt.Errorf("failed to get totp secret: %w", err)
+	}
+
+	// 2. Verify code and apply domain change
+	step, err := validateTOTPCode(userTOTP, input.Code)
+	if err != nil {
+		return nil, err
+	}
+	if err := userTOTP.Enable(step); err != nil {
+		return nil, err
+	}
+
+	// 3. Generate recovery codes
+	codes, hashes, err := auth.NewRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Persist
+	if err := uc.twoFactor.EnableTOTP(ctx, userTOTP, hashes); err != nil {
+		if errors.Is(err, user.ErrTwoFactorAlreadyEnabled) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to enable TOTP")
+		return nil, fmt.Errorf("failed to enable totp: %w", err)
+	}
+
+	// 5. Publish domain event
+	event := user.UserTwoFactorEnabledEvent{
+		UserID:    userTOTP.UserID,
+		EnabledAt: *userTOTP.EnabledAt,
+	}
+	if err := uc.eventPub.Publish(ctx, user.EventTypeUserTwoFactorEnabled, event); err != nil {
+		// Don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to publish two-factor enabled event")
+	}
+
+	uc.logger.WithField("user_id", userTOTP.UserID).Info("Two-factor authentication enabled")
+
+	return &ConfirmTOTPEnrollmentOutput{RecoveryCodes: codes}, nil
+}
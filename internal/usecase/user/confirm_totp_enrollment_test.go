@@ -0,0 +1,137 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/memclutter/go-microservices-template/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTwoFactorRepository struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorRepository) GetTOTP(ctx context.Context, userID string) (*user.TOTP, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.TOTP), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) SaveTOTP(ctx context.Context, t *user.TOTP) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) EnableTOTP(ctx context.Context, t *user.TOTP, recoveryCodeHashes []string) error {
+	args := m.Called(ctx, t, recoveryCodeHashes)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) UseTOTPStep(ctx context.Context, t *user.TOTP) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string, at time.Time) error {
+	args := m.Called(ctx, userID, codeHash, at)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) DisableTOTP(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// currentTOTPCode returns the code of secret for the current period
+func currentTOTPCode(t *testing.T, secret string) string {
+	t.Helper()
+	code, err := totp.Code(secret, time.Now())
+	require.NoError(t, err)
+	return code
+}
+
+func TestConfirmTOTPEnrollmentUseCase_Execute(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	var storedHashes []string
+
+	tests := []struct {
+		name    string
+		pending *user.TOTP
+		code    string
+		setup   func(*MockTwoFactorRepository, *MockEventPublisher)
+		wantErr error
+	}{
+		{
+			name:    "valid code",
+			pending: user.NewTOTP("user-1", secret),
+			code:    currentTOTPCode(t, secret),
+			setup: func(twoFactor *MockTwoFactorRepository, pub *MockEventPublisher) {
+				twoFactor.On("EnableTOTP", mock.Anything, mock.MatchedBy(func(t *user.TOTP) bool {
+					return t.IsEnabled() && t.LastUsedStep >= totp.Step(time.Now())-totp.Skew
+				}), mock.MatchedBy(func(hashes []string) bool {
+					storedHashes = hashes
+					return len(hashes) == recoveryCodeCount
+				})).Return(nil)
+				pub.On("Publish", mock.Anything, user.EventTypeUserTwoFactorEnabled, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:    "wrong code",
+			pending: user.NewTOTP("user-1", secret),
+			code:    "000000",
+			setup:   func(*MockTwoFactorRepository, *MockEventPublisher) {},
+			wantErr: user.ErrInvalidTwoFactorCode,
+		},
+		{
+			name:    "already enabled",
+			pending: &user.TOTP{UserID: "user-1", Secret: secret, EnabledAt: &enabledAt},
+			code:    currentTOTPCode(t, secret),
+			setup:   func(*MockTwoFactorRepository, *MockEventPublisher) {},
+			wantErr: user.ErrTwoFactorAlreadyEnabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twoFactor := new(MockTwoFactorRepository)
+			pub := new(MockEventPublisher)
+			twoFactor.On("GetTOTP", mock.Anything, "user-1").Return(tt.pending, nil)
+			tt.setup(twoFactor, pub)
+
+			uc := NewConfirmTOTPEnrollmentUseCase(twoFactor, pub, logger.New("test"))
+			result, err := uc.Execute(context.Background(), ConfirmTOTPEnrollmentInput{UserID: "user-1", Code: tt.code})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, result.RecoveryCodes, recoveryCodeCount)
+				assert.Equal(t, auth.HashRecoveryCode(result.RecoveryCodes[0]), storedHashes[0], "only hashes are stored")
+			}
+
+			twoFactor.AssertExpectations(t)
+			pub.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConfirmTOTPEnrollmentUseCase_NotStarted(t *testing.T) {
+	twoFactor := new(MockTwoFactorRepository)
+	twoFactor.On("GetTOTP", mock.Anything, "user-1").Return(nil, user.ErrTwoFactorNotEnrolled)
+
+	uc := NewConfirmTOTPEnrollmentUseCase(twoFactor, new(MockEventPublisher), logger.New("test"))
+	_, err := uc.Execute(context.Background(), ConfirmTOTPEnrollmentInput{UserID: "user-1", Code: "123456"})
+	assert.ErrorIs(t, err, user.ErrTwoFactorNotEnrolled)
+}
@@ -0,0 +1,133 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// ConsumeMagicLinkInput represents the token sent to the user and the client starting the session
+type ConsumeMagicLinkInput struct {
+	Token     string
+	UserAgent string
+	IPAddress string
+}
+
+// ConsumeMagicLinkOutput represents the result of a successful login,
+// the same as for logging in with a password
+type ConsumeMagicLinkOutput struct {
+	LoginOutput
+}
+
+// ConsumeMagicLinkUseCase handles logging in with magic link tokens
+type ConsumeMagicLinkUseCase struct {
+	repo                 user.Repository
+	links                user.MagicLinkRepository
+	sessions             user.SessionRepository
+	challenges           user.LoginChallengeRepository
+	tokens               TokenIssuer
+	refreshTTL           time.Duration
+	challengeTTL         time.Duration
+	requireVerifiedEmail bool
+	logger               *logger.Logger
+}
+
+// NewConsumeMagicLinkUseCase creates a new use case instance
+func NewConsumeMagicLinkUseCase(
+	repo user.Repository,
+	links user.MagicLinkRepository,
+	sessions user.SessionRepository,
+	challenges user.LoginChallengeRepository,
+	tokens TokenIssuer,
+	refreshTTL time.Duration,
+	challengeTTL time.Duration,
+	requireVerifiedEmail bool,
+	logger *logger.Logger,
+) *ConsumeMagicLinkUseCase {
+	return &ConsumeMagicLinkUseCase{
+		repo:                 repo,
+		links:                links,
+		sessions:             sessions,
+		challenges:           challenges,
+		tokens:               tokens,
+		refreshTTL:           refreshTTL,
+		challengeTTL:         challengeTTL,
+		requireVerifiedEmail: requireVerifiedEmail,
+		logger:               logger,
+	}
+}
+
+// Execute consumes the token, invalidating the user's other magic links, and
+// starts a session. Unknown, used and expired tokens return user.ErrInvalidMagicLink.
+// The link stands in for the password only: users with two-factor
+// authentication enabled get a login challenge instead of a session.
+func (uc *ConsumeMagicLinkUseCase) Execute(ctx context.Context, input ConsumeMagicLinkInput) (*ConsumeMagicLinkOutput, error) {
+	uc.logger.Info("Consuming magic link")
+
+	// 1. Find usable token
+	link, err := uc.links.GetByHash(ctx, auth.HashSecretToken(input.Token))
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidMagicLink) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to get magic link token")
+		return nil, fmt.Errorf("failed to get magic link token: %w", err)
+	}
+	if !link.IsUsable(time.Now()) {
+		return nil, user.ErrInvalidMagicLink
+	}
+
+	// 2. Load user
+	u, err := uc.repo.GetByID(ctx, link.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, user.ErrInvalidMagicLink
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	// Like every other login, a magic link does not get past an unverified
+	// email. It does not verify the email either, as the link may have gone to
+	// an address the user has since replaced. The link stays usable, so it
+	// works once the email is verified.
+	if uc.requireVerifiedEmail && !u.IsEmailVerified() {
+		uc.logger.WithField("user_id", u.ID).Info("Login failed: email not verified")
+		return nil, user.ErrEmailNotVerified
+	}
+
+	// 3. Consume token
+	if err := uc.links.Consume(ctx, link, time.Now()); err != nil {
+		if errors.Is(err, user.ErrInvalidMagicLink) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to consume magic link token")
+		return nil, fmt.Errorf("failed to consume magic link token: %w", err)
+	}
+
+	// 4. Require second factor
+	if u.IsTwoFactorEnabled() {
+		output, err := issueLoginChallenge(ctx, uc.challenges, u.ID, uc.challengeTTL, uc.logger)
+		if err != nil {
+			return nil, err
+		}
+		return &ConsumeMagicLinkOutput{LoginOutput: *output}, nil
+	}
+
+	// 5. Start session and issue its tokens
+	tokens, err := startSession(ctx, uc.sessions, uc.tokens, u.ID, input.UserAgent, input.IPAddress, uc.refreshTTL, uc.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.WithFields(map[string]any{
+		"user_id":    u.ID,
+		"session_id": tokens.SessionID,
+	}).Info("User logged in successfully with magic link")
+
+	return &ConsumeMagicLinkOutput{LoginOutput: LoginOutput{SessionTokens: *tokens}}, nil
+}
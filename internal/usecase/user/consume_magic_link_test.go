@@ -0,0 +1,118 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumeMagicLinkUseCase_Execute(t *testing.T) {
+	existing, err := user.NewUser("test@example.com", "Test User", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+	existing.ID = "user-1"
+	expiresAt := time.Now().Add(15 * time.Minute)
+
+	newLink := func() *user.MagicLinkToken {
+		return user.NewMagicLinkToken(auth.HashSecretToken("link-token"), "user-1", 15*time.Minute)
+	}
+
+	t.Run("starts a session", func(t *testing.T) {
+		repo := new(MockRepository)
+		links := new(MockMagicLinkRepository)
+		sessions := new(MockSessionRepository)
+		tokens := new(MockTokenIssuer)
+		link := newLink()
+		links.On("GetByHash", mock.Anything, link.TokenHash).Return(link, nil)
+		repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil)
+		links.On("Consume", mock.Anything, link, mock.Anything).Return(nil)
+		sessions.On("Create", mock.Anything, mock.MatchedBy(func(s *user.Session) bool {
+			return s.UserID == "user-1" && s.UserAgent == "curl/8.0"
+		})).Return(nil)
+		tokens.On("IssueAccessToken", "user-1", mock.Anything).Return("token", expiresAt, nil)
+
+		uc := NewConsumeMagicLinkUseCase(repo, links, sessions, new(MockLoginChallengeRepository), tokens, time.Hour, 5*time.Minute, false, logger.New("test"))
+		result, err := uc.Execute(context.Background(), ConsumeMagicLinkInput{Token: "link-token", UserAgent: "curl/8.0"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "token", result.AccessToken)
+		assert.NotEmpty(t, result.RefreshToken)
+		assert.False(t, result.TwoFactorRequired)
+		links.AssertExpectations(t)
+	})
+
+	t.Run("requires second factor", func(t *testing.T) {
+		twoFactorUser := *existing
+		enabledAt := time.Now()
+		twoFactorUser.TwoFactorEnabledAt = &enabledAt
+
+		repo := new(MockRepository)
+		links := new(MockMagicLinkRepository)
+		sessions := new(MockSessionRepository)
+		challenges := new(MockLoginChallengeRepository)
+		link := newLink()
+		links.On("GetByHash", mock.Anything, link.TokenHash).Return(link, nil)
+		repo.On("GetByID", mock.Anything, "user-1").Return(&twoFactorUser, nil)
+		links.On("Consume", mock.Anything, link, mock.Anything).Return(nil)
+		challenges.On("Create", mock.Anything, mock.MatchedBy(func(c *user.LoginChallenge) bool {
+			return c.UserID == "user-1"
+		})).Return(nil)
+
+		uc := NewConsumeMagicLinkUseCase(repo, links, sessions, challenges, new(MockTokenIssuer), time.Hour, 5*time.Minute, false, logger.New("test"))
+		result, err := uc.Execute(context.Background(), ConsumeMagicLinkInput{Token: "link-token"})
+		require.NoError(t, err)
+
+		assert.True(t, result.TwoFactorRequired)
+		assert.NotEmpty(t, result.ChallengeToken)
+		assert.Empty(t, result.AccessToken)
+		sessions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unverified email when verification is required", func(t *testing.T) {
+		repo := new(MockRepository)
+		links := new(MockMagicLinkRepository)
+		sessions := new(MockSessionRepository)
+		link := newLink()
+		links.On("GetByHash", mock.Anything, link.TokenHash).Return(link, nil)
+		repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil)
+
+		uc := NewConsumeMagicLinkUseCase(repo, links, sessions, new(MockLoginChallengeRepository), new(MockTokenIssuer), time.Hour, 5*time.Minute, true, logger.New("test"))
+		_, err := uc.Execute(context.Background(), ConsumeMagicLinkInput{Token: "link-token"})
+		assert.ErrorIs(t, err, user.ErrEmailNotVerified)
+		links.AssertNotCalled(t, "Consume", mock.Anything, mock.Anything, mock.Anything)
+		sessions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		links := new(MockMagicLinkRepository)
+		link := newLink()
+		link.ExpiresAt = time.Now().Add(-time.Minute)
+		links.On("GetByHash", mock.Anything, link.TokenHash).Return(link, nil)
+
+		uc := NewConsumeMagicLinkUseCase(new(MockRepository), links, new(MockSessionRepository), new(MockLoginChallengeRepository), new(MockTokenIssuer), time.Hour, 5*time.Minute, false, logger.New("test"))
+		_, err := uc.Execute(context.Background(), ConsumeMagicLinkInput{Token: "link-token"})
+		assert.ErrorIs(t, err, user.ErrInvalidMagicLink)
+		links.AssertNotCalled(t, "Consume", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("token used concurrently", func(t *testing.T) {
+		repo := new(MockRepository)
+		links := new(MockMagicLinkRepository)
+		sessions := new(MockSessionRepository)
+		link := newLink()
+		links.On("GetByHash", mock.Anything, link.TokenHash).Return(link, nil)
+		repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil)
+		links.On("Consume", mock.Anything, link, mock.Anything).Return(user.ErrInvalidMagicLink)
+
+		uc := NewConsumeMagicLinkUseCase(repo, links, sessions, new(MockLoginChallengeRepository), new(MockTokenIssuer), time.Hour, 5*time.Minute, false, logger.New("test"))
+		_, err := uc.Execute(context.Background(), ConsumeMagicLinkInput{Token: "link-token"})
+		assert.ErrorIs(t, err, user.ErrInvalidMagicLink)
+		sessions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
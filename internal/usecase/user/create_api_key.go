@@ -0,0 +1,121 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// CreateAPIKeyInput represents input for creating an API key
+type CreateAPIKeyInput struct {
+	UserID string
+	Name   string
+	// Scopes are the full gRPC method names the key may call
+	Scopes []string
+	// ExpiresAt is nil for keys that do not expire
+	ExpiresAt *time.Time
+	// CallerAPIKeyID is the key the request was authenticated with, empty for
+	// access tokens. Such a key can only create keys within its own scopes.
+	CallerAPIKeyID string
+}
+
+// CreateAPIKeyOutput represents the created key
+type CreateAPIKeyOutput struct {
+	// Key is the secret itself, which cannot be retrieved later
+	Key    string
+	APIKey *user.APIKey
+}
+
+// CreateAPIKeyUseCase handles creating API keys for service-to-service callers
+type CreateAPIKeyUseCase struct {
+	keys     user.APIKeyRepository
+	scopes   []string
+	eventPub EventPublisher
+	logger   *logger.Logger
+}
+
+// NewCreateAPIKeyUseCase creates a new use case instance. scopes are the full
+// names of the RPC methods keys may be scoped to.
+func NewCreateAPIKeyUseCase(keys user.APIKeyRepository, scopes []string, eventPub EventPublisher, logger *logger.Logger) *CreateAPIKeyUseCase {
+	return &CreateAPIKeyUseCase{
+		keys:     keys,
+		scopes:   scopes,
+		eventPub: eventPub,
+		logger:   logger,
+	}
+}
+
+// Execute generates an API key acting as the user. Only its prefix and hash
+// are stored, so the key is returned once.
+func (uc *CreateAPIKeyUseCase) Execute(ctx context.Context, input CreateAPIKeyInput) (*CreateAPIKeyOutput, error) {
+	uc.logger.WithField("user_id", input.UserID).Info("Creating API key")
+
+	// 1. Keep keys from granting scopes they do not hold
+	if input.CallerAPIKeyID != "" {
+		caller, err := uc.keys.GetByID(ctx, input.CallerAPIKeyID)
+		if err != nil {
+			if errors.Is(err, user.ErrAPIKeyNotFound) {
+				return nil, user.ErrInvalidAPIKey
+			}
+			uc.logger.WithError(err).Error("Failed to get calling API key")
+			return nil, fmt.Errorf("failed to get api key: %w", err)
+		}
+		for _, scope := range input.Scopes {
+			if !caller.Allows(scope) {
+				uc.logger.WithFields(map[string]any{
+					"api_key_id": caller.ID,
+					"scope":      scope,
+				}).Warn("API key tried to grant a scope it does not hold")
+				return nil, user.ErrAPIKeyScopeNotHeld
+			}
+		}
+	}
+
+	// 2. Generate key
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Create domain entity
+	apiKey, err := user.NewAPIKey(uuid.New().String(), input.UserID, input.Name, prefix, hash, input.Scopes, uc.scopes, input.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Persist
+	if err := uc.keys.Create(ctx, apiKey); err != nil {
+		if errors.Is(err, user.ErrUserNotFound) || errors.Is(err, user.ErrAPIKeyAlreadyExists) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to create API key")
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	// 5. Publish domain event
+	event := user.APIKeyCreatedEvent{
+		UserID:    apiKey.UserID,
+		APIKeyID:  apiKey.ID,
+		Name:      apiKey.Name,
+		Scopes:    apiKey.Scopes,
+		ExpiresAt: apiKey.ExpiresAt,
+		CreatedAt: apiKey.CreatedAt,
+	}
+	if err := uc.eventPub.Publish(ctx, user.EventTypeAPIKeyCreated, event); err != nil {
+		// Don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to publish API key created event")
+	}
+
+	uc.logger.WithFields(map[string]any{
+		"user_id":    apiKey.UserID,
+		"api_key_id": apiKey.ID,
+	}).Info("API key created successfully")
+
+	return &CreateAPIKeyOutput{Key: key, APIKey: apiKey}, nil
+}
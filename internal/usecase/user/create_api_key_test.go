@@ -0,0 +1,148 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAPIKeyRepository is a mock implementation of user.APIKeyRepository
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, k *user.APIKey) error {
+	args := m.Called(ctx, k)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetByID(ctx context.Context, id string) (*user.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*user.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) ListByUser(ctx context.Context, userID string) ([]*user.APIKey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*user.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, k *user.APIKey, at time.Time) error {
+	args := m.Called(ctx, k, at)
+	if args.Error(0) == nil {
+		k.RevokedAt = &at
+	}
+	return args.Error(0)
+}
+
+func TestCreateAPIKeyUseCase_Execute(t *testing.T) {
+	scopes := []string{"/user.UserService/GetUser"}
+	allScopes := []string{"/user.UserService/GetUser", "/user.UserService/CreateAPIKey"}
+
+	t.Run("stores only prefix and hash", func(t *testing.T) {
+		keys := new(MockAPIKeyRepository)
+		pub := new(MockEventPublisher)
+		var stored *user.APIKey
+		keys.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*user.APIKey)
+		}).Return(nil)
+		pub.On("Publish", mock.Anything, user.EventTypeAPIKeyCreated, mock.Anything).Return(nil)
+
+		uc := NewCreateAPIKeyUseCase(keys, scopes, pub, logger.New("test"))
+		result, err := uc.Execute(context.Background(), CreateAPIKeyInput{UserID: "user-1", Name: "billing-service", Scopes: scopes})
+		require.NoError(t, err)
+
+		require.NotNil(t, stored)
+		assert.Equal(t, "user-1", stored.UserID)
+		assert.Equal(t, scopes, stored.Scopes)
+		assert.True(t, strings.HasPrefix(result.Key, stored.Prefix))
+		assert.Equal(t, auth.HashAPIKey(result.Key), stored.KeyHash)
+		assert.NotContains(t, stored.KeyHash, result.Key)
+		assert.Nil(t, stored.ExpiresAt)
+		pub.AssertExpectations(t)
+	})
+
+	t.Run("invalid scopes", func(t *testing.T) {
+		keys := new(MockAPIKeyRepository)
+
+		uc := NewCreateAPIKeyUseCase(keys, scopes, new(MockEventPublisher), logger.New("test"))
+		_, err := uc.Execute(context.Background(), CreateAPIKeyInput{UserID: "user-1", Name: "billing-service"})
+		assert.ErrorIs(t, err, user.ErrInvalidAPIKeyScope)
+		keys.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown scope", func(t *testing.T) {
+		keys := new(MockAPIKeyRepository)
+
+		uc := NewCreateAPIKeyUseCase(keys, scopes, new(MockEventPublisher), logger.New("test"))
+		_, err := uc.Execute(context.Background(), CreateAPIKeyInput{
+			UserID: "user-1",
+			Name:   "billing-service",
+			Scopes: []string{"/user.UserService/GetUsers"},
+		})
+		assert.ErrorIs(t, err, user.ErrInvalidAPIKeyScope)
+		keys.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("api key grants scopes it holds", func(t *testing.T) {
+		keys := new(MockAPIKeyRepository)
+		pub := new(MockEventPublisher)
+		keys.On("GetByID", mock.Anything, "key-1").Return(&user.APIKey{ID: "key-1", UserID: "user-1", Scopes: scopes}, nil)
+		keys.On("Create", mock.Anything, mock.Anything).Return(nil)
+		pub.On("Publish", mock.Anything, user.EventTypeAPIKeyCreated, mock.Anything).Return(nil)
+
+		uc := NewCreateAPIKeyUseCase(keys, allScopes, pub, logger.New("test"))
+		_, err := uc.Execute(context.Background(), CreateAPIKeyInput{
+			UserID:         "user-1",
+			Name:           "billing-service",
+			Scopes:         scopes,
+			CallerAPIKeyID: "key-1",
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("api key cannot grant scopes it does not hold", func(t *testing.T) {
+		keys := new(MockAPIKeyRepository)
+		keys.On("GetByID", mock.Anything, "key-1").Return(&user.APIKey{ID: "key-1", UserID: "user-1", Scopes: scopes}, nil)
+
+		uc := NewCreateAPIKeyUseCase(keys, allScopes, new(MockEventPublisher), logger.New("test"))
+		_, err := uc.Execute(context.Background(), CreateAPIKeyInput{
+			UserID:         "user-2",
+			Name:           "billing-service",
+			Scopes:         allScopes,
+			CallerAPIKeyID: "key-1",
+		})
+		assert.ErrorIs(t, err, user.ErrAPIKeyScopeNotHeld)
+		keys.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing user", func(t *testing.T) {
+		keys := new(MockAPIKeyRepository)
+		keys.On("Create", mock.Anything, mock.Anything).Return(user.ErrUserNotFound)
+
+		uc := NewCreateAPIKeyUseCase(keys, scopes, new(MockEventPublisher), logger.New("test"))
+		_, err := uc.Execute(context.Background(), CreateAPIKeyInput{UserID: "missing", Name: "billing-service", Scopes: scopes})
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+	})
+}
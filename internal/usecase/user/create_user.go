@@ -2,7 +2,9 @@ package user
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/memclutter/go-microservices-template/internal/domain/user"
@@ -21,28 +23,47 @@ type CreateUserOutput struct {
 	UserID string
 	Email  string
 	Name   string
+	Roles  []string
+
+	EmailVerified bool
+	Version       int64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 // CreateUserUseCase handles user creation business flow
 type CreateUserUseCase struct {
-	repo          user.Repository
-	domainService user.Service
-	eventPub      EventPublisher
-	logger        *logger.Logger
+	repo            user.Repository
+	domainService   user.Service
+	verifications   user.EmailVerificationRepository
+	hasher          user.PasswordHasher
+	passwordPolicy  user.PasswordPolicy
+	eventPub        EventPublisher
+	verificationTTL time.Duration
+	logger          *logger.Logger
 }
 
 // NewCreateUserUseCase creates a new use case instance
 func NewCreateUserUseCase(
 	repo user.Repository,
 	domainService user.Service,
+	verifications user.EmailVerificationRepository,
+	hasher user.PasswordHasher,
+	passwordPolicy user.PasswordPolicy,
 	eventPub EventPublisher,
+	verificationTTL time.Duration,
 	logger *logger.Logger,
 ) *CreateUserUseCase {
 	return &CreateUserUseCase{
-		repo:          repo,
-		domainService: domainService,
-		eventPub:      eventPub,
-		logger:        logger,
+		repo:            repo,
+		domainService:   domainService,
+		verifications:   verifications,
+		hasher:          hasher,
+		passwordPolicy:  passwordPolicy,
+		eventPub:        eventPub,
+		verificationTTL: verificationTTL,
+		logger:          logger,
 	}
 }
 
@@ -65,7 +86,7 @@ func (uc *CreateUserUseCase) Execute(ctx context.Context, input CreateUserInput)
 	}
 
 	// 2. Create domain entity (with validation)
-	newUser, err := user.NewUser(input.Email, input.Name, input.Password)
+	newUser, err := user.NewUser(input.Email, input.Name, input.Password, uc.passwordPolicy, uc.hasher)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user data: %w", err)
 	}
@@ -74,7 +95,12 @@ func (uc *CreateUserUseCase) Execute(ctx context.Context, input CreateUserInput)
 	newUser.ID = uuid.New().String()
 
 	// 4. Save to repository
+	// The uniqueness check above can race with a concurrent sign-up,
+	// in which case the repository reports the conflict itself
 	if err := uc.repo.Create(ctx, newUser); err != nil {
+		if errors.Is(err, user.ErrUserAlreadyExists) {
+			return nil, user.ErrUserAlreadyExists
+		}
 		uc.logger.WithError(err).Error("Failed to create user in database")
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
@@ -91,11 +117,24 @@ func (uc *CreateUserUseCase) Execute(ctx context.Context, input CreateUserInput)
 		uc.logger.WithError(err).Warn("Failed to publish user created event")
 	}
 
+	// 6. Request email verification
+	if err := requestEmailVerification(ctx, uc.verifications, uc.eventPub, newUser, newUser.Email, uc.verificationTTL); err != nil {
+		// The account exists already, so don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to request email verification")
+	}
+
 	uc.logger.WithField("user_id", newUser.ID).Info("User created successfully")
 
 	return &CreateUserOutput{
 		UserID: newUser.ID,
 		Email:  newUser.Email,
 		Name:   newUser.Name,
+		Roles:  newUser.RoleNames(),
+
+		EmailVerified: newUser.IsEmailVerified(),
+		Version:       newUser.Version,
+
+		CreatedAt: newUser.CreatedAt,
+		UpdatedAt: newUser.UpdatedAt,
 	}, nil
 }
@@ -2,10 +2,13 @@ package user
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/memclutter/go-microservices-template/internal/domain/user"
 	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/memclutter/go-microservices-template/pkg/password"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -28,6 +31,14 @@ func (m *MockRepository) GetByID(ctx context.Context, id string) (*user.User, er
 	return args.Get(0).(*user.User), args.Error(1)
 }
 
+func (m *MockRepository) GetByIDs(ctx context.Context, ids []string) ([]*user.User, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
 func (m *MockRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	args := m.Called(ctx, email)
 	if args.Get(0) == nil {
@@ -41,8 +52,28 @@ func (m *MockRepository) Update(ctx context.Context, u *user.User) error {
 	return args.Error(0)
 }
 
-func (m *MockRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockRepository) UpdateRoles(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdatePassword(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateEmail(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	args := m.Called(ctx, email)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id string, version int64) error {
+	args := m.Called(ctx, id, version)
 	return args.Error(0)
 }
 
@@ -54,6 +85,19 @@ func (m *MockRepository) List(ctx context.Context, limit, offset int32) ([]*user
 	return args.Get(0).([]*user.User), args.Error(1)
 }
 
+func (m *MockRepository) ListAfter(ctx context.Context, cursor user.PageCursor, limit int32) ([]*user.User, error) {
+	args := m.Called(ctx, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+func (m *MockRepository) Count(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 type MockDomainService struct {
 	mock.Mock
 }
@@ -77,11 +121,25 @@ func (m *MockEventPublisher) Publish(ctx context.Context, eventType string, payl
 	return args.Error(0)
 }
 
+var (
+	// testHasher uses the minimum bcrypt cost to keep tests fast
+	testHasher = mustTestHasher()
+	testPolicy = user.PasswordPolicy{MinLength: 8, MaxLength: 64, MaxBytes: password.BcryptMaxPasswordLength, HistorySize: 3}
+)
+
+func mustTestHasher() user.PasswordHasher {
+	hasher, err := password.NewBcrypt(password.BcryptParams{Cost: 4})
+	if err != nil {
+		panic(err)
+	}
+	return hasher
+}
+
 func TestCreateUserUseCase_Execute(t *testing.T) {
 	tests := []struct {
 		name    string
 		input   CreateUserInput
-		setup   func(*MockRepository, *MockDomainService, *MockEventPublisher)
+		setup   func(*MockRepository, *MockDomainService, *MockEmailVerificationRepository, *MockEventPublisher)
 		wantErr error
 	}{
 		{
@@ -91,10 +149,14 @@ func TestCreateUserUseCase_Execute(t *testing.T) {
 				Name:     "Test User",
 				Password: "password123",
 			},
-			setup: func(repo *MockRepository, ds *MockDomainService, pub *MockEventPublisher) {
+			setup: func(repo *MockRepository, ds *MockDomainService, verifications *MockEmailVerificationRepository, pub *MockEventPublisher) {
 				ds.On("IsEmailUnique", mock.Anything, "test@example.com").Return(true, nil)
 				repo.On("Create", mock.Anything, mock.AnythingOfType("*user.User")).Return(nil)
 				pub.On("Publish", mock.Anything, user.EventTypeUserCreated, mock.Anything).Return(nil)
+				verifications.On("Create", mock.Anything, mock.MatchedBy(func(v *user.EmailVerificationToken) bool {
+					return v.Email == "test@example.com" && v.TokenHash != ""
+				})).Return(nil)
+				pub.On("Publish", mock.Anything, user.EventTypeVerificationRequested, mock.Anything).Return(nil)
 			},
 			wantErr: nil,
 		},
@@ -105,11 +167,24 @@ func TestCreateUserUseCase_Execute(t *testing.T) {
 				Name:     "Test User",
 				Password: "password123",
 			},
-			setup: func(repo *MockRepository, ds *MockDomainService, pub *MockEventPublisher) {
+			setup: func(repo *MockRepository, ds *MockDomainService, verifications *MockEmailVerificationRepository, pub *MockEventPublisher) {
 				ds.On("IsEmailUnique", mock.Anything, "test@example.com").Return(false, nil)
 			},
 			wantErr: user.ErrUserAlreadyExists,
 		},
+		{
+			name: "concurrent sign-up with same email",
+			input: CreateUserInput{
+				Email:    "test@example.com",
+				Name:     "Test User",
+				Password: "password123",
+			},
+			setup: func(repo *MockRepository, ds *MockDomainService, verifications *MockEmailVerificationRepository, pub *MockEventPublisher) {
+				ds.On("IsEmailUnique", mock.Anything, "test@example.com").Return(true, nil)
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*user.User")).Return(user.ErrUserAlreadyExists)
+			},
+			wantErr: user.ErrUserAlreadyExists,
+		},
 		{
 			name: "invalid email",
 			input: CreateUserInput{
@@ -117,9 +192,35 @@ func TestCreateUserUseCase_Execute(t *testing.T) {
 				Name:     "Test User",
 				Password: "password123",
 			},
-			setup:   func(repo *MockRepository, ds *MockDomainService, pub *MockEventPublisher) {},
+			setup: func(repo *MockRepository, ds *MockDomainService, verifications *MockEmailVerificationRepository, pub *MockEventPublisher) {
+				ds.On("IsEmailUnique", mock.Anything, "").Return(true, nil)
+			},
 			wantErr: user.ErrInvalidEmail,
 		},
+		{
+			name: "duplicate email reported before invalid input",
+			input: CreateUserInput{
+				Email:    "test@example.com",
+				Name:     "",
+				Password: "password123",
+			},
+			setup: func(repo *MockRepository, ds *MockDomainService, verifications *MockEmailVerificationRepository, pub *MockEventPublisher) {
+				ds.On("IsEmailUnique", mock.Anything, "test@example.com").Return(false, nil)
+			},
+			wantErr: user.ErrUserAlreadyExists,
+		},
+		{
+			name: "multibyte password too long for bcrypt",
+			input: CreateUserInput{
+				Email:    "test@example.com",
+				Name:     "Test User",
+				Password: strings.Repeat("密", 30),
+			},
+			setup: func(repo *MockRepository, ds *MockDomainService, verifications *MockEmailVerificationRepository, pub *MockEventPublisher) {
+				ds.On("IsEmailUnique", mock.Anything, "test@example.com").Return(true, nil)
+			},
+			wantErr: user.ErrWeakPassword,
+		},
 	}
 
 	for _, tt := range tests {
@@ -127,13 +228,14 @@ func TestCreateUserUseCase_Execute(t *testing.T) {
 			// Setup mocks
 			repo := new(MockRepository)
 			domainService := new(MockDomainService)
+			verifications := new(MockEmailVerificationRepository)
 			eventPub := new(MockEventPublisher)
 			log := logger.New("test")
 
-			tt.setup(repo, domainService, eventPub)
+			tt.setup(repo, domainService, verifications, eventPub)
 
 			// Create use case
-			uc := NewCreateUserUseCase(repo, domainService, eventPub, log)
+			uc := NewCreateUserUseCase(repo, domainService, verifications, testHasher, testPolicy, eventPub, 48*time.Hour, log)
 
 			// Execute
 			result, err := uc.Execute(context.Background(), tt.input)
@@ -148,11 +250,13 @@ func TestCreateUserUseCase_Execute(t *testing.T) {
 				assert.NotNil(t, result)
 				assert.NotEmpty(t, result.UserID)
 				assert.Equal(t, tt.input.Email, result.Email)
+				assert.False(t, result.EmailVerified)
 			}
 
 			// Verify mocks
 			repo.AssertExpectations(t)
 			domainService.AssertExpectations(t)
+			verifications.AssertExpectations(t)
 			eventPub.AssertExpectations(t)
 		})
 	}
@@ -0,0 +1,84 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// DeleteUserInput represents input for deleting a user
+type DeleteUserInput struct {
+	UserID string
+	// Version is the version of the user the deletion is based on. Unless
+	// it is zero, the deletion fails with user.ErrVersionMismatch when the
+	// user was changed since.
+	Version int64
+}
+
+// DeleteUserUseCase handles user deletion business flow
+type DeleteUserUseCase struct {
+	repo          user.Repository
+	domainService user.Service
+	eventPub      EventPublisher
+	logger        *logger.Logger
+}
+
+// NewDeleteUserUseCase creates a new use case instance
+func NewDeleteUserUseCase(
+	repo user.Repository,
+	domainService user.Service,
+	eventPub EventPublisher,
+	logger *logger.Logger,
+) *DeleteUserUseCase {
+	return &DeleteUserUseCase{
+		repo:          repo,
+		domainService: domainService,
+		eventPub:      eventPub,
+		logger:        logger,
+	}
+}
+
+// Execute deletes a user by ID
+func (uc *DeleteUserUseCase) Execute(ctx context.Context, input DeleteUserInput) error {
+	uc.logger.WithField("user_id", input.UserID).Info("Deleting user")
+
+	// 1. Check business rules (domain service)
+	canDelete, err := uc.domainService.CanUserBeDeleted(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to check user deletion rules")
+		return fmt.Errorf("failed to check deletion rules: %w", err)
+	}
+	if !canDelete {
+		return user.ErrUserCannotBeDeleted
+	}
+
+	// 2. Delete from repository
+	if err := uc.repo.Delete(ctx, input.UserID, input.Version); err != nil {
+		if errors.Is(err, user.ErrUserNotFound) || errors.Is(err, user.ErrVersionMismatch) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to delete user from database")
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	// 3. Publish domain event
+	event := user.UserDeletedEvent{
+		UserID:    input.UserID,
+		DeletedAt: time.Now(),
+	}
+	if err := uc.eventPub.Publish(ctx, user.EventTypeUserDeleted, event); err != nil {
+		// Don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to publish user deleted event")
+	}
+
+	uc.logger.WithField("user_id", input.UserID).Info("User deleted successfully")
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeleteUserUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   DeleteUserInput
+		setup   func(*MockRepository, *MockDomainService, *MockEventPublisher)
+		wantErr error
+	}{
+		{
+			name:  "successful user deletion",
+			input: DeleteUserInput{UserID: "user-1"},
+			setup: func(repo *MockRepository, ds *MockDomainService, pub *MockEventPublisher) {
+				ds.On("CanUserBeDeleted", mock.Anything, "user-1").Return(true, nil)
+				repo.On("Delete", mock.Anything, "user-1", int64(0)).Return(nil)
+				pub.On("Publish", mock.Anything, user.EventTypeUserDeleted, mock.AnythingOfType("user.UserDeletedEvent")).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "user not found",
+			input: DeleteUserInput{UserID: "missing"},
+			setup: func(repo *MockRepository, ds *MockDomainService, pub *MockEventPublisher) {
+				ds.On("CanUserBeDeleted", mock.Anything, "missing").Return(false, user.ErrUserNotFound)
+			},
+			wantErr: user.ErrUserNotFound,
+		},
+		{
+			name:  "business rules block deletion",
+			input: DeleteUserInput{UserID: "admin"},
+			setup: func(repo *MockRepository, ds *MockDomainService, pub *MockEventPublisher) {
+				ds.On("CanUserBeDeleted", mock.Anything, "admin").Return(false, nil)
+			},
+			wantErr: user.ErrUserCannotBeDeleted,
+		},
+		{
+			name:  "user changed since it was read",
+			input: DeleteUserInput{UserID: "user-1", Version: 3},
+			setup: func(repo *MockRepository, ds *MockDomainService, pub *MockEventPublisher) {
+				ds.On("CanUserBeDeleted", mock.Anything, "user-1").Return(true, nil)
+				repo.On("Delete", mock.Anything, "user-1", int64(3)).Return(user.ErrVersionMismatch)
+			},
+			wantErr: user.ErrVersionMismatch,
+		},
+		{
+			name:  "user removed concurrently",
+			input: DeleteUserInput{UserID: "user-1"},
+			setup: func(repo *MockRepository, ds *MockDomainService, pub *MockEventPublisher) {
+				ds.On("CanUserBeDeleted", mock.Anything, "user-1").Return(true, nil)
+				repo.On("Delete", mock.Anything, "user-1", int64(0)).Return(user.ErrUserNotFound)
+			},
+			wantErr: user.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup mocks
+			repo := new(MockRepository)
+			domainService := new(MockDomainService)
+			eventPub := new(MockEventPublisher)
+			log := logger.New("test")
+
+			tt.setup(repo, domainService, eventPub)
+
+			// Create use case
+			uc := NewDeleteUserUseCase(repo, domainService, eventPub, log)
+
+			// Execute
+			err := uc.Execute(context.Background(), tt.input)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Verify mocks
+			repo.AssertExpectations(t)
+			domainService.AssertExpectations(t)
+			eventPub.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// DisableTOTPInput represents a current TOTP code or an unused recovery code
+// proving the caller still holds the second factor, and the client making the request
+type DisableTOTPInput struct {
+	UserID       string
+	Code         string
+	RecoveryCode string
+	IPAddress    string
+}
+
+// DisableTOTPUseCase handles turning two-factor authentication off
+type DisableTOTPUseCase struct {
+	repo      user.Repository
+	twoFactor user.TwoFactorRepository
+	throttle  *LoginThrottle
+	eventPub  EventPublisher
+	logger    *logger.Logger
+}
+
+// NewDisableTOTPUseCase creates a new use case instance
+func NewDisableTOTPUseCase(
+	repo user.Repository,
+	twoFactor user.TwoFactorRepository,
+	throttle *LoginThrottle,
+	eventPub EventPublisher,
+	logger *logger.Logger,
+) *DisableTOTPUseCase {
+	return &DisableTOTPUseCase{
+		repo:      repo,
+		twoFactor: twoFactor,
+		throttle:  throttle,
+		eventPub:  eventPub,
+		logger:    logger,
+	}
+}
+
+// Execute removes the TOTP secret and the recovery codes of the user.
+// Wrong codes count as failed logins, so that a stolen access token cannot be
+// used to guess its way past the second factor.
+func (uc *DisableTOTPUseCase) Execute(ctx context.Context, input DisableTOTPInput) error {
+	uc.logger.WithField("user_id", input.UserID).Info("Disabling TOTP")
+
+	// 1. Load existing user
+	u, err := uc.repo.GetByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 2. Apply delay or lockout of earlier failures
+	if err := uc.throttle.Check(ctx, u.Email, input.IPAddress); err != nil {
+		if !errors.Is(err, user.ErrTooManyLoginAttempts) {
+			uc.logger.WithError(err).Error("Failed to check login failures")
+		}
+		return err
+	}
+
+	// 3. Load secret
+	userTOTP, err := uc.twoFactor.GetTOTP(ctx, u.ID)
+	if err != nil && !errors.Is(err, user.ErrTwoFactorNotEnrolled) {
+		uc.logger.WithError(err).Error("Failed to get TOTP secret")
+		return fmt.Errorf("failed to get totp secret: %w", err)
+	}
+	if err != nil || !userTOTP.IsEnabled() {
+		return user.ErrTwoFactorNotEnabled
+	}
+
+	// 4. Verify second factor
+	if err := verifySecondFactor(ctx, uc.twoFactor, userTOTP, input.Code, input.RecoveryCode); err != nil {
+		if errors.Is(err, user.ErrInvalidTwoFactorCode) {
+			uc.throttle.RecordFailure(ctx, u, u.Email, input.IPAddress)
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to verify second factor")
+		return fmt.Errorf("failed to verify second factor: %w", err)
+	}
+
+	// 5. Persist
+	if err := uc.twoFactor.DisableTOTP(ctx, u.ID); err != nil {
+		uc.logger.WithError(err).Error("Failed to disable TOTP")
+		return fmt.Errorf("failed to disable totp: %w", err)
+	}
+
+	// 6. Publish domain event
+	event := user.UserTwoFactorDisabledEvent{
+		UserID:     u.ID,
+		DisabledAt: time.Now(),
+	}
+	if err := uc.eventPub.Publish(ctx, user.EventTypeUserTwoFactorDisabled, event); err != nil {
+		// Don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to publish two-factor disabled event")
+	}
+
+	uc.logger.WithField("user_id", u.ID).Info("Two-factor authentication disabled")
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/memclutter/go-microservices-template/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisableTOTPUseCase_Execute(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	existing := &user.User{ID: "user-1", Email: "test@example.com", TwoFactorEnabledAt: &enabledAt}
+
+	tests := []struct {
+		name    string
+		input   DisableTOTPInput
+		stored  *user.TOTP
+		setup   func(*MockTwoFactorRepository, *MockEventPublisher)
+		wantErr error
+	}{
+		{
+			name:   "valid code",
+			input:  DisableTOTPInput{UserID: "user-1", Code: currentTOTPCode(t, secret)},
+			stored: &user.TOTP{UserID: "user-1", Secret: secret, EnabledAt: &enabledAt},
+			setup: func(twoFactor *MockTwoFactorRepository, pub *MockEventPublisher) {
+				twoFactor.On("UseTOTPStep", mock.Anything, mock.Anything).Return(nil)
+				twoFactor.On("DisableTOTP", mock.Anything, "user-1").Return(nil)
+				pub.On("Publish", mock.Anything, user.EventTypeUserTwoFactorDisabled, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:   "valid recovery code",
+			input:  DisableTOTPInput{UserID: "user-1", RecoveryCode: "abcd-efgh-ijkl-mnop"},
+			stored: &user.TOTP{UserID: "user-1", Secret: secret, EnabledAt: &enabledAt},
+			setup: func(twoFactor *MockTwoFactorRepository, pub *MockEventPublisher) {
+				twoFactor.On("UseRecoveryCode", mock.Anything, "user-1", auth.HashRecoveryCode("abcd-efgh-ijkl-mnop"), mock.Anything).Return(nil)
+				twoFactor.On("DisableTOTP", mock.Anything, "user-1").Return(nil)
+				pub.On("Publish", mock.Anything, user.EventTypeUserTwoFactorDisabled, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:    "wrong code",
+			input:   DisableTOTPInput{UserID: "user-1", Code: "000000"},
+			stored:  &user.TOTP{UserID: "user-1", Secret: secret, EnabledAt: &enabledAt},
+			setup:   func(*MockTwoFactorRepository, *MockEventPublisher) {},
+			wantErr: user.ErrInvalidTwoFactorCode,
+		},
+		{
+			name:    "unconfirmed enrollment",
+			input:   DisableTOTPInput{UserID: "user-1", Code: currentTOTPCode(t, secret)},
+			stored:  &user.TOTP{UserID: "user-1", Secret: secret},
+			setup:   func(*MockTwoFactorRepository, *MockEventPublisher) {},
+			wantErr: user.ErrTwoFactorNotEnabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil)
+			twoFactor := new(MockTwoFactorRepository)
+			twoFactor.On("GetTOTP", mock.Anything, "user-1").Return(tt.stored, nil)
+			pub := new(MockEventPublisher)
+			tt.setup(twoFactor, pub)
+
+			uc := NewDisableTOTPUseCase(repo, twoFactor, newPermissiveLoginThrottle(), pub, logger.New("test"))
+			err := uc.Execute(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				twoFactor.AssertNotCalled(t, "DisableTOTP", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			twoFactor.AssertExpectations(t)
+			pub.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/memclutter/go-microservices-template/pkg/webauthn"
+)
+
+// FinishPasskeyLoginInput represents the response of the authenticator to an
+// authentication ceremony and the client starting the session
+type FinishPasskeyLoginInput struct {
+	CredentialID      []byte
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+	// UserHandle is the user handle returned by the authenticator, checked
+	// against the owner of the passkey when present
+	UserHandle string
+	UserAgent  string
+	IPAddress  string
+}
+
+// FinishPasskeyLoginUseCase handles completing passkey authentication ceremonies
+type FinishPasskeyLoginUseCase struct {
+	repo                 user.Repository
+	passkeys             user.PasskeyRepository
+	challenges           user.PasskeyChallengeRepository
+	sessions             user.SessionRepository
+	tokens               TokenIssuer
+	rp                   *webauthn.RelyingParty
+	refreshTTL           time.Duration
+	requireVerifiedEmail bool
+	logger               *logger.Logger
+}
+
+// NewFinishPasskeyLoginUseCase creates a new use case instance
+func NewFinishPasskeyLoginUseCase(
+	repo user.Repository,
+	passkeys user.PasskeyRepository,
+	challenges user.PasskeyChallengeRepository,
+	sessions user.SessionRepository,
+	tokens TokenIssuer,
+	rp *webauthn.RelyingParty,
+	refreshTTL time.Duration,
+	requireVerifiedEmail bool,
+	logger *logger.Logger,
+) *FinishPasskeyLoginUseCase {
+	return &FinishPasskeyLoginUseCase{
+		repo:                 repo,
+		passkeys:             passkeys,
+		challenges:           challenges,
+		sessions:             sessions,
+		tokens:               tokens,
+		rp:                   rp,
+		refreshTTL:           refreshTTL,
+		requireVerifiedEmail: requireVerifiedEmail,
+		logger:               logger,
+	}
+}
+
+// Execute verifies the assertion against the stored passkey and starts a
+// session. Unknown passkeys, assertions that do not verify and signature
+// counters that did not increase all return user.ErrUnauthorized.
+// Authenticators verify the user for passkeys, so no second factor is asked.
+func (uc *FinishPasskeyLoginUseCase) Execute(ctx context.Context, input FinishPasskeyLoginInput) (*LoginOutput, error) {
+	uc.logger.Info("Logging in user with passkey")
+
+	// 1. Find usable challenge
+	challenge, c, err := findPasskeyChallenge(ctx, uc.challenges, input.ClientDataJSON, user.PasskeyLogin, uc.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Consume challenge before verifying, so that a failed assertion
+	// cannot be retried against it
+	if err := uc.challenges.Consume(ctx, c, time.Now()); err != nil {
+		if errors.Is(err, user.ErrInvalidPasskeyChallenge) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to consume passkey challenge")
+		return nil, fmt.Errorf("failed to consume passkey challenge: %w", err)
+	}
+
+	// 3. Find passkey
+	passkey, err := uc.passkeys.GetByCredentialID(ctx, input.CredentialID)
+	if err != nil {
+		if errors.Is(err, user.ErrPasskeyNotFound) {
+			return nil, user.ErrUnauthorized
+		}
+		uc.logger.WithError(err).Error("Failed to get passkey")
+		return nil, fmt.Errorf("failed to get passkey: %w", err)
+	}
+	if input.UserHandle != "" && input.UserHandle != passkey.UserID {
+		return nil, user.ErrUnauthorized
+	}
+
+	// 4. Verify assertion
+	assertion := webauthn.Assertion{
+		ClientDataJSON:    input.ClientDataJSON,
+		AuthenticatorData: input.AuthenticatorData,
+		Signature:         input.Signature,
+	}
+	signCount, err := uc.rp.VerifyAssertion(passkey.PublicKey, passkey.SignCount, assertion, challenge)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrSignCountMismatch) {
+			uc.logger.WithField("passkey_id", passkey.ID).Warn("Passkey login failed: authenticator may be cloned")
+		} else {
+			uc.logger.WithError(err).WithField("passkey_id", passkey.ID).Info("Passkey login failed")
+		}
+		return nil, user.ErrUnauthorized
+	}
+
+	// 5. Record use of the passkey
+	passkey.Use(signCount, time.Now())
+	if err := uc.passkeys.Use(ctx, passkey); err != nil {
+		if errors.Is(err, user.ErrPasskeyNotFound) {
+			return nil, user.ErrUnauthorized
+		}
+		uc.logger.WithError(err).Error("Failed to update passkey")
+		return nil, fmt.Errorf("failed to update passkey: %w", err)
+	}
+
+	// 6. Load user
+	u, err := uc.repo.GetByID(ctx, passkey.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, user.ErrUnauthorized
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if uc.requireVerifiedEmail && !u.IsEmailVerified() {
+		uc.logger.WithField("user_id", u.ID).Info("Login failed: email not verified")
+		return nil, user.ErrEmailNotVerified
+	}
+
+	// 7. Start session and issue its tokens
+	tokens, err := startSession(ctx, uc.sessions, uc.tokens, u.ID, input.UserAgent, input.IPAddress, uc.refreshTTL, uc.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.WithFields(map[string]any{
+		"user_id":    u.ID,
+		"session_id": tokens.SessionID,
+		"passkey_id": passkey.ID,
+	}).Info("User logged in successfully with passkey")
+
+	return &LoginOutput{SessionTokens: *tokens}, nil
+}
@@ -0,0 +1,196 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/memclutter/go-microservices-template/pkg/webauthn/webauthntest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasskeyLogin(t *testing.T) {
+	existing, err := user.NewUser("test@example.com", "Test User", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+	existing.ID = "user-1"
+	enabledAt := time.Now()
+	existing.TwoFactorEnabledAt = &enabledAt
+	expiresAt := time.Now().Add(15 * time.Minute)
+	rp := newTestRelyingParty(t)
+
+	// setup registers a passkey of a software authenticator, begins a login
+	// ceremony and returns the authenticator, its passkey and the challenge
+	setup := func(t *testing.T) (*webauthntest.Authenticator, *user.Passkey, string, *user.PasskeyChallenge) {
+		authenticator, err := webauthntest.New(testRPID, testOrigin)
+		require.NoError(t, err)
+		passkey, err := user.NewPasskey("passkey-1", "user-1", "Laptop", authenticator.CredentialID, authenticator.PublicKey(), 0, nil)
+		require.NoError(t, err)
+
+		challenges := new(MockPasskeyChallengeRepository)
+		var stored *user.PasskeyChallenge
+		challenges.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*user.PasskeyChallenge)
+		}).Return(nil)
+
+		uc := NewBeginPasskeyLoginUseCase(challenges, rp, 5*time.Minute, logger.New("test"))
+		options, err := uc.Execute(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, testRPID, options.RPID)
+		assert.Equal(t, user.PasskeyLogin, stored.Ceremony)
+		assert.Empty(t, stored.UserID)
+		return authenticator, passkey, options.Challenge, stored
+	}
+
+	t.Run("starts a session without second factor", func(t *testing.T) {
+		authenticator, passkey, challenge, stored := setup(t)
+		clientDataJSON, authData, signature, err := authenticator.Login(challenge)
+		require.NoError(t, err)
+
+		repo := new(MockRepository)
+		passkeys := new(MockPasskeyRepository)
+		challenges := new(MockPasskeyChallengeRepository)
+		sessions := new(MockSessionRepository)
+		tokens := new(MockTokenIssuer)
+		challenges.On("GetByHash", mock.Anything, stored.ChallengeHash).Return(stored, nil)
+		passkeys.On("GetByCredentialID", mock.Anything, authenticator.CredentialID).Return(passkey, nil)
+		challenges.On("Consume", mock.Anything, stored, mock.Anything).Return(nil)
+		passkeys.On("Use", mock.Anything, mock.MatchedBy(func(p *user.Passkey) bool {
+			return p.SignCount == 1 && p.LastUsedAt != nil
+		})).Return(nil)
+		repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil)
+		sessions.On("Create", mock.Anything, mock.MatchedBy(func(s *user.Session) bool {
+			return s.UserID == "user-1"
+		})).Return(nil)
+		tokens.On("IssueAccessToken", "user-1", mock.Anything).Return("token", expiresAt, nil)
+
+		uc := NewFinishPasskeyLoginUseCase(repo, passkeys, challenges, sessions, tokens, rp, time.Hour, false, logger.New("test"))
+		result, err := uc.Execute(context.Background(), FinishPasskeyLoginInput{
+			CredentialID:      authenticator.CredentialID,
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authData,
+			Signature:         signature,
+			UserHandle:        "user-1",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "token", result.AccessToken)
+		assert.False(t, result.TwoFactorRequired)
+		passkeys.AssertExpectations(t)
+		challenges.AssertExpectations(t)
+	})
+
+	t.Run("cloned authenticator", func(t *testing.T) {
+		authenticator, passkey, challenge, stored := setup(t)
+		passkey.SignCount = 5
+		clientDataJSON, authData, signature, err := authenticator.Login(challenge)
+		require.NoError(t, err)
+
+		passkeys := new(MockPasskeyRepository)
+		challenges := new(MockPasskeyChallengeRepository)
+		challenges.On("GetByHash", mock.Anything, stored.ChallengeHash).Return(stored, nil)
+		challenges.On("Consume", mock.Anything, stored, mock.Anything).Return(nil)
+		passkeys.On("GetByCredentialID", mock.Anything, authenticator.CredentialID).Return(passkey, nil)
+
+		uc := NewFinishPasskeyLoginUseCase(new(MockRepository), passkeys, challenges, new(MockSessionRepository), new(MockTokenIssuer), rp, time.Hour, false, logger.New("test"))
+		_, err = uc.Execute(context.Background(), FinishPasskeyLoginInput{
+			CredentialID:      authenticator.CredentialID,
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authData,
+			Signature:         signature,
+		})
+		assert.ErrorIs(t, err, user.ErrUnauthorized)
+		passkeys.AssertNotCalled(t, "Use", mock.Anything, mock.Anything)
+	})
+
+	t.Run("signature of another key", func(t *testing.T) {
+		authenticator, passkey, challenge, stored := setup(t)
+		other, err := webauthntest.New(testRPID, testOrigin)
+		require.NoError(t, err)
+		passkey.PublicKey = other.PublicKey()
+		clientDataJSON, authData, signature, err := authenticator.Login(challenge)
+		require.NoError(t, err)
+
+		passkeys := new(MockPasskeyRepository)
+		challenges := new(MockPasskeyChallengeRepository)
+		challenges.On("GetByHash", mock.Anything, stored.ChallengeHash).Return(stored, nil)
+		challenges.On("Consume", mock.Anything, stored, mock.Anything).Return(nil)
+		passkeys.On("GetByCredentialID", mock.Anything, authenticator.CredentialID).Return(passkey, nil)
+
+		uc := NewFinishPasskeyLoginUseCase(new(MockRepository), passkeys, challenges, new(MockSessionRepository), new(MockTokenIssuer), rp, time.Hour, false, logger.New("test"))
+		_, err = uc.Execute(context.Background(), FinishPasskeyLoginInput{
+			CredentialID:      authenticator.CredentialID,
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authData,
+			Signature:         signature,
+		})
+		assert.ErrorIs(t, err, user.ErrUnauthorized)
+		// The challenge is spent, so the assertion cannot be retried
+		challenges.AssertExpectations(t)
+	})
+
+	t.Run("user handle of another user", func(t *testing.T) {
+		authenticator, passkey, challenge, stored := setup(t)
+		clientDataJSON, authData, signature, err := authenticator.Login(challenge)
+		require.NoError(t, err)
+
+		passkeys := new(MockPasskeyRepository)
+		challenges := new(MockPasskeyChallengeRepository)
+		challenges.On("GetByHash", mock.Anything, stored.ChallengeHash).Return(stored, nil)
+		challenges.On("Consume", mock.Anything, stored, mock.Anything).Return(nil)
+		passkeys.On("GetByCredentialID", mock.Anything, authenticator.CredentialID).Return(passkey, nil)
+
+		uc := NewFinishPasskeyLoginUseCase(new(MockRepository), passkeys, challenges, new(MockSessionRepository), new(MockTokenIssuer), rp, time.Hour, false, logger.New("test"))
+		_, err = uc.Execute(context.Background(), FinishPasskeyLoginInput{
+			CredentialID:      authenticator.CredentialID,
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authData,
+			Signature:         signature,
+			UserHandle:        "user-2",
+		})
+		assert.ErrorIs(t, err, user.ErrUnauthorized)
+	})
+
+	t.Run("used challenge", func(t *testing.T) {
+		authenticator, _, challenge, stored := setup(t)
+		clientDataJSON, authData, signature, err := authenticator.Login(challenge)
+		require.NoError(t, err)
+
+		passkeys := new(MockPasskeyRepository)
+		challenges := new(MockPasskeyChallengeRepository)
+		challenges.On("GetByHash", mock.Anything, stored.ChallengeHash).Return(stored, nil)
+		challenges.On("Consume", mock.Anything, stored, mock.Anything).Return(user.ErrInvalidPasskeyChallenge)
+
+		uc := NewFinishPasskeyLoginUseCase(new(MockRepository), passkeys, challenges, new(MockSessionRepository), new(MockTokenIssuer), rp, time.Hour, false, logger.New("test"))
+		_, err = uc.Execute(context.Background(), FinishPasskeyLoginInput{
+			CredentialID:      authenticator.CredentialID,
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authData,
+			Signature:         signature,
+		})
+		assert.ErrorIs(t, err, user.ErrInvalidPasskeyChallenge)
+		passkeys.AssertNotCalled(t, "GetByCredentialID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("registration challenge", func(t *testing.T) {
+		authenticator, _, challenge, stored := setup(t)
+		stored.Ceremony = user.PasskeyRegistration
+		clientDataJSON, authData, signature, err := authenticator.Login(challenge)
+		require.NoError(t, err)
+
+		challenges := new(MockPasskeyChallengeRepository)
+		challenges.On("GetByHash", mock.Anything, stored.ChallengeHash).Return(stored, nil)
+
+		uc := NewFinishPasskeyLoginUseCase(new(MockRepository), new(MockPasskeyRepository), challenges, new(MockSessionRepository), new(MockTokenIssuer), rp, time.Hour, false, logger.New("test"))
+		_, err = uc.Execute(context.Background(), FinishPasskeyLoginInput{
+			CredentialID:      authenticator.CredentialID,
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authData,
+			Signature:         signature,
+		})
+		assert.ErrorIs(t, err, user.ErrInvalidPasskeyChallenge)
+	})
+}
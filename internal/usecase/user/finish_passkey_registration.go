@@ -0,0 +1,158 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/memclutter/go-microservices-template/pkg/webauthn"
+)
+
+// FinishPasskeyRegistrationInput represents the response of the authenticator
+// to a registration ceremony
+type FinishPasskeyRegistrationInput struct {
+	UserID string
+	// Name labels the passkey for its owner, DefaultPasskeyName when empty
+	Name              string
+	ClientDataJSON    []byte
+	AttestationObject []byte
+	// Transports are the hints of AuthenticatorAttestationResponse.getTransports()
+	Transports []string
+}
+
+// FinishPasskeyRegistrationOutput represents the registered passkey
+type FinishPasskeyRegistrationOutput struct {
+	Passkey *user.Passkey
+}
+
+// FinishPasskeyRegistrationUseCase handles completing passkey registration ceremonies
+type FinishPasskeyRegistrationUseCase struct {
+	passkeys   user.PasskeyRepository
+	challenges user.PasskeyChallengeRepository
+	rp         *webauthn.RelyingParty
+	eventPub   EventPublisher
+	logger     *logger.Logger
+}
+
+// NewFinishPasskeyRegistrationUseCase creates a new use case instance
+func NewFinishPasskeyRegistrationUseCase(
+	passkeys user.PasskeyRepository,
+	challenges user.PasskeyChallengeRepository,
+	rp *webauthn.RelyingParty,
+	eventPub EventPublisher,
+	logger *logger.Logger,
+) *FinishPasskeyRegistrationUseCase {
+	return &FinishPasskeyRegistrationUseCase{
+		passkeys:   passkeys,
+		challenges: challenges,
+		rp:         rp,
+		eventPub:   eventPub,
+		logger:     logger,
+	}
+}
+
+// Execute verifies the attestation of the new credential and stores it as a
+// passkey of the user. A challenge that is unknown, used, expired or issued
+// for another user returns user.ErrInvalidPasskeyChallenge; a response that
+// does not verify returns user.ErrInvalidPasskeyResponse.
+func (uc *FinishPasskeyRegistrationUseCase) Execute(ctx context.Context, input FinishPasskeyRegistrationInput) (*FinishPasskeyRegistrationOutput, error) {
+	uc.logger.WithField("user_id", input.UserID).Info("Finishing passkey registration")
+
+	// 1. Find usable challenge
+	challenge, c, err := findPasskeyChallenge(ctx, uc.challenges, input.ClientDataJSON, user.PasskeyRegistration, uc.logger)
+	if err != nil {
+		return nil, err
+	}
+	if c.UserID != input.UserID {
+		return nil, user.ErrInvalidPasskeyChallenge
+	}
+
+	// 2. Consume challenge before verifying, so that a failed attestation
+	// cannot be retried against it
+	if err := uc.challenges.Consume(ctx, c, time.Now()); err != nil {
+		if errors.Is(err, user.ErrInvalidPasskeyChallenge) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to consume passkey challenge")
+		return nil, fmt.Errorf("failed to consume passkey challenge: %w", err)
+	}
+
+	// 3. Verify attestation
+	credential, err := uc.rp.VerifyRegistration(input.ClientDataJSON, input.AttestationObject, challenge)
+	if err != nil {
+		uc.logger.WithError(err).WithField("user_id", input.UserID).Info("Passkey registration failed")
+		return nil, fmt.Errorf("%w: %w", user.ErrInvalidPasskeyResponse, err)
+	}
+
+	// 4. Create domain entity
+	name := input.Name
+	if name == "" {
+		name = user.DefaultPasskeyName
+	}
+	passkey, err := user.NewPasskey(uuid.New().String(), input.UserID, name, credential.ID, credential.PublicKey, credential.SignCount, input.Transports)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Persist
+	if err := uc.passkeys.Create(ctx, passkey); err != nil {
+		if errors.Is(err, user.ErrPasskeyAlreadyRegistered) || errors.Is(err, user.ErrUserNotFound) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to create passkey")
+		return nil, fmt.Errorf("failed to create passkey: %w", err)
+	}
+
+	// 6. Publish domain event
+	event := user.PasskeyRegisteredEvent{
+		UserID:       passkey.UserID,
+		PasskeyID:    passkey.ID,
+		Name:         passkey.Name,
+		RegisteredAt: passkey.CreatedAt,
+	}
+	if err := uc.eventPub.Publish(ctx, user.EventTypePasskeyRegistered, event); err != nil {
+		// Don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to publish passkey registered event")
+	}
+
+	uc.logger.WithFields(map[string]any{
+		"user_id":    passkey.UserID,
+		"passkey_id": passkey.ID,
+	}).Info("Passkey registered successfully")
+
+	return &FinishPasskeyRegistrationOutput{Passkey: passkey}, nil
+}
+
+// findPasskeyChallenge looks up the challenge named by the client data of a
+// response and returns it with its value if it is usable for the ceremony.
+// The value is verified against the client data by the relying party.
+func findPasskeyChallenge(
+	ctx context.Context,
+	challenges user.PasskeyChallengeRepository,
+	clientDataJSON []byte,
+	ceremony user.PasskeyCeremony,
+	logger *logger.Logger,
+) (string, *user.PasskeyChallenge, error) {
+	clientData, err := webauthn.ParseClientData(clientDataJSON)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", user.ErrInvalidPasskeyResponse, err)
+	}
+
+	c, err := challenges.GetByHash(ctx, auth.HashSecretToken(clientData.Challenge))
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidPasskeyChallenge) {
+			return "", nil, err
+		}
+		logger.WithError(err).Error("Failed to get passkey challenge")
+		return "", nil, fmt.Errorf("failed to get passkey challenge: %w", err)
+	}
+	if !c.IsUsableFor(ceremony, time.Now()) {
+		return "", nil, user.ErrInvalidPasskeyChallenge
+	}
+	return clientData.Challenge, c, nil
+}
@@ -0,0 +1,217 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/memclutter/go-microservices-template/pkg/webauthn"
+	"github.com/memclutter/go-microservices-template/pkg/webauthn/webauthntest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPasskeyRepository is a mock implementation of user.PasskeyRepository
+type MockPasskeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasskeyRepository) Create(ctx context.Context, p *user.Passkey) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (m *MockPasskeyRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*user.Passkey, error) {
+	args := m.Called(ctx, credentialID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.Passkey), args.Error(1)
+}
+
+func (m *MockPasskeyRepository) ListByUser(ctx context.Context, userID string) ([]*user.Passkey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*user.Passkey), args.Error(1)
+}
+
+func (m *MockPasskeyRepository) Use(ctx context.Context, p *user.Passkey) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
+// MockPasskeyChallengeRepository is a mock implementation of user.PasskeyChallengeRepository
+type MockPasskeyChallengeRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasskeyChallengeRepository) Create(ctx context.Context, c *user.PasskeyChallenge) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockPasskeyChallengeRepository) GetByHash(ctx context.Context, challengeHash string) (*user.PasskeyChallenge, error) {
+	args := m.Called(ctx, challengeHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.PasskeyChallenge), args.Error(1)
+}
+
+func (m *MockPasskeyChallengeRepository) Consume(ctx context.Context, c *user.PasskeyChallenge, at time.Time) error {
+	args := m.Called(ctx, c, at)
+	return args.Error(0)
+}
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://example.com"
+)
+
+func newTestRelyingParty(t *testing.T) *webauthn.RelyingParty {
+	t.Helper()
+	rp, err := webauthn.New(webauthn.Config{RPID: testRPID, RPName: "Example", Origins: []string{testOrigin}})
+	require.NoError(t, err)
+	return rp
+}
+
+// beginTestPasskeyRegistration runs BeginPasskeyRegistration for user-1 and
+// returns the options and the stored challenge
+func beginTestPasskeyRegistration(t *testing.T, rp *webauthn.RelyingParty, existing *user.User) (*BeginPasskeyRegistrationOutput, *user.PasskeyChallenge) {
+	t.Helper()
+	repo := new(MockRepository)
+	passkeys := new(MockPasskeyRepository)
+	challenges := new(MockPasskeyChallengeRepository)
+
+	var stored *user.PasskeyChallenge
+	repo.On("GetByID", mock.Anything, existing.ID).Return(existing, nil)
+	passkeys.On("ListByUser", mock.Anything, existing.ID).Return([]*user.Passkey{{CredentialID: []byte("registered")}}, nil)
+	challenges.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*user.PasskeyChallenge)
+	}).Return(nil)
+
+	uc := NewBeginPasskeyRegistrationUseCase(repo, passkeys, challenges, rp, 5*time.Minute, logger.New("test"))
+	options, err := uc.Execute(context.Background(), BeginPasskeyRegistrationInput{UserID: existing.ID})
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	return options, stored
+}
+
+func TestPasskeyRegistration(t *testing.T) {
+	existing, err := user.NewUser("test@example.com", "Test User", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+	existing.ID = "user-1"
+	rp := newTestRelyingParty(t)
+
+	t.Run("registers credential of the authenticator", func(t *testing.T) {
+		options, stored := beginTestPasskeyRegistration(t, rp, existing)
+		assert.Equal(t, testRPID, options.RPID)
+		assert.Equal(t, "user-1", options.UserHandle)
+		assert.Equal(t, "test@example.com", options.UserName)
+		assert.Equal(t, [][]byte{[]byte("registered")}, options.ExcludeCredentialIDs)
+		assert.Equal(t, user.PasskeyRegistration, stored.Ceremony)
+		assert.Equal(t, "user-1", stored.UserID)
+
+		authenticator, err := webauthntest.New(testRPID, testOrigin)
+		require.NoError(t, err)
+		clientDataJSON, attestationObject, err := authenticator.Register(options.Challenge)
+		require.NoError(t, err)
+
+		passkeys := new(MockPasskeyRepository)
+		challenges := new(MockPasskeyChallengeRepository)
+		pub := new(MockEventPublisher)
+		challenges.On("GetByHash", mock.Anything, stored.ChallengeHash).Return(stored, nil)
+		challenges.On("Consume", mock.Anything, stored, mock.Anything).Return(nil)
+		passkeys.On("Create", mock.Anything, mock.MatchedBy(func(p *user.Passkey) bool {
+			return p.UserID == "user-1" && p.Name == "Laptop" &&
+				assert.ObjectsAreEqual(authenticator.CredentialID, p.CredentialID) &&
+				assert.ObjectsAreEqual(authenticator.PublicKey(), p.PublicKey)
+		})).Return(nil)
+		pub.On("Publish", mock.Anything, user.EventTypePasskeyRegistered, mock.Anything).Return(nil)
+
+		uc := NewFinishPasskeyRegistrationUseCase(passkeys, challenges, rp, pub, logger.New("test"))
+		result, err := uc.Execute(context.Background(), FinishPasskeyRegistrationInput{
+			UserID:            "user-1",
+			Name:              "Laptop",
+			ClientDataJSON:    clientDataJSON,
+			AttestationObject: attestationObject,
+			Transports:        []string{"internal", "hybrid"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"internal", "hybrid"}, result.Passkey.Transports)
+		passkeys.AssertExpectations(t)
+		challenges.AssertExpectations(t)
+		pub.AssertExpectations(t)
+	})
+
+	t.Run("challenge of another user", func(t *testing.T) {
+		options, stored := beginTestPasskeyRegistration(t, rp, existing)
+		authenticator, err := webauthntest.New(testRPID, testOrigin)
+		require.NoError(t, err)
+		clientDataJSON, attestationObject, err := authenticator.Register(options.Challenge)
+		require.NoError(t, err)
+
+		passkeys := new(MockPasskeyRepository)
+		challenges := new(MockPasskeyChallengeRepository)
+		challenges.On("GetByHash", mock.Anything, stored.ChallengeHash).Return(stored, nil)
+
+		uc := NewFinishPasskeyRegistrationUseCase(passkeys, challenges, rp, new(MockEventPublisher), logger.New("test"))
+		_, err = uc.Execute(context.Background(), FinishPasskeyRegistrationInput{
+			UserID:            "user-2",
+			ClientDataJSON:    clientDataJSON,
+			AttestationObject: attestationObject,
+		})
+		assert.ErrorIs(t, err, user.ErrInvalidPasskeyChallenge)
+		passkeys.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("response for another relying party", func(t *testing.T) {
+		options, stored := beginTestPasskeyRegistration(t, rp, existing)
+		authenticator, err := webauthntest.New("evil.example", testOrigin)
+		require.NoError(t, err)
+		clientDataJSON, attestationObject, err := authenticator.Register(options.Challenge)
+		require.NoError(t, err)
+
+		passkeys := new(MockPasskeyRepository)
+		challenges := new(MockPasskeyChallengeRepository)
+		challenges.On("GetByHash", mock.Anything, stored.ChallengeHash).Return(stored, nil)
+		challenges.On("Consume", mock.Anything, stored, mock.Anything).Return(nil)
+
+		uc := NewFinishPasskeyRegistrationUseCase(passkeys, challenges, rp, new(MockEventPublisher), logger.New("test"))
+		_, err = uc.Execute(context.Background(), FinishPasskeyRegistrationInput{
+			UserID:            "user-1",
+			ClientDataJSON:    clientDataJSON,
+			AttestationObject: attestationObject,
+		})
+		assert.ErrorIs(t, err, user.ErrInvalidPasskeyResponse)
+		// The challenge is spent, so the response cannot be retried
+		challenges.AssertExpectations(t)
+		passkeys.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("expired challenge", func(t *testing.T) {
+		options, stored := beginTestPasskeyRegistration(t, rp, existing)
+		stored.ExpiresAt = time.Now().Add(-time.Minute)
+		authenticator, err := webauthntest.New(testRPID, testOrigin)
+		require.NoError(t, err)
+		clientDataJSON, attestationObject, err := authenticator.Register(options.Challenge)
+		require.NoError(t, err)
+
+		challenges := new(MockPasskeyChallengeRepository)
+		challenges.On("GetByHash", mock.Anything, stored.ChallengeHash).Return(stored, nil)
+
+		uc := NewFinishPasskeyRegistrationUseCase(new(MockPasskeyRepository), challenges, rp, new(MockEventPublisher), logger.New("test"))
+		_, err = uc.Execute(context.Background(), FinishPasskeyRegistrationInput{
+			UserID:            "user-1",
+			ClientDataJSON:    clientDataJSON,
+			AttestationObject: attestationObject,
+		})
+		assert.ErrorIs(t, err, user.ErrInvalidPasskeyChallenge)
+	})
+}
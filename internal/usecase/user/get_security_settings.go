@@ -0,0 +1,66 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// GetSecuritySettingsInput represents input for getting the security settings of a user
+type GetSecuritySettingsInput struct {
+	UserID string
+}
+
+// GetSecuritySettingsOutput represents how a user protects their account
+type GetSecuritySettingsOutput struct {
+	TwoFactorEnabled bool
+	Passkeys         int
+}
+
+// GetSecuritySettingsUseCase handles retrieving the sign-in factors of a user
+type GetSecuritySettingsUseCase struct {
+	twoFactor user.TwoFactorRepository
+	passkeys  user.PasskeyRepository
+	logger    *logger.Logger
+}
+
+// NewGetSecuritySettingsUseCase creates a new use case instance
+func NewGetSecuritySettingsUseCase(
+	twoFactor user.TwoFactorRepository,
+	passkeys user.PasskeyRepository,
+	logger *logger.Logger,
+) *GetSecuritySettingsUseCase {
+	return &GetSecuritySettingsUseCase{
+		twoFactor: twoFactor,
+		passkeys:  passkeys,
+		logger:    logger,
+	}
+}
+
+// Execute reports whether the user has two-factor authentication enabled and
+// how many passkeys they registered
+func (uc *GetSecuritySettingsUseCase) Execute(ctx context.Context, input GetSecuritySettingsInput) (*GetSecuritySettingsOutput, error) {
+	uc.logger.WithField("user_id", input.UserID).Debug("Getting security settings")
+
+	// 1. Load TOTP secret, an unconfirmed enrollment does not count
+	userTOTP, err := uc.twoFactor.GetTOTP(ctx, input.UserID)
+	if err != nil && !errors.Is(err, user.ErrTwoFactorNotEnrolled) {
+		uc.logger.WithError(err).Error("Failed to get TOTP secret")
+		return nil, fmt.Errorf("failed to get totp secret: %w", err)
+	}
+
+	// 2. Load passkeys
+	passkeys, err := uc.passkeys.ListByUser(ctx, input.UserID)
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to list passkeys")
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+
+	return &GetSecuritySettingsOutput{
+		TwoFactorEnabled: userTOTP != nil && userTOTP.IsEnabled(),
+		Passkeys:         len(passkeys),
+	}, nil
+}
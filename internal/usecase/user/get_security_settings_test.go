@@ -0,0 +1,71 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSecuritySettingsUseCase_Execute(t *testing.T) {
+	enabledAt := time.Now()
+
+	tests := []struct {
+		name         string
+		totp         *user.TOTP
+		totpErr      error
+		passkeys     []*user.Passkey
+		wantEnabled  bool
+		wantPasskeys int
+	}{
+		{
+			name:         "two-factor enabled with passkeys",
+			totp:         &user.TOTP{UserID: "user-1", EnabledAt: &enabledAt},
+			passkeys:     []*user.Passkey{{ID: "passkey-1"}, {ID: "passkey-2"}},
+			wantEnabled:  true,
+			wantPasskeys: 2,
+		},
+		{
+			name:    "not enrolled",
+			totpErr: user.ErrTwoFactorNotEnrolled,
+		},
+		{
+			name: "enrollment not confirmed",
+			totp: &user.TOTP{UserID: "user-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twoFactor := new(MockTwoFactorRepository)
+			if tt.totp != nil {
+				twoFactor.On("GetTOTP", mock.Anything, "user-1").Return(tt.totp, nil)
+			} else {
+				twoFactor.On("GetTOTP", mock.Anything, "user-1").Return(nil, tt.totpErr)
+			}
+			passkeys := new(MockPasskeyRepository)
+			passkeys.On("ListByUser", mock.Anything, "user-1").Return(tt.passkeys, nil)
+
+			uc := NewGetSecuritySettingsUseCase(twoFactor, passkeys, logger.New("test"))
+			output, err := uc.Execute(context.Background(), GetSecuritySettingsInput{UserID: "user-1"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEnabled, output.TwoFactorEnabled)
+			assert.Equal(t, tt.wantPasskeys, output.Passkeys)
+		})
+	}
+
+	t.Run("storage error", func(t *testing.T) {
+		twoFactor := new(MockTwoFactorRepository)
+		twoFactor.On("GetTOTP", mock.Anything, "user-1").Return(nil, errors.New("connection refused"))
+
+		uc := NewGetSecuritySettingsUseCase(twoFactor, new(MockPasskeyRepository), logger.New("test"))
+		_, err := uc.Execute(context.Background(), GetSecuritySettingsInput{UserID: "user-1"})
+		assert.Error(t, err)
+	})
+}
@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/memclutter/go-microservices-template/internal/domain/user"
 	"github.com/memclutter/go-microservices-template/pkg/logger"
@@ -11,6 +12,9 @@ import (
 // GetUserInput represents input for getting a user
 type GetUserInput struct {
 	UserID string
+	// IncludePendingEmail returns the pending email address, which only the
+	// user themselves and admins may see
+	IncludePendingEmail bool
 }
 
 // GetUserOutput represents user data
@@ -18,6 +22,15 @@ type GetUserOutput struct {
 	ID    string
 	Email string
 	Name  string
+	Roles []string
+
+	EmailVerified bool
+	PendingEmail  string
+	// Version changes whenever a field of the user changes, see user.User
+	Version int64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 // GetUserUseCase handles retrieving user data
@@ -47,9 +60,20 @@ func (uc *GetUserUseCase) Execute(ctx context.Context, input GetUserInput) (*Get
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	return &GetUserOutput{
+	output := &GetUserOutput{
 		ID:    u.ID,
 		Email: u.Email,
 		Name:  u.Name,
-	}, nil
+		Roles: u.RoleNames(),
+
+		EmailVerified: u.IsEmailVerified(),
+		Version:       u.Version,
+
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+	if input.IncludePendingEmail {
+		output.PendingEmail = u.PendingEmail
+	}
+	return output, nil
 }
@@ -0,0 +1,97 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetUserUseCase_Execute(t *testing.T) {
+	createdAt := time.Date(2025, 10, 30, 19, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		input   GetUserInput
+		setup   func(*MockRepository)
+		wantErr error
+	}{
+		{
+			name:  "successful get",
+			input: GetUserInput{UserID: "user-1"},
+			setup: func(repo *MockRepository) {
+				repo.On("GetByID", mock.Anything, "user-1").Return(&user.User{
+					ID:        "user-1",
+					Email:     "test@example.com",
+					Name:      "Test User",
+					Version:   3,
+					CreatedAt: createdAt,
+					UpdatedAt: updatedAt,
+				}, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "user not found",
+			input: GetUserInput{UserID: "missing"},
+			setup: func(repo *MockRepository) {
+				repo.On("GetByID", mock.Anything, "missing").Return(nil, user.ErrUserNotFound)
+			},
+			wantErr: user.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup mocks
+			repo := new(MockRepository)
+			tt.setup(repo)
+
+			// Create use case
+			uc := NewGetUserUseCase(repo, logger.New("test"))
+
+			// Execute
+			result, err := uc.Execute(context.Background(), tt.input)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "user-1", result.ID)
+				assert.Equal(t, int64(3), result.Version)
+				// The stored timestamps drive Last-Modified
+				assert.Equal(t, createdAt, result.CreatedAt)
+				assert.Equal(t, updatedAt, result.UpdatedAt)
+			}
+
+			// Verify mocks
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetUserUseCase_PendingEmail(t *testing.T) {
+	repo := new(MockRepository)
+	repo.On("GetByID", mock.Anything, "user-1").Return(&user.User{
+		ID:           "user-1",
+		Email:        "test@example.com",
+		PendingEmail: "new@example.com",
+	}, nil)
+	uc := NewGetUserUseCase(repo, logger.New("test"))
+
+	// Support staff reading the user do not see the address it is moving to
+	result, err := uc.Execute(context.Background(), GetUserInput{UserID: "user-1"})
+	assert.NoError(t, err)
+	assert.Empty(t, result.PendingEmail)
+
+	result, err = uc.Execute(context.Background(), GetUserInput{UserID: "user-1", IncludePendingEmail: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "new@example.com", result.PendingEmail)
+}
@@ -1,8 +1,23 @@
 package user
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // EventPublisher defines interface for publishing domain events
 type EventPublisher interface {
 	Publish(ctx context.Context, eventType string, payload interface{}) error
 }
+
+// PageTokenCodec defines interface for encoding opaque pagination tokens
+type PageTokenCodec interface {
+	Encode(cursor any) (string, error)
+	// Decode returns pagination.ErrInvalidPageToken for a token it did not issue
+	Decode(token string, dst any) error
+}
+
+// TokenIssuer defines interface for issuing access tokens to authenticated users
+type TokenIssuer interface {
+	IssueAccessToken(userID, sessionID string) (token string, expiresAt time.Time, err error)
+}
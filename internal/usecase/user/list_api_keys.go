@@ -0,0 +1,41 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// ListAPIKeysInput represents input for listing a user's API keys
+type ListAPIKeysInput struct {
+	UserID string
+}
+
+// ListAPIKeysUseCase handles listing a user's API keys
+type ListAPIKeysUseCase struct {
+	keys   user.APIKeyRepository
+	logger *logger.Logger
+}
+
+// NewListAPIKeysUseCase creates a new use case instance
+func NewListAPIKeysUseCase(keys user.APIKeyRepository, logger *logger.Logger) *ListAPIKeysUseCase {
+	return &ListAPIKeysUseCase{
+		keys:   keys,
+		logger: logger,
+	}
+}
+
+// Execute lists the keys that are not revoked, newest first. Expired keys
+// are included until they are revoked.
+func (uc *ListAPIKeysUseCase) Execute(ctx context.Context, input ListAPIKeysInput) ([]*user.APIKey, error) {
+	uc.logger.WithField("user_id", input.UserID).Debug("Listing API keys")
+
+	keys, err := uc.keys.ListByUser(ctx, input.UserID)
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to list API keys from database")
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
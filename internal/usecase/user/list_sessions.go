@@ -0,0 +1,63 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// ListSessionsInput represents input for listing a user's sessions
+type ListSessionsInput struct {
+	UserID string
+}
+
+// SessionOutput represents session data
+type SessionOutput struct {
+	ID         string
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+}
+
+// ListSessionsUseCase handles listing a user's active sessions
+type ListSessionsUseCase struct {
+	sessions user.SessionRepository
+	logger   *logger.Logger
+}
+
+// NewListSessionsUseCase creates a new use case instance
+func NewListSessionsUseCase(sessions user.SessionRepository, logger *logger.Logger) *ListSessionsUseCase {
+	return &ListSessionsUseCase{
+		sessions: sessions,
+		logger:   logger,
+	}
+}
+
+// Execute lists sessions that are neither revoked nor expired, most recently used first
+func (uc *ListSessionsUseCase) Execute(ctx context.Context, input ListSessionsInput) ([]*SessionOutput, error) {
+	uc.logger.WithField("user_id", input.UserID).Debug("Listing sessions")
+
+	sessions, err := uc.sessions.ListActive(ctx, input.UserID, time.Now())
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to list sessions from database")
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	output := make([]*SessionOutput, len(sessions))
+	for i, s := range sessions {
+		output[i] = &SessionOutput{
+			ID:         s.ID,
+			UserAgent:  s.UserAgent,
+			IPAddress:  s.IPAddress,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+			ExpiresAt:  s.ExpiresAt,
+		}
+	}
+	return output, nil
+}
@@ -0,0 +1,135 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+const (
+	defaultListLimit int32 = 10
+	maxListLimit     int32 = 100
+)
+
+// ListUsersInput represents pagination parameters for listing users.
+// PageToken takes precedence over Offset when both are set.
+type ListUsersInput struct {
+	Limit     int32
+	Offset    int32
+	PageToken string
+	// IncludePendingEmail returns the pending email addresses, which only
+	// admins may see
+	IncludePendingEmail bool
+}
+
+// ListUsersOutput represents a page of users
+type ListUsersOutput struct {
+	Users         []*GetUserOutput
+	Total         int64
+	NextPageToken string
+}
+
+// listCursor is the payload of an opaque page token
+type listCursor struct {
+	CreatedAt int64  `json:"c"`
+	ID        string `json:"i"`
+}
+
+// ListUsersUseCase handles paginated user listing
+type ListUsersUseCase struct {
+	repo   user.Repository
+	tokens PageTokenCodec
+	logger *logger.Logger
+}
+
+// NewListUsersUseCase creates a new use case instance
+func NewListUsersUseCase(repo user.Repository, tokens PageTokenCodec, logger *logger.Logger) *ListUsersUseCase {
+	return &ListUsersUseCase{
+		repo:   repo,
+		tokens: tokens,
+		logger: logger,
+	}
+}
+
+// Execute retrieves a page of users ordered from newest to oldest
+func (uc *ListUsersUseCase) Execute(ctx context.Context, input ListUsersInput) (*ListUsersOutput, error) {
+	uc.logger.WithFields(map[string]any{
+		"limit":      input.Limit,
+		"offset":     input.Offset,
+		"page_token": input.PageToken != "",
+	}).Debug("Listing users")
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	// Fetch one extra row to find out whether there is a next page
+	var (
+		users []*user.User
+		err   error
+	)
+	if input.PageToken != "" {
+		var cursor listCursor
+		if err := uc.tokens.Decode(input.PageToken, &cursor); err != nil {
+			return nil, fmt.Errorf("failed to decode page token: %w", err)
+		}
+		users, err = uc.repo.ListAfter(ctx, user.PageCursor{
+			CreatedAt: time.Unix(0, cursor.CreatedAt).UTC(),
+			ID:        cursor.ID,
+		}, limit+1)
+	} else {
+		users, err = uc.repo.List(ctx, limit+1, input.Offset)
+	}
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to list users from database")
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	total, err := uc.repo.Count(ctx)
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to count users in database")
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	output := &ListUsersOutput{Total: total}
+
+	if int32(len(users)) > limit {
+		users = users[:limit]
+		last := users[len(users)-1]
+		output.NextPageToken, err = uc.tokens.Encode(listCursor{
+			CreatedAt: last.CreatedAt.UnixNano(),
+			ID:        last.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode page token: %w", err)
+		}
+	}
+
+	output.Users = make([]*GetUserOutput, len(users))
+	for i, u := range users {
+		output.Users[i] = &GetUserOutput{
+			ID:    u.ID,
+			Email: u.Email,
+			Name:  u.Name,
+			Roles: u.RoleNames(),
+
+			EmailVerified: u.IsEmailVerified(),
+			Version:       u.Version,
+
+			CreatedAt: u.CreatedAt,
+			UpdatedAt: u.UpdatedAt,
+		}
+		if input.IncludePendingEmail {
+			output.Users[i].PendingEmail = u.PendingEmail
+		}
+	}
+
+	return output, nil
+}
@@ -0,0 +1,108 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/memclutter/go-microservices-template/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func makeUsers(n int) []*user.User {
+	base := time.Date(2025, 10, 30, 19, 0, 0, 0, time.UTC)
+	users := make([]*user.User, n)
+	for i := range users {
+		users[i] = &user.User{
+			ID:        fmt.Sprintf("user-%d", i),
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			Name:      fmt.Sprintf("User %d", i),
+			CreatedAt: base.Add(-time.Duration(i) * time.Minute),
+		}
+	}
+	return users
+}
+
+func TestListUsersUseCase_Execute(t *testing.T) {
+	codec, err := pagination.NewTokenCodec("test-secret")
+	require.NoError(t, err)
+	log := logger.New("test")
+
+	t.Run("first page returns next page token", func(t *testing.T) {
+		repo := new(MockRepository)
+		users := makeUsers(3)
+		repo.On("List", mock.Anything, int32(3), int32(0)).Return(users, nil)
+		repo.On("Count", mock.Anything).Return(int64(5), nil)
+
+		uc := NewListUsersUseCase(repo, codec, log)
+		result, err := uc.Execute(context.Background(), ListUsersInput{Limit: 2})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Users, 2)
+		assert.Equal(t, int64(5), result.Total)
+		assert.NotEmpty(t, result.NextPageToken)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("page token continues after last user", func(t *testing.T) {
+		repo := new(MockRepository)
+		users := makeUsers(3)
+		token, err := codec.Encode(listCursor{CreatedAt: users[1].CreatedAt.UnixNano(), ID: users[1].ID})
+		require.NoError(t, err)
+
+		cursor := user.PageCursor{CreatedAt: users[1].CreatedAt, ID: users[1].ID}
+		repo.On("ListAfter", mock.Anything, cursor, int32(3)).Return(users[2:], nil)
+		repo.On("Count", mock.Anything).Return(int64(3), nil)
+
+		uc := NewListUsersUseCase(repo, codec, log)
+		result, err := uc.Execute(context.Background(), ListUsersInput{Limit: 2, PageToken: token})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Users, 1)
+		assert.Equal(t, "user-2", result.Users[0].ID)
+		assert.Empty(t, result.NextPageToken)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("offset is still supported", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("List", mock.Anything, defaultListLimit+1, int32(20)).Return(makeUsers(1), nil)
+		repo.On("Count", mock.Anything).Return(int64(21), nil)
+
+		uc := NewListUsersUseCase(repo, codec, log)
+		result, err := uc.Execute(context.Background(), ListUsersInput{Offset: 20})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Users, 1)
+		assert.Empty(t, result.NextPageToken)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("limit is capped", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("List", mock.Anything, maxListLimit+1, int32(0)).Return(makeUsers(0), nil)
+		repo.On("Count", mock.Anything).Return(int64(0), nil)
+
+		uc := NewListUsersUseCase(repo, codec, log)
+		_, err := uc.Execute(context.Background(), ListUsersInput{Limit: 1000})
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("tampered page token is rejected", func(t *testing.T) {
+		repo := new(MockRepository)
+
+		uc := NewListUsersUseCase(repo, codec, log)
+		result, err := uc.Execute(context.Background(), ListUsersInput{PageToken: "eyJpIjoieCJ9.bad"})
+
+		assert.ErrorIs(t, err, pagination.ErrInvalidPageToken)
+		assert.Nil(t, result)
+		repo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,261 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// LoginInput represents user credentials and the client starting the session
+type LoginInput struct {
+	Email     string
+	Password  string
+	UserAgent string
+	IPAddress string
+}
+
+// SessionTokens represents the tokens issued for a session
+type SessionTokens struct {
+	UserID                string
+	SessionID             string
+	AccessToken           string
+	TokenType             string
+	ExpiresAt             time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// LoginOutput represents the result of a successful login.
+// For users with two-factor authentication enabled, it carries a challenge
+// instead of the session tokens.
+type LoginOutput struct {
+	SessionTokens
+	// TwoFactorRequired means ChallengeToken has to be passed with a second
+	// factor to VerifyLoginChallenge before ChallengeExpiresAt
+	TwoFactorRequired  bool
+	ChallengeToken     string
+	ChallengeExpiresAt time.Time
+}
+
+// LoginUseCase handles authentication with email and password
+type LoginUseCase struct {
+	repo                 user.Repository
+	sessions             user.SessionRepository
+	challenges           user.LoginChallengeRepository
+	tokens               TokenIssuer
+	hasher               user.PasswordHasher
+	throttle             *LoginThrottle
+	refreshTTL           time.Duration
+	challengeTTL         time.Duration
+	requireVerifiedEmail bool
+	logger               *logger.Logger
+
+	dummyHashOnce sync.Once
+	dummyHash     string
+}
+
+// NewLoginUseCase creates a new use case instance
+func NewLoginUseCase(
+	repo user.Repository,
+	sessions user.SessionRepository,
+	challenges user.LoginChallengeRepository,
+	tokens TokenIssuer,
+	hasher user.PasswordHasher,
+	throttle *LoginThrottle,
+	refreshTTL time.Duration,
+	challengeTTL time.Duration,
+	requireVerifiedEmail bool,
+	logger *logger.Logger,
+) *LoginUseCase {
+	return &LoginUseCase{
+		repo:                 repo,
+		sessions:             sessions,
+		challenges:           challenges,
+		tokens:               tokens,
+		hasher:               hasher,
+		throttle:             throttle,
+		refreshTTL:           refreshTTL,
+		challengeTTL:         challengeTTL,
+		requireVerifiedEmail: requireVerifiedEmail,
+		logger:               logger,
+	}
+}
+
+// compareDummyPassword spends the same time as a real password check so that
+// response timing does not reveal whether an email is registered
+func (uc *LoginUseCase) compareDummyPassword(password string) {
+	uc.dummyHashOnce.Do(func() {
+		uc.dummyHash, _ = uc.hasher.Hash("dummy-password")
+	})
+	if uc.dummyHash != "" {
+		_ = uc.hasher.Verify(uc.dummyHash, password)
+	}
+}
+
+// Execute verifies credentials, starts a session and issues its tokens.
+// Unknown email and wrong password both return user.ErrUnauthorized.
+// Attempts made too soon after repeated failures return a
+// *user.LoginThrottledError without checking the password.
+// When verified emails are required, correct credentials of an unverified
+// user return user.ErrEmailNotVerified.
+// Users with two-factor authentication enabled get a login challenge instead
+// of a session.
+func (uc *LoginUseCase) Execute(ctx context.Context, input LoginInput) (*LoginOutput, error) {
+	uc.logger.WithField("email", input.Email).Info("Logging in user")
+
+	// 1. Apply delay or lockout of earlier failures
+	if err := uc.throttle.Check(ctx, input.Email, input.IPAddress); err != nil {
+		if !errors.Is(err, user.ErrTooManyLoginAttempts) {
+			uc.logger.WithError(err).Error("Failed to check login failures")
+		}
+		return nil, err
+	}
+
+	// 2. Find user by email
+	u, err := uc.repo.GetByEmail(ctx, input.Email)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			uc.compareDummyPassword(input.Password)
+			uc.throttle.RecordFailure(ctx, nil, input.Email, input.IPAddress)
+			return nil, user.ErrUnauthorized
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 3. Verify password
+	if err := u.CheckPassword(input.Password, uc.hasher); err != nil {
+		uc.logger.WithField("user_id", u.ID).Info("Login failed: wrong password")
+		uc.throttle.RecordFailure(ctx, u, input.Email, input.IPAddress)
+		return nil, user.ErrUnauthorized
+	}
+	if !u.IsTwoFactorEnabled() {
+		// Otherwise failures are forgotten once the second factor is verified
+		uc.throttle.RecordSuccess(ctx, input.Email)
+	}
+	uc.upgradePasswordHash(ctx, u, input.Password)
+	if uc.requireVerifiedEmail && !u.IsEmailVerified() {
+		uc.logger.WithField("user_id", u.ID).Info("Login failed: email not verified")
+		return nil, user.ErrEmailNotVerified
+	}
+
+	// 4. Require second factor
+	if u.IsTwoFactorEnabled() {
+		return issueLoginChallenge(ctx, uc.challenges, u.ID, uc.challengeTTL, uc.logger)
+	}
+
+	// 5. Start session and issue its tokens
+	tokens, err := startSession(ctx, uc.sessions, uc.tokens, u.ID, input.UserAgent, input.IPAddress, uc.refreshTTL, uc.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.WithFields(map[string]any{
+		"user_id":    u.ID,
+		"session_id": tokens.SessionID,
+	}).Info("User logged in successfully")
+
+	return &LoginOutput{SessionTokens: *tokens}, nil
+}
+
+// issueLoginChallenge creates a login challenge to be completed with the second factor
+func issueLoginChallenge(
+	ctx context.Context,
+	challenges user.LoginChallengeRepository,
+	userID string,
+	challengeTTL time.Duration,
+	logger *logger.Logger,
+) (*LoginOutput, error) {
+	token, tokenHash, err := auth.NewSecretToken()
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := user.NewLoginChallenge(tokenHash, userID, challengeTTL)
+	if err := challenges.Create(ctx, challenge); err != nil {
+		logger.WithError(err).Error("Failed to save login challenge")
+		return nil, fmt.Errorf("failed to create login challenge: %w", err)
+	}
+
+	logger.WithField("user_id", userID).Info("Login requires second factor")
+
+	return &LoginOutput{
+		TwoFactorRequired:  true,
+		ChallengeToken:     token,
+		ChallengeExpiresAt: challenge.ExpiresAt,
+	}, nil
+}
+
+// upgradePasswordHash rehashes the just verified password when its hash was
+// made with an outdated algorithm or parameters
+func (uc *LoginUseCase) upgradePasswordHash(ctx context.Context, u *user.User, password string) {
+	upgraded, err := u.UpgradePasswordHash(password, uc.hasher)
+	if err != nil {
+		// Don't fail the login, the old hash still works
+		uc.logger.WithError(err).Warn("Failed to rehash password")
+		return
+	}
+	if !upgraded {
+		return
+	}
+	if err := uc.repo.UpdatePassword(ctx, u); err != nil {
+		// Don't fail the login, the old hash still works
+		uc.logger.WithError(err).Warn("Failed to save rehashed password")
+		return
+	}
+	uc.logger.WithField("user_id", u.ID).Info("Password rehashed with current settings")
+}
+
+// startSession creates a session of the user for the client and issues its tokens
+func startSession(
+	ctx context.Context,
+	sessions user.SessionRepository,
+	tokens TokenIssuer,
+	userID, userAgent, ipAddress string,
+	refreshTTL time.Duration,
+	logger *logger.Logger,
+) (*SessionTokens, error) {
+	sessionID := uuid.New().String()
+	refreshToken, refreshHash, err := auth.NewRefreshToken(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	session := user.NewSession(sessionID, userID, refreshHash, userAgent, ipAddress, refreshTTL)
+	if err := sessions.Create(ctx, session); err != nil {
+		logger.WithError(err).Error("Failed to save session")
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	sessionTokens, err := issueSessionTokens(tokens, session, refreshToken)
+	if err != nil {
+		logger.WithError(err).Error("Failed to issue access token")
+		return nil, err
+	}
+	return sessionTokens, nil
+}
+
+// issueSessionTokens signs an access token for the session and pairs it with the refresh token
+func issueSessionTokens(tokens TokenIssuer, session *user.Session, refreshToken string) (*SessionTokens, error) {
+	accessToken, expiresAt, err := tokens.IssueAccessToken(session.UserID, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue access token: %w", err)
+	}
+
+	return &SessionTokens{
+		UserID:                session.UserID,
+		SessionID:             session.ID,
+		AccessToken:           accessToken,
+		TokenType:             "Bearer",
+		ExpiresAt:             expiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: session.ExpiresAt,
+	}, nil
+}
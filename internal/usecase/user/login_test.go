@@ -0,0 +1,273 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/memclutter/go-microservices-template/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTokenIssuer struct {
+	mock.Mock
+}
+
+func (m *MockTokenIssuer) IssueAccessToken(userID, sessionID string) (string, time.Time, error) {
+	args := m.Called(userID, sessionID)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Create(ctx context.Context, s *user.Session) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) GetByID(ctx context.Context, id string) (*user.Session, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Rotate(ctx context.Context, s *user.Session, previousHash string) error {
+	args := m.Called(ctx, s, previousHash)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) ListActive(ctx context.Context, userID string, now time.Time) ([]*user.Session, error) {
+	args := m.Called(ctx, userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*user.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Revoke(ctx context.Context, userID, sessionID string, at time.Time) error {
+	args := m.Called(ctx, userID, sessionID, at)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeAll(ctx context.Context, userID string, at time.Time) (int64, error) {
+	args := m.Called(ctx, userID, at)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockLoginChallengeRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginChallengeRepository) Create(ctx context.Context, c *user.LoginChallenge) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockLoginChallengeRepository) GetByHash(ctx context.Context, tokenHash string) (*user.LoginChallenge, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.LoginChallenge), args.Error(1)
+}
+
+func (m *MockLoginChallengeRepository) Consume(ctx context.Context, c *user.LoginChallenge, at time.Time) error {
+	args := m.Called(ctx, c, at)
+	return args.Error(0)
+}
+
+func TestLoginUseCase_Execute(t *testing.T) {
+	existing, err := user.NewUser("test@example.com", "Test User", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+	existing.ID = "user-1"
+	expiresAt := time.Now().Add(15 * time.Minute)
+
+	tests := []struct {
+		name    string
+		input   LoginInput
+		setup   func(*MockRepository, *MockSessionRepository, *MockTokenIssuer)
+		wantErr error
+	}{
+		{
+			name:  "valid credentials",
+			input: LoginInput{Email: "test@example.com", Password: "password123", UserAgent: "curl/8.0"},
+			setup: func(repo *MockRepository, sessions *MockSessionRepository, tokens *MockTokenIssuer) {
+				repo.On("GetByEmail", mock.Anything, "test@example.com").Return(existing, nil)
+				sessions.On("Create", mock.Anything, mock.MatchedBy(func(s *user.Session) bool {
+					return s.UserID == "user-1" && s.UserAgent == "curl/8.0" && s.RefreshTokenHash != ""
+				})).Return(nil)
+				tokens.On("IssueAccessToken", "user-1", mock.Anything).Return("token", expiresAt, nil)
+			},
+		},
+		{
+			name:  "wrong password",
+			input: LoginInput{Email: "test@example.com", Password: "wrong-password"},
+			setup: func(repo *MockRepository, sessions *MockSessionRepository, tokens *MockTokenIssuer) {
+				repo.On("GetByEmail", mock.Anything, "test@example.com").Return(existing, nil)
+			},
+			wantErr: user.ErrUnauthorized,
+		},
+		{
+			name:  "unknown email",
+			input: LoginInput{Email: "missing@example.com", Password: "password123"},
+			setup: func(repo *MockRepository, sessions *MockSessionRepository, tokens *MockTokenIssuer) {
+				repo.On("GetByEmail", mock.Anything, "missing@example.com").Return(nil, user.ErrUserNotFound)
+			},
+			wantErr: user.ErrUnauthorized,
+		},
+		{
+			name:  "storage failure is not reported as unauthorized",
+			input: LoginInput{Email: "test@example.com", Password: "password123"},
+			setup: func(repo *MockRepository, sessions *MockSessionRepository, tokens *MockTokenIssuer) {
+				repo.On("GetByEmail", mock.Anything, "test@example.com").Return(nil, user.ErrStorageUnavailable)
+			},
+			wantErr: user.ErrStorageUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			sessions := new(MockSessionRepository)
+			tokens := new(MockTokenIssuer)
+			tt.setup(repo, sessions, tokens)
+
+			uc := NewLoginUseCase(repo, sessions, new(MockLoginChallengeRepository), tokens, testHasher, newPermissiveLoginThrottle(), time.Hour, 5*time.Minute, false, logger.New("test"))
+			result, err := uc.Execute(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "user-1", result.UserID)
+				assert.NotEmpty(t, result.SessionID)
+				assert.Equal(t, "token", result.AccessToken)
+				assert.Equal(t, "Bearer", result.TokenType)
+				assert.Equal(t, expiresAt, result.ExpiresAt)
+				assert.NotEmpty(t, result.RefreshToken)
+				assert.WithinDuration(t, time.Now().Add(time.Hour), result.RefreshTokenExpiresAt, time.Minute)
+			}
+
+			repo.AssertExpectations(t)
+			sessions.AssertExpectations(t)
+			tokens.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLoginUseCase_RequireVerifiedEmail(t *testing.T) {
+	unverified, err := user.NewUser("test@example.com", "Test User", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+	unverified.ID = "user-1"
+
+	repo := new(MockRepository)
+	repo.On("GetByEmail", mock.Anything, "test@example.com").Return(unverified, nil)
+	sessions := new(MockSessionRepository)
+	tokens := new(MockTokenIssuer)
+
+	uc := NewLoginUseCase(repo, sessions, new(MockLoginChallengeRepository), tokens, testHasher, newPermissiveLoginThrottle(), time.Hour, 5*time.Minute, true, logger.New("test"))
+
+	_, err = uc.Execute(context.Background(), LoginInput{Email: "test@example.com", Password: "wrong-password"})
+	assert.ErrorIs(t, err, user.ErrUnauthorized, "wrong password must not reveal verification state")
+
+	_, err = uc.Execute(context.Background(), LoginInput{Email: "test@example.com", Password: "password123"})
+	assert.ErrorIs(t, err, user.ErrEmailNotVerified)
+	sessions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	unverified.VerifyEmail()
+	sessions.On("Create", mock.Anything, mock.Anything).Return(nil)
+	tokens.On("IssueAccessToken", "user-1", mock.Anything).Return("token", time.Now().Add(time.Minute), nil)
+	_, err = uc.Execute(context.Background(), LoginInput{Email: "test@example.com", Password: "password123"})
+	assert.NoError(t, err)
+}
+
+func TestLoginUseCase_RehashesOutdatedPassword(t *testing.T) {
+	existing, err := user.NewUser("test@example.com", "Test User", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+	existing.ID = "user-1"
+	oldHash := existing.Password
+
+	// Prefer Argon2id while the stored hash is bcrypt
+	hasher, err := password.New(password.Config{
+		Algorithm: password.AlgorithmArgon2id,
+		Bcrypt:    password.BcryptParams{Cost: 4},
+		Argon2id:  password.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+	})
+	require.NoError(t, err)
+
+	repo := new(MockRepository)
+	repo.On("GetByEmail", mock.Anything, "test@example.com").Return(existing, nil)
+	repo.On("UpdatePassword", mock.Anything, existing).Return(nil).Once()
+	sessions := new(MockSessionRepository)
+	sessions.On("Create", mock.Anything, mock.Anything).Return(nil)
+	tokens := new(MockTokenIssuer)
+	tokens.On("IssueAccessToken", "user-1", mock.Anything).Return("token", time.Now().Add(time.Minute), nil)
+
+	uc := NewLoginUseCase(repo, sessions, new(MockLoginChallengeRepository), tokens, hasher, newPermissiveLoginThrottle(), time.Hour, 5*time.Minute, false, logger.New("test"))
+
+	_, err = uc.Execute(context.Background(), LoginInput{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	assert.NotEqual(t, oldHash, existing.Password)
+	assert.False(t, hasher.NeedsRehash(existing.Password))
+
+	// A current hash is left alone
+	_, err = uc.Execute(context.Background(), LoginInput{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestLoginUseCase_Throttled(t *testing.T) {
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	failures := new(MockLoginFailureRepository)
+	failures.On("Get", mock.Anything, user.LoginSubjectEmail, "test@example.com").
+		Return(&user.LoginFailures{Count: 10, LastFailedAt: time.Now(), LockedUntil: &lockedUntil}, nil)
+	failures.On("Get", mock.Anything, user.LoginSubjectIP, "192.0.2.1").Return(&user.LoginFailures{}, nil)
+	throttle := NewLoginThrottle(failures, user.LoginThrottlePolicy{}, user.LoginThrottlePolicy{}, new(MockEventPublisher), logger.New("test"))
+
+	repo := new(MockRepository)
+	uc := NewLoginUseCase(repo, new(MockSessionRepository), new(MockLoginChallengeRepository), new(MockTokenIssuer), testHasher, throttle, time.Hour, 5*time.Minute, false, logger.New("test"))
+
+	_, err := uc.Execute(context.Background(), LoginInput{Email: "test@example.com", Password: "password123", IPAddress: "192.0.2.1"})
+	assert.ErrorIs(t, err, user.ErrTooManyLoginAttempts)
+	repo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+}
+
+func TestLoginUseCase_TwoFactorChallenge(t *testing.T) {
+	existing, err := user.NewUser("test@example.com", "Test User", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+	existing.ID = "user-1"
+	enabledAt := time.Now()
+	existing.TwoFactorEnabledAt = &enabledAt
+
+	repo := new(MockRepository)
+	repo.On("GetByEmail", mock.Anything, "test@example.com").Return(existing, nil)
+	sessions := new(MockSessionRepository)
+	challenges := new(MockLoginChallengeRepository)
+	challenges.On("Create", mock.Anything, mock.MatchedBy(func(c *user.LoginChallenge) bool {
+		return c.UserID == "user-1" && c.TokenHash != ""
+	})).Return(nil)
+	failures := new(MockLoginFailureRepository)
+	failures.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(&user.LoginFailures{}, nil)
+	throttle := NewLoginThrottle(failures, user.LoginThrottlePolicy{}, user.LoginThrottlePolicy{}, new(MockEventPublisher), logger.New("test"))
+
+	uc := NewLoginUseCase(repo, sessions, challenges, new(MockTokenIssuer), testHasher, throttle, time.Hour, 5*time.Minute, false, logger.New("test"))
+	result, err := uc.Execute(context.Background(), LoginInput{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	assert.True(t, result.TwoFactorRequired)
+	assert.NotEmpty(t, result.ChallengeToken)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), result.ChallengeExpiresAt, time.Minute)
+	assert.Empty(t, result.AccessToken)
+	sessions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	failures.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything, mock.Anything)
+	challenges.AssertExpectations(t)
+}
@@ -0,0 +1,133 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// LoginThrottle slows down and then locks out repeated failed logins per
+// email and per client IP address
+type LoginThrottle struct {
+	failures    user.LoginFailureRepository
+	emailPolicy user.LoginThrottlePolicy
+	ipPolicy    user.LoginThrottlePolicy
+	eventPub    EventPublisher
+	logger      *logger.Logger
+}
+
+// NewLoginThrottle creates a new login throttle
+func NewLoginThrottle(
+	failures user.LoginFailureRepository,
+	emailPolicy user.LoginThrottlePolicy,
+	ipPolicy user.LoginThrottlePolicy,
+	eventPub EventPublisher,
+	logger *logger.Logger,
+) *LoginThrottle {
+	return &LoginThrottle{
+		failures:    failures,
+		emailPolicy: emailPolicy,
+		ipPolicy:    ipPolicy,
+		eventPub:    eventPub,
+		logger:      logger,
+	}
+}
+
+// throttledSubject is a subject key with the policy applied to it
+type throttledSubject struct {
+	subject user.LoginSubject
+	key     string
+	policy  user.LoginThrottlePolicy
+}
+
+func (t *LoginThrottle) subjects(email, ipAddress string) []throttledSubject {
+	subjects := []throttledSubject{{subject: user.LoginSubjectEmail, key: email, policy: t.emailPolicy}}
+	if ipAddress != "" {
+		subjects = append(subjects, throttledSubject{subject: user.LoginSubjectIP, key: ipAddress, policy: t.ipPolicy})
+	}
+	return subjects
+}
+
+// Check returns a *user.LoginThrottledError when earlier failures of the email
+// or IP address require the caller to wait before the next attempt
+func (t *LoginThrottle) Check(ctx context.Context, email, ipAddress string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+	for _, s := range t.subjects(email, ipAddress) {
+		failures, err := t.failures.Get(ctx, s.subject, s.key)
+		if err != nil {
+			return fmt.Errorf("failed to get login failures: %w", err)
+		}
+		retryAfter = max(retryAfter, failures.RetryAfter(s.policy, now))
+	}
+
+	if retryAfter > 0 {
+		t.logger.WithFields(map[string]any{
+			"email":       email,
+			"ip_address":  ipAddress,
+			"retry_after": retryAfter.String(),
+		}).Info("Login throttled")
+		return &user.LoginThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login of email from ipAddress, locking either
+// out once it reaches its threshold. u is nil when email is not registered.
+// Errors are only logged, so that the caller still reports the failed login.
+func (t *LoginThrottle) RecordFailure(ctx context.Context, u *user.User, email, ipAddress string) {
+	now := time.Now()
+	for _, s := range t.subjects(email, ipAddress) {
+		failures, err := t.failures.RecordFailure(ctx, s.subject, s.key, now, s.policy.WindowStart(now))
+		if err != nil {
+			t.logger.WithError(err).Warn("Failed to record login failure")
+			continue
+		}
+		if !failures.Lock(s.policy, now) {
+			continue
+		}
+		if err := t.failures.Lock(ctx, failures); err != nil {
+			t.logger.WithError(err).Warn("Failed to lock login")
+			continue
+		}
+
+		t.logger.WithFields(map[string]any{
+			"subject":      string(s.subject),
+			"key":          s.key,
+			"failures":     failures.Count,
+			"locked_until": *failures.LockedUntil,
+		}).Warn("Login locked after repeated failures")
+
+		if s.subject == user.LoginSubjectEmail && u != nil {
+			event := user.UserLockedEvent{
+				UserID:      u.ID,
+				Email:       u.Email,
+				Failures:    failures.Count,
+				LockedUntil: *failures.LockedUntil,
+			}
+			if err := t.eventPub.Publish(ctx, user.EventTypeUserLocked, event); err != nil {
+				// Don't fail the use case, just log the error
+				t.logger.WithError(err).Warn("Failed to publish user locked event")
+			}
+		}
+	}
+}
+
+// RecordSuccess forgets the failed logins of email. Failures of the IP
+// address are kept, so that one known account cannot reset them.
+func (t *LoginThrottle) RecordSuccess(ctx context.Context, email string) {
+	if err := t.failures.Reset(ctx, user.LoginSubjectEmail, email); err != nil {
+		t.logger.WithError(err).Warn("Failed to reset login failures")
+	}
+}
+
+// Unlock forgets the failed logins and lockout of email
+func (t *LoginThrottle) Unlock(ctx context.Context, email string) error {
+	if err := t.failures.Reset(ctx, user.LoginSubjectEmail, email); err != nil {
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	return nil
+}
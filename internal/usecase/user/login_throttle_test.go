@@ -0,0 +1,99 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockLoginFailureRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginFailureRepository) Get(ctx context.Context, subject user.LoginSubject, key string) (*user.LoginFailures, error) {
+	args := m.Called(ctx, subject, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.LoginFailures), args.Error(1)
+}
+
+func (m *MockLoginFailureRepository) RecordFailure(ctx context.Context, subject user.LoginSubject, key string, at, windowStart time.Time) (*user.LoginFailures, error) {
+	args := m.Called(ctx, subject, key, at, windowStart)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.LoginFailures), args.Error(1)
+}
+
+func (m *MockLoginFailureRepository) Lock(ctx context.Context, failures *user.LoginFailures) error {
+	args := m.Called(ctx, failures)
+	return args.Error(0)
+}
+
+func (m *MockLoginFailureRepository) Reset(ctx context.Context, subject user.LoginSubject, key string) error {
+	args := m.Called(ctx, subject, key)
+	return args.Error(0)
+}
+
+// newPermissiveLoginThrottle returns a throttle that never delays or locks logins
+func newPermissiveLoginThrottle() *LoginThrottle {
+	failures := new(MockLoginFailureRepository)
+	failures.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(&user.LoginFailures{}, nil).Maybe()
+	failures.On("RecordFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&user.LoginFailures{Count: 1}, nil).Maybe()
+	failures.On("Reset", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return NewLoginThrottle(failures, user.LoginThrottlePolicy{}, user.LoginThrottlePolicy{}, new(MockEventPublisher), logger.New("test"))
+}
+
+func TestLoginThrottle_Check(t *testing.T) {
+	policy := user.LoginThrottlePolicy{DelayAfter: 3, BaseDelay: time.Minute, LockAfter: 5, LockDuration: time.Hour}
+	lockedUntil := time.Now().Add(time.Hour)
+
+	failures := new(MockLoginFailureRepository)
+	failures.On("Get", mock.Anything, user.LoginSubjectEmail, "test@example.com").
+		Return(&user.LoginFailures{Count: 1, LastFailedAt: time.Now()}, nil)
+	failures.On("Get", mock.Anything, user.LoginSubjectIP, "192.0.2.1").
+		Return(&user.LoginFailures{Count: 5, LastFailedAt: time.Now(), LockedUntil: &lockedUntil}, nil)
+	failures.On("Get", mock.Anything, user.LoginSubjectIP, "192.0.2.2").
+		Return(&user.LoginFailures{}, nil)
+
+	throttle := NewLoginThrottle(failures, policy, policy, new(MockEventPublisher), logger.New("test"))
+
+	assert.NoError(t, throttle.Check(context.Background(), "test@example.com", "192.0.2.2"))
+
+	err := throttle.Check(context.Background(), "test@example.com", "192.0.2.1")
+	assert.ErrorIs(t, err, user.ErrTooManyLoginAttempts, "a locked IP address blocks every email")
+	var throttled *user.LoginThrottledError
+	require.ErrorAs(t, err, &throttled)
+	assert.InDelta(t, time.Hour, throttled.RetryAfter, float64(time.Minute))
+}
+
+func TestLoginThrottle_RecordFailureLocksUser(t *testing.T) {
+	policy := user.LoginThrottlePolicy{LockAfter: 3, LockDuration: 15 * time.Minute, ResetAfter: time.Hour}
+	u := &user.User{ID: "user-1", Email: "test@example.com"}
+
+	failures := new(MockLoginFailureRepository)
+	failures.On("RecordFailure", mock.Anything, user.LoginSubjectEmail, "test@example.com", mock.Anything, mock.Anything).
+		Return(&user.LoginFailures{Subject: user.LoginSubjectEmail, Key: "test@example.com", Count: 3}, nil)
+	failures.On("RecordFailure", mock.Anything, user.LoginSubjectIP, "192.0.2.1", mock.Anything, mock.Anything).
+		Return(&user.LoginFailures{Subject: user.LoginSubjectIP, Key: "192.0.2.1", Count: 1}, nil)
+	failures.On("Lock", mock.Anything, mock.MatchedBy(func(f *user.LoginFailures) bool {
+		return f.Subject == user.LoginSubjectEmail && f.LockedUntil != nil
+	})).Return(nil).Once()
+	pub := new(MockEventPublisher)
+	pub.On("Publish", mock.Anything, user.EventTypeUserLocked, mock.MatchedBy(func(e user.UserLockedEvent) bool {
+		return e.UserID == "user-1" && e.Failures == 3 && time.Until(e.LockedUntil) > 14*time.Minute
+	})).Return(nil).Once()
+
+	throttle := NewLoginThrottle(failures, policy, user.LoginThrottlePolicy{LockAfter: 10}, pub, logger.New("test"))
+	throttle.RecordFailure(context.Background(), u, "test@example.com", "192.0.2.1")
+
+	failures.AssertExpectations(t)
+	pub.AssertExpectations(t)
+}
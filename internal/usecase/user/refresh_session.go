@@ -0,0 +1,118 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// RefreshSessionInput represents a refresh token presented by a client
+type RefreshSessionInput struct {
+	RefreshToken string
+	UserAgent    string
+	IPAddress    string
+}
+
+// RefreshSessionUseCase exchanges a refresh token for a new token pair
+type RefreshSessionUseCase struct {
+	sessions   user.SessionRepository
+	tokens     TokenIssuer
+	refreshTTL time.Duration
+	logger     *logger.Logger
+}
+
+// NewRefreshSessionUseCase creates a new use case instance
+func NewRefreshSessionUseCase(
+	sessions user.SessionRepository,
+	tokens TokenIssuer,
+	refreshTTL time.Duration,
+	logger *logger.Logger,
+) *RefreshSessionUseCase {
+	return &RefreshSessionUseCase{
+		sessions:   sessions,
+		tokens:     tokens,
+		refreshTTL: refreshTTL,
+		logger:     logger,
+	}
+}
+
+// Execute rotates the session refresh token and issues a new access token.
+// Presenting an already rotated token is treated as theft: the session is
+// revoked and user.ErrUnauthorized is returned.
+func (uc *RefreshSessionUseCase) Execute(ctx context.Context, input RefreshSessionInput) (*SessionTokens, error) {
+	// 1. Parse refresh token
+	sessionID, presentedHash, err := auth.ParseRefreshToken(input.RefreshToken)
+	if err != nil {
+		return nil, user.ErrUnauthorized
+	}
+
+	uc.logger.WithField("session_id", sessionID).Info("Refreshing session")
+
+	// 2. Load session
+	session, err := uc.sessions.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, user.ErrSessionNotFound) {
+			return nil, user.ErrUnauthorized
+		}
+		uc.logger.WithError(err).Error("Failed to get session from database")
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if !session.IsActive(time.Now()) {
+		return nil, user.ErrUnauthorized
+	}
+
+	// 3. Detect refresh token reuse
+	if session.RefreshTokenHash != presentedHash {
+		uc.revokeReused(ctx, session)
+		return nil, user.ErrUnauthorized
+	}
+
+	// 4. Rotate refresh token
+	refreshToken, refreshHash, err := auth.NewRefreshToken(session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Rotate(refreshHash, input.UserAgent, input.IPAddress, uc.refreshTTL)
+	if err := uc.sessions.Rotate(ctx, session, presentedHash); err != nil {
+		if errors.Is(err, user.ErrSessionNotFound) {
+			// Another request rotated or revoked the session first
+			uc.revokeReused(ctx, session)
+			return nil, user.ErrUnauthorized
+		}
+		uc.logger.WithError(err).Error("Failed to rotate session")
+		return nil, fmt.Errorf("failed to rotate session: %w", err)
+	}
+
+	// 5. Issue access token
+	tokens, err := issueSessionTokens(uc.tokens, session, refreshToken)
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to issue access token")
+		return nil, err
+	}
+
+	uc.logger.WithFields(map[string]any{
+		"user_id":    session.UserID,
+		"session_id": session.ID,
+	}).Info("Session refreshed successfully")
+
+	return tokens, nil
+}
+
+// revokeReused revokes a session whose refresh token was presented twice
+func (uc *RefreshSessionUseCase) revokeReused(ctx context.Context, session *user.Session) {
+	uc.logger.WithFields(map[string]any{
+		"user_id":    session.UserID,
+		"session_id": session.ID,
+	}).Warn("Refresh token reuse detected, revoking session")
+
+	err := uc.sessions.Revoke(ctx, session.UserID, session.ID, time.Now())
+	if err != nil && !errors.Is(err, user.ErrSessionNotFound) {
+		uc.logger.WithError(err).Error("Failed to revoke reused session")
+	}
+}
@@ -0,0 +1,109 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshSessionUseCase_Execute(t *testing.T) {
+	log := logger.New("test")
+	expiresAt := time.Now().Add(15 * time.Minute)
+
+	newSession := func(t *testing.T) (*user.Session, string) {
+		token, hash, err := auth.NewRefreshToken("session-1")
+		require.NoError(t, err)
+		return user.NewSession("session-1", "user-1", hash, "", "", time.Hour), token
+	}
+
+	t.Run("rotates refresh token", func(t *testing.T) {
+		session, token := newSession(t)
+		oldHash := session.RefreshTokenHash
+
+		sessions := new(MockSessionRepository)
+		tokens := new(MockTokenIssuer)
+		sessions.On("GetByID", mock.Anything, "session-1").Return(session, nil)
+		sessions.On("Rotate", mock.Anything, session, oldHash).Return(nil)
+		tokens.On("IssueAccessToken", "user-1", "session-1").Return("access", expiresAt, nil)
+
+		uc := NewRefreshSessionUseCase(sessions, tokens, time.Hour, log)
+		result, err := uc.Execute(context.Background(), RefreshSessionInput{RefreshToken: token})
+
+		require.NoError(t, err)
+		assert.Equal(t, "access", result.AccessToken)
+		assert.NotEqual(t, token, result.RefreshToken)
+		assert.NotEqual(t, oldHash, session.RefreshTokenHash)
+		sessions.AssertExpectations(t)
+		tokens.AssertExpectations(t)
+	})
+
+	t.Run("reused refresh token revokes session", func(t *testing.T) {
+		session, _ := newSession(t)
+		staleToken, _, err := auth.NewRefreshToken("session-1")
+		require.NoError(t, err)
+
+		sessions := new(MockSessionRepository)
+		sessions.On("GetByID", mock.Anything, "session-1").Return(session, nil)
+		sessions.On("Revoke", mock.Anything, "user-1", "session-1", mock.Anything).Return(nil)
+
+		uc := NewRefreshSessionUseCase(sessions, new(MockTokenIssuer), time.Hour, log)
+		result, err := uc.Execute(context.Background(), RefreshSessionInput{RefreshToken: staleToken})
+
+		assert.ErrorIs(t, err, user.ErrUnauthorized)
+		assert.Nil(t, result)
+		sessions.AssertExpectations(t)
+	})
+
+	t.Run("concurrent rotation is treated as reuse", func(t *testing.T) {
+		session, token := newSession(t)
+
+		sessions := new(MockSessionRepository)
+		sessions.On("GetByID", mock.Anything, "session-1").Return(session, nil)
+		sessions.On("Rotate", mock.Anything, session, mock.Anything).Return(user.ErrSessionNotFound)
+		sessions.On("Revoke", mock.Anything, "user-1", "session-1", mock.Anything).Return(nil)
+
+		uc := NewRefreshSessionUseCase(sessions, new(MockTokenIssuer), time.Hour, log)
+		_, err := uc.Execute(context.Background(), RefreshSessionInput{RefreshToken: token})
+
+		assert.ErrorIs(t, err, user.ErrUnauthorized)
+		sessions.AssertExpectations(t)
+	})
+
+	t.Run("revoked session is rejected", func(t *testing.T) {
+		session, token := newSession(t)
+		revokedAt := time.Now()
+		session.RevokedAt = &revokedAt
+
+		sessions := new(MockSessionRepository)
+		sessions.On("GetByID", mock.Anything, "session-1").Return(session, nil)
+
+		uc := NewRefreshSessionUseCase(sessions, new(MockTokenIssuer), time.Hour, log)
+		_, err := uc.Execute(context.Background(), RefreshSessionInput{RefreshToken: token})
+
+		assert.ErrorIs(t, err, user.ErrUnauthorized)
+		sessions.AssertExpectations(t)
+	})
+
+	t.Run("unknown or malformed token is rejected", func(t *testing.T) {
+		token, _, err := auth.NewRefreshToken("missing")
+		require.NoError(t, err)
+
+		sessions := new(MockSessionRepository)
+		sessions.On("GetByID", mock.Anything, "missing").Return(nil, user.ErrSessionNotFound)
+
+		uc := NewRefreshSessionUseCase(sessions, new(MockTokenIssuer), time.Hour, log)
+		_, err = uc.Execute(context.Background(), RefreshSessionInput{RefreshToken: token})
+		assert.ErrorIs(t, err, user.ErrUnauthorized)
+
+		_, err = uc.Execute(context.Background(), RefreshSessionInput{RefreshToken: "garbage"})
+		assert.ErrorIs(t, err, user.ErrUnauthorized)
+		sessions.AssertExpectations(t)
+	})
+}
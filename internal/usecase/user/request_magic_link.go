@@ -0,0 +1,119 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// RequestMagicLinkInput represents the email of a user logging in without a password
+type RequestMagicLinkInput struct {
+	Email string
+}
+
+// MagicLinkRateLimit limits how many magic links an email receives
+type MagicLinkRateLimit struct {
+	// MaxRequests is the number of links issued per Window, zero for no limit
+	MaxRequests int
+	Window      time.Duration
+}
+
+// RequestMagicLinkUseCase handles issuing magic link login tokens
+type RequestMagicLinkUseCase struct {
+	repo      user.Repository
+	links     user.MagicLinkRepository
+	eventPub  EventPublisher
+	ttl       time.Duration
+	rateLimit MagicLinkRateLimit
+	logger    *logger.Logger
+}
+
+// NewRequestMagicLinkUseCase creates a new use case instance
+func NewRequestMagicLinkUseCase(
+	repo user.Repository,
+	links user.MagicLinkRepository,
+	eventPub EventPublisher,
+	ttl time.Duration,
+	rateLimit MagicLinkRateLimit,
+	logger *logger.Logger,
+) *RequestMagicLinkUseCase {
+	return &RequestMagicLinkUseCase{
+		repo:      repo,
+		links:     links,
+		eventPub:  eventPub,
+		ttl:       ttl,
+		rateLimit: rateLimit,
+		logger:    logger,
+	}
+}
+
+// Execute issues a login token and publishes it for delivery to the user.
+// An unknown email, and an email that already received the maximum number of
+// links within the rate limit window, succeed without doing anything, so that
+// callers cannot probe which emails are registered or flood a mailbox.
+func (uc *RequestMagicLinkUseCase) Execute(ctx context.Context, input RequestMagicLinkInput) error {
+	uc.logger.WithField("email", input.Email).Info("Requesting magic link")
+
+	// 1. Find user by email
+	u, err := uc.repo.GetByEmail(ctx, input.Email)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			uc.logger.WithField("email", input.Email).Info("Magic link requested for unknown email")
+			return nil
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 2. Apply rate limit
+	// The request is counted atomically before the check, so concurrent
+	// requests cannot all pass the limit
+	if uc.rateLimit.MaxRequests > 0 {
+		now := time.Now()
+		count, err := uc.links.RecordRequest(ctx, u.ID, now, now.Add(-uc.rateLimit.Window))
+		if err != nil {
+			uc.logger.WithError(err).Error("Failed to count magic link requests")
+			return fmt.Errorf("failed to count magic link requests: %w", err)
+		}
+		if count > uc.rateLimit.MaxRequests {
+			uc.logger.WithField("user_id", u.ID).Warn("Magic link rate limit exceeded")
+			return nil
+		}
+	}
+
+	// 3. Issue login token
+	token, tokenHash, err := auth.NewSecretToken()
+	if err != nil {
+		return err
+	}
+
+	link := user.NewMagicLinkToken(tokenHash, u.ID, uc.ttl)
+	if err := uc.links.Create(ctx, link); err != nil {
+		uc.logger.WithError(err).Error("Failed to save magic link token")
+		return fmt.Errorf("failed to create magic link token: %w", err)
+	}
+
+	// 4. Publish domain event
+	// The event is the only way the token reaches the user, so a failure
+	// to publish fails the request
+	event := user.MagicLinkRequestedEvent{
+		UserID:    u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		Token:     token,
+		ExpiresAt: link.ExpiresAt,
+	}
+	if err := uc.eventPub.Publish(ctx, user.EventTypeMagicLinkRequested, event); err != nil {
+		uc.logger.WithError(err).Error("Failed to publish magic link requested event")
+		return fmt.Errorf("failed to publish magic link: %w", err)
+	}
+
+	uc.logger.WithField("user_id", u.ID).Info("Magic link issued")
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockMagicLinkRepository struct {
+	mock.Mock
+}
+
+func (m *MockMagicLinkRepository) Create(ctx context.Context, t *user.MagicLinkToken) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockMagicLinkRepository) GetByHash(ctx context.Context, tokenHash string) (*user.MagicLinkToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.MagicLinkToken), args.Error(1)
+}
+
+func (m *MockMagicLinkRepository) Consume(ctx context.Context, t *user.MagicLinkToken, at time.Time) error {
+	args := m.Called(ctx, t, at)
+	return args.Error(0)
+}
+
+func (m *MockMagicLinkRepository) RecordRequest(ctx context.Context, userID string, at, windowStart time.Time) (int, error) {
+	args := m.Called(ctx, userID, at, windowStart)
+	return args.Int(0), args.Error(1)
+}
+
+func TestRequestMagicLinkUseCase_Execute(t *testing.T) {
+	existing, err := user.NewUser("test@example.com", "Test User", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+	existing.ID = "user-1"
+	rateLimit := MagicLinkRateLimit{MaxRequests: 3, Window: time.Hour}
+
+	t.Run("publishes token matching the stored hash", func(t *testing.T) {
+		repo := new(MockRepository)
+		links := new(MockMagicLinkRepository)
+		pub := new(MockEventPublisher)
+
+		var stored *user.MagicLinkToken
+		repo.On("GetByEmail", mock.Anything, "test@example.com").Return(existing, nil)
+		links.On("RecordRequest", mock.Anything, "user-1", mock.Anything, mock.MatchedBy(func(windowStart time.Time) bool {
+			return time.Since(windowStart) > 59*time.Minute && time.Since(windowStart) < 61*time.Minute
+		})).Return(3, nil)
+		links.On("Create", mock.Anything, mock.AnythingOfType("*user.MagicLinkToken")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*user.MagicLinkToken) }).
+			Return(nil)
+		pub.On("Publish", mock.Anything, user.EventTypeMagicLinkRequested, mock.Anything).Return(nil)
+
+		uc := NewRequestMagicLinkUseCase(repo, links, pub, 15*time.Minute, rateLimit, logger.New("test"))
+		require.NoError(t, uc.Execute(context.Background(), RequestMagicLinkInput{Email: "test@example.com"}))
+
+		event := pub.Calls[0].Arguments.Get(2).(user.MagicLinkRequestedEvent)
+		assert.Equal(t, "user-1", event.UserID)
+		assert.Equal(t, "test@example.com", event.Email)
+		assert.Equal(t, stored.TokenHash, auth.HashSecretToken(event.Token))
+		assert.Equal(t, "user-1", stored.UserID)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), stored.ExpiresAt, time.Minute)
+	})
+
+	t.Run("unknown email succeeds silently", func(t *testing.T) {
+		repo := new(MockRepository)
+		links := new(MockMagicLinkRepository)
+		pub := new(MockEventPublisher)
+		repo.On("GetByEmail", mock.Anything, "missing@example.com").Return(nil, user.ErrUserNotFound)
+
+		uc := NewRequestMagicLinkUseCase(repo, links, pub, 15*time.Minute, rateLimit, logger.New("test"))
+		assert.NoError(t, uc.Execute(context.Background(), RequestMagicLinkInput{Email: "missing@example.com"}))
+
+		links.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		pub.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rate limited email succeeds silently", func(t *testing.T) {
+		repo := new(MockRepository)
+		links := new(MockMagicLinkRepository)
+		pub := new(MockEventPublisher)
+		repo.On("GetByEmail", mock.Anything, "test@example.com").Return(existing, nil)
+		links.On("RecordRequest", mock.Anything, "user-1", mock.Anything, mock.Anything).Return(4, nil)
+
+		uc := NewRequestMagicLinkUseCase(repo, links, pub, 15*time.Minute, rateLimit, logger.New("test"))
+		assert.NoError(t, uc.Execute(context.Background(), RequestMagicLinkInput{Email: "test@example.com"}))
+
+		links.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		pub.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("publish failure fails the request", func(t *testing.T) {
+		repo := new(MockRepository)
+		links := new(MockMagicLinkRepository)
+		pub := new(MockEventPublisher)
+		repo.On("GetByEmail", mock.Anything, "test@example.com").Return(existing, nil)
+		links.On("RecordRequest", mock.Anything, "user-1", mock.Anything, mock.Anything).Return(1, nil)
+		links.On("Create", mock.Anything, mock.Anything).Return(nil)
+		pub.On("Publish", mock.Anything, user.EventTypeMagicLinkRequested, mock.Anything).Return(errors.New("broker down"))
+
+		uc := NewRequestMagicLinkUseCase(repo, links, pub, 15*time.Minute, rateLimit, logger.New("test"))
+		assert.Error(t, uc.Execute(context.Background(), RequestMagicLinkInput{Email: "test@example.com"}))
+	})
+}
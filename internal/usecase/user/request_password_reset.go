@@ -0,0 +1,92 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// RequestPasswordResetInput represents the email of a user who forgot their password
+type RequestPasswordResetInput struct {
+	Email string
+}
+
+// RequestPasswordResetUseCase handles issuing password reset tokens
+type RequestPasswordResetUseCase struct {
+	repo     user.Repository
+	resets   user.PasswordResetRepository
+	eventPub EventPublisher
+	ttl      time.Duration
+	logger   *logger.Logger
+}
+
+// NewRequestPasswordResetUseCase creates a new use case instance
+func NewRequestPasswordResetUseCase(
+	repo user.Repository,
+	resets user.PasswordResetRepository,
+	eventPub EventPublisher,
+	ttl time.Duration,
+	logger *logger.Logger,
+) *RequestPasswordResetUseCase {
+	return &RequestPasswordResetUseCase{
+		repo:     repo,
+		resets:   resets,
+		eventPub: eventPub,
+		ttl:      ttl,
+		logger:   logger,
+	}
+}
+
+// Execute issues a reset token and publishes it for delivery to the user.
+// An unknown email succeeds without doing anything, so that callers cannot
+// probe which emails are registered.
+func (uc *RequestPasswordResetUseCase) Execute(ctx context.Context, input RequestPasswordResetInput) error {
+	uc.logger.WithField("email", input.Email).Info("Requesting password reset")
+
+	// 1. Find user by email
+	u, err := uc.repo.GetByEmail(ctx, input.Email)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			uc.logger.WithField("email", input.Email).Info("Password reset requested for unknown email")
+			return nil
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 2. Issue reset token
+	token, tokenHash, err := auth.NewSecretToken()
+	if err != nil {
+		return err
+	}
+
+	resetToken := user.NewPasswordResetToken(tokenHash, u.ID, uc.ttl)
+	if err := uc.resets.Create(ctx, resetToken); err != nil {
+		uc.logger.WithError(err).Error("Failed to save password reset token")
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	// 3. Publish domain event
+	// The event is the only way the token reaches the user, so a failure
+	// to publish fails the request
+	event := user.PasswordResetRequestedEvent{
+		UserID:    u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		Token:     token,
+		ExpiresAt: resetToken.ExpiresAt,
+	}
+	if err := uc.eventPub.Publish(ctx, user.EventTypePasswordResetRequested, event); err != nil {
+		uc.logger.WithError(err).Error("Failed to publish password reset requested event")
+		return fmt.Errorf("failed to publish password reset: %w", err)
+	}
+
+	uc.logger.WithField("user_id", u.ID).Info("Password reset token issued")
+
+	return nil
+}
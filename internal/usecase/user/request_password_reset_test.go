@@ -0,0 +1,91 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) Create(ctx context.Context, t *user.PasswordResetToken) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) GetByHash(ctx context.Context, tokenHash string) (*user.PasswordResetToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) Consume(ctx context.Context, t *user.PasswordResetToken, at time.Time) error {
+	args := m.Called(ctx, t, at)
+	return args.Error(0)
+}
+
+func TestRequestPasswordResetUseCase_Execute(t *testing.T) {
+	existing, err := user.NewUser("test@example.com", "Test User", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+	existing.ID = "user-1"
+
+	t.Run("publishes token matching the stored hash", func(t *testing.T) {
+		repo := new(MockRepository)
+		resets := new(MockPasswordResetRepository)
+		pub := new(MockEventPublisher)
+
+		var stored *user.PasswordResetToken
+		repo.On("GetByEmail", mock.Anything, "test@example.com").Return(existing, nil)
+		resets.On("Create", mock.Anything, mock.AnythingOfType("*user.PasswordResetToken")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*user.PasswordResetToken) }).
+			Return(nil)
+		pub.On("Publish", mock.Anything, user.EventTypePasswordResetRequested, mock.Anything).Return(nil)
+
+		uc := NewRequestPasswordResetUseCase(repo, resets, pub, time.Hour, logger.New("test"))
+		require.NoError(t, uc.Execute(context.Background(), RequestPasswordResetInput{Email: "test@example.com"}))
+
+		event := pub.Calls[0].Arguments.Get(2).(user.PasswordResetRequestedEvent)
+		assert.Equal(t, "user-1", event.UserID)
+		assert.Equal(t, "test@example.com", event.Email)
+		assert.Equal(t, stored.TokenHash, auth.HashSecretToken(event.Token))
+		assert.Equal(t, "user-1", stored.UserID)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+	})
+
+	t.Run("unknown email succeeds silently", func(t *testing.T) {
+		repo := new(MockRepository)
+		resets := new(MockPasswordResetRepository)
+		pub := new(MockEventPublisher)
+		repo.On("GetByEmail", mock.Anything, "missing@example.com").Return(nil, user.ErrUserNotFound)
+
+		uc := NewRequestPasswordResetUseCase(repo, resets, pub, time.Hour, logger.New("test"))
+		assert.NoError(t, uc.Execute(context.Background(), RequestPasswordResetInput{Email: "missing@example.com"}))
+
+		resets.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		pub.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("publish failure fails the request", func(t *testing.T) {
+		repo := new(MockRepository)
+		resets := new(MockPasswordResetRepository)
+		pub := new(MockEventPublisher)
+		repo.On("GetByEmail", mock.Anything, "test@example.com").Return(existing, nil)
+		resets.On("Create", mock.Anything, mock.Anything).Return(nil)
+		pub.On("Publish", mock.Anything, user.EventTypePasswordResetRequested, mock.Anything).Return(errors.New("broker down"))
+
+		uc := NewRequestPasswordResetUseCase(repo, resets, pub, time.Hour, logger.New("test"))
+		assert.Error(t, uc.Execute(context.Background(), RequestPasswordResetInput{Email: "test@example.com"}))
+	})
+}
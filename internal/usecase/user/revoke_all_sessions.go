@@ -0,0 +1,47 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// RevokeAllSessionsInput represents input for revoking every session of a user
+type RevokeAllSessionsInput struct {
+	UserID string
+}
+
+// RevokeAllSessionsUseCase handles signing a user out everywhere
+type RevokeAllSessionsUseCase struct {
+	sessions user.SessionRepository
+	logger   *logger.Logger
+}
+
+// NewRevokeAllSessionsUseCase creates a new use case instance
+func NewRevokeAllSessionsUseCase(sessions user.SessionRepository, logger *logger.Logger) *RevokeAllSessionsUseCase {
+	return &RevokeAllSessionsUseCase{
+		sessions: sessions,
+		logger:   logger,
+	}
+}
+
+// Execute revokes every active session of the user and returns how many were revoked
+func (uc *RevokeAllSessionsUseCase) Execute(ctx context.Context, input RevokeAllSessionsInput) (int64, error) {
+	uc.logger.WithField("user_id", input.UserID).Info("Revoking all sessions")
+
+	revoked, err := uc.sessions.RevokeAll(ctx, input.UserID, time.Now())
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to revoke sessions")
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	uc.logger.WithFields(map[string]any{
+		"user_id": input.UserID,
+		"revoked": revoked,
+	}).Info("Sessions revoked")
+
+	return revoked, nil
+}
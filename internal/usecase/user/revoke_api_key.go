@@ -0,0 +1,77 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// RevokeAPIKeyInput represents input for revoking an API key
+type RevokeAPIKeyInput struct {
+	UserID   string
+	APIKeyID string
+}
+
+// RevokeAPIKeyUseCase handles revoking API keys
+type RevokeAPIKeyUseCase struct {
+	keys     user.APIKeyRepository
+	eventPub EventPublisher
+	logger   *logger.Logger
+}
+
+// NewRevokeAPIKeyUseCase creates a new use case instance
+func NewRevokeAPIKeyUseCase(keys user.APIKeyRepository, eventPub EventPublisher, logger *logger.Logger) *RevokeAPIKeyUseCase {
+	return &RevokeAPIKeyUseCase{
+		keys:     keys,
+		eventPub: eventPub,
+		logger:   logger,
+	}
+}
+
+// Execute revokes a key of the user. Keys of other users and keys that are
+// already revoked return user.ErrAPIKeyNotFound.
+func (uc *RevokeAPIKeyUseCase) Execute(ctx context.Context, input RevokeAPIKeyInput) error {
+	uc.logger.WithFields(map[string]any{
+		"user_id":    input.UserID,
+		"api_key_id": input.APIKeyID,
+	}).Info("Revoking API key")
+
+	// 1. Load key
+	key, err := uc.keys.GetByID(ctx, input.APIKeyID)
+	if err != nil {
+		if errors.Is(err, user.ErrAPIKeyNotFound) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to get API key from database")
+		return fmt.Errorf("failed to get api key: %w", err)
+	}
+	if key.UserID != input.UserID {
+		return user.ErrAPIKeyNotFound
+	}
+
+	// 2. Revoke
+	if err := uc.keys.Revoke(ctx, key, time.Now()); err != nil {
+		if errors.Is(err, user.ErrAPIKeyNotFound) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to revoke API key")
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	// 3. Publish domain event
+	event := user.APIKeyRevokedEvent{
+		UserID:    key.UserID,
+		APIKeyID:  key.ID,
+		RevokedAt: *key.RevokedAt,
+	}
+	if err := uc.eventPub.Publish(ctx, user.EventTypeAPIKeyRevoked, event); err != nil {
+		// Don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to publish API key revoked event")
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeAPIKeyUseCase_Execute(t *testing.T) {
+	t.Run("revokes key of the user", func(t *testing.T) {
+		keys := new(MockAPIKeyRepository)
+		pub := new(MockEventPublisher)
+		key := &user.APIKey{ID: "key-1", UserID: "user-1"}
+		keys.On("GetByID", mock.Anything, "key-1").Return(key, nil)
+		keys.On("Revoke", mock.Anything, key, mock.Anything).Return(nil)
+		pub.On("Publish", mock.Anything, user.EventTypeAPIKeyRevoked, mock.Anything).Return(nil)
+
+		uc := NewRevokeAPIKeyUseCase(keys, pub, logger.New("test"))
+		require.NoError(t, uc.Execute(context.Background(), RevokeAPIKeyInput{UserID: "user-1", APIKeyID: "key-1"}))
+
+		assert.NotNil(t, key.RevokedAt)
+		pub.AssertExpectations(t)
+	})
+
+	t.Run("key of another user", func(t *testing.T) {
+		keys := new(MockAPIKeyRepository)
+		keys.On("GetByID", mock.Anything, "key-1").Return(&user.APIKey{ID: "key-1", UserID: "user-2"}, nil)
+
+		uc := NewRevokeAPIKeyUseCase(keys, new(MockEventPublisher), logger.New("test"))
+		err := uc.Execute(context.Background(), RevokeAPIKeyInput{UserID: "user-1", APIKeyID: "key-1"})
+		assert.ErrorIs(t, err, user.ErrAPIKeyNotFound)
+		keys.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
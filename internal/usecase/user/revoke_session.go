@@ -0,0 +1,49 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// RevokeSessionInput represents input for revoking a session
+type RevokeSessionInput struct {
+	UserID    string
+	SessionID string
+}
+
+// RevokeSessionUseCase handles signing out a single session
+type RevokeSessionUseCase struct {
+	sessions user.SessionRepository
+	logger   *logger.Logger
+}
+
+// NewRevokeSessionUseCase creates a new use case instance
+func NewRevokeSessionUseCase(sessions user.SessionRepository, logger *logger.Logger) *RevokeSessionUseCase {
+	return &RevokeSessionUseCase{
+		sessions: sessions,
+		logger:   logger,
+	}
+}
+
+// Execute revokes a single active session of the user
+func (uc *RevokeSessionUseCase) Execute(ctx context.Context, input RevokeSessionInput) error {
+	uc.logger.WithFields(map[string]any{
+		"user_id":    input.UserID,
+		"session_id": input.SessionID,
+	}).Info("Revoking session")
+
+	if err := uc.sessions.Revoke(ctx, input.UserID, input.SessionID, time.Now()); err != nil {
+		if errors.Is(err, user.ErrSessionNotFound) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to revoke session")
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
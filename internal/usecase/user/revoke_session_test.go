@@ -0,0 +1,50 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeSessionUseCase_Execute(t *testing.T) {
+	log := logger.New("test")
+
+	t.Run("revokes session", func(t *testing.T) {
+		sessions := new(MockSessionRepository)
+		sessions.On("Revoke", mock.Anything, "user-1", "session-1", mock.Anything).Return(nil)
+
+		uc := NewRevokeSessionUseCase(sessions, log)
+		err := uc.Execute(context.Background(), RevokeSessionInput{UserID: "user-1", SessionID: "session-1"})
+
+		assert.NoError(t, err)
+		sessions.AssertExpectations(t)
+	})
+
+	t.Run("session of another user is not found", func(t *testing.T) {
+		sessions := new(MockSessionRepository)
+		sessions.On("Revoke", mock.Anything, "user-2", "session-1", mock.Anything).Return(user.ErrSessionNotFound)
+
+		uc := NewRevokeSessionUseCase(sessions, log)
+		err := uc.Execute(context.Background(), RevokeSessionInput{UserID: "user-2", SessionID: "session-1"})
+
+		assert.ErrorIs(t, err, user.ErrSessionNotFound)
+		sessions.AssertExpectations(t)
+	})
+}
+
+func TestRevokeAllSessionsUseCase_Execute(t *testing.T) {
+	sessions := new(MockSessionRepository)
+	sessions.On("RevokeAll", mock.Anything, "user-1", mock.Anything).Return(int64(3), nil)
+
+	uc := NewRevokeAllSessionsUseCase(sessions, logger.New("test"))
+	revoked, err := uc.Execute(context.Background(), RevokeAllSessionsInput{UserID: "user-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), revoked)
+	sessions.AssertExpectations(t)
+}
@@ -0,0 +1,89 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/memclutter/go-microservices-template/pkg/totp"
+)
+
+// StartTOTPEnrollmentInput represents the user enrolling a TOTP second factor
+type StartTOTPEnrollmentInput struct {
+	UserID string
+}
+
+// StartTOTPEnrollmentOutput represents the secret to add to an authenticator app
+type StartTOTPEnrollmentOutput struct {
+	Secret string
+	// URI is the otpauth:// form of the secret, usually shown as a QR code
+	URI string
+}
+
+// StartTOTPEnrollmentUseCase handles generating TOTP secrets
+type StartTOTPEnrollmentUseCase struct {
+	repo      user.Repository
+	twoFactor user.TwoFactorRepository
+	issuer    string
+	logger    *logger.Logger
+}
+
+// NewStartTOTPEnrollmentUseCase creates a new use case instance. issuer names
+// the service in authenticator apps.
+func NewStartTOTPEnrollmentUseCase(
+	repo user.Repository,
+	twoFactor user.TwoFactorRepository,
+	issuer string,
+	logger *logger.Logger,
+) *StartTOTPEnrollmentUseCase {
+	return &StartTOTPEnrollmentUseCase{
+		repo:      repo,
+		twoFactor: twoFactor,
+		issuer:    issuer,
+		logger:    logger,
+	}
+}
+
+// Execute generates a new TOTP secret for the user, replacing one of an
+// unconfirmed enrollment. Two-factor authentication is enabled only once
+// ConfirmTOTPEnrollment accepts a code of the secret.
+func (uc *StartTOTPEnrollmentUseCase) Execute(ctx context.Context, input StartTOTPEnrollmentInput) (*StartTOTPEnrollmentOutput, error) {
+	uc.logger.WithField("user_id", input.UserID).Info("Starting TOTP enrollment")
+
+	// 1. Load existing user
+	u, err := uc.repo.GetByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if u.IsTwoFactorEnabled() {
+		return nil, user.ErrTwoFactorAlreadyEnabled
+	}
+
+	// 2. Generate secret
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Save encrypted secret
+	if err := uc.twoFactor.SaveTOTP(ctx, user.NewTOTP(u.ID, secret)); err != nil {
+		if errors.Is(err, user.ErrTwoFactorAlreadyEnabled) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to save TOTP secret")
+		return nil, fmt.Errorf("failed to save totp secret: %w", err)
+	}
+
+	uc.logger.WithField("user_id", u.ID).Info("TOTP enrollment started")
+
+	return &StartTOTPEnrollmentOutput{
+		Secret: secret,
+		URI:    totp.URI(uc.issuer, u.Email, secret),
+	}, nil
+}
@@ -0,0 +1,48 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartTOTPEnrollmentUseCase_Execute(t *testing.T) {
+	existing := &user.User{ID: "user-1", Email: "test@example.com"}
+
+	repo := new(MockRepository)
+	repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil)
+	twoFactor := new(MockTwoFactorRepository)
+	twoFactor.On("SaveTOTP", mock.Anything, mock.MatchedBy(func(t *user.TOTP) bool {
+		return t.UserID == "user-1" && t.Secret != "" && !t.IsEnabled()
+	})).Return(nil)
+
+	uc := NewStartTOTPEnrollmentUseCase(repo, twoFactor, "Example", logger.New("test"))
+	result, err := uc.Execute(context.Background(), StartTOTPEnrollmentInput{UserID: "user-1"})
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, result.Secret)
+	assert.True(t, strings.HasPrefix(result.URI, "otpauth://totp/Example:test@example.com?"))
+	assert.Contains(t, result.URI, "secret="+result.Secret)
+	twoFactor.AssertExpectations(t)
+}
+
+func TestStartTOTPEnrollmentUseCase_AlreadyEnabled(t *testing.T) {
+	enabledAt := time.Now()
+	existing := &user.User{ID: "user-1", Email: "test@example.com", TwoFactorEnabledAt: &enabledAt}
+
+	repo := new(MockRepository)
+	repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil)
+	twoFactor := new(MockTwoFactorRepository)
+
+	uc := NewStartTOTPEnrollmentUseCase(repo, twoFactor, "Example", logger.New("test"))
+	_, err := uc.Execute(context.Background(), StartTOTPEnrollmentInput{UserID: "user-1"})
+	assert.ErrorIs(t, err, user.ErrTwoFactorAlreadyEnabled)
+	twoFactor.AssertNotCalled(t, "SaveTOTP", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,47 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/totp"
+)
+
+// recoveryCodeCount is the number of recovery codes issued when two-factor
+// authentication is enabled
+const recoveryCodeCount = 10
+
+// validateTOTPCode returns the time step of a current code of the secret,
+// or user.ErrInvalidTwoFactorCode
+func validateTOTPCode(userTOTP *user.TOTP, code string) (int64, error) {
+	step, err := totp.Validate(userTOTP.Secret, code, time.Now())
+	if err != nil {
+		if errors.Is(err, totp.ErrInvalidCode) {
+			return 0, user.ErrInvalidTwoFactorCode
+		}
+		return 0, fmt.Errorf("failed to validate totp code: %w", err)
+	}
+	return step, nil
+}
+
+// verifySecondFactor accepts either a TOTP code that was not used before or an
+// unused recovery code, returning user.ErrInvalidTwoFactorCode otherwise.
+// Either is used up.
+func verifySecondFactor(ctx context.Context, twoFactor user.TwoFactorRepository, userTOTP *user.TOTP, code, recoveryCode string) error {
+	if recoveryCode != "" {
+		return twoFactor.UseRecoveryCode(ctx, userTOTP.UserID, auth.HashRecoveryCode(recoveryCode), time.Now())
+	}
+
+	step, err := validateTOTPCode(userTOTP, code)
+	if err != nil {
+		return err
+	}
+	if err := userTOTP.UseStep(step); err != nil {
+		return err
+	}
+	return twoFactor.UseTOTPStep(ctx, userTOTP)
+}
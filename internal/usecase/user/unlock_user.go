@@ -0,0 +1,61 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// UnlockUserInput represents the user whose login lockout to lift
+type UnlockUserInput struct {
+	UserID string
+}
+
+// UnlockUserUseCase handles lifting login lockouts
+type UnlockUserUseCase struct {
+	repo     user.Repository
+	throttle *LoginThrottle
+	logger   *logger.Logger
+}
+
+// NewUnlockUserUseCase creates a new use case instance
+func NewUnlockUserUseCase(
+	repo user.Repository,
+	throttle *LoginThrottle,
+	logger *logger.Logger,
+) *UnlockUserUseCase {
+	return &UnlockUserUseCase{
+		repo:     repo,
+		throttle: throttle,
+		logger:   logger,
+	}
+}
+
+// Execute forgets the failed logins of the user's email, lifting any delay or
+// lockout. Lockouts of client IP addresses are left to expire.
+func (uc *UnlockUserUseCase) Execute(ctx context.Context, input UnlockUserInput) error {
+	uc.logger.WithField("user_id", input.UserID).Info("Unlocking user")
+
+	// 1. Load existing user
+	u, err := uc.repo.GetByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 2. Reset failed logins
+	if err := uc.throttle.Unlock(ctx, u.Email); err != nil {
+		uc.logger.WithError(err).Error("Failed to reset login failures")
+		return err
+	}
+
+	uc.logger.WithField("user_id", u.ID).Info("User unlocked successfully")
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUnlockUserUseCase_Execute(t *testing.T) {
+	t.Run("resets failures of the user's email", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetByID", mock.Anything, "user-1").Return(&user.User{ID: "user-1", Email: "test@example.com"}, nil)
+		failures := new(MockLoginFailureRepository)
+		failures.On("Reset", mock.Anything, user.LoginSubjectEmail, "test@example.com").Return(nil)
+		throttle := NewLoginThrottle(failures, user.LoginThrottlePolicy{}, user.LoginThrottlePolicy{}, new(MockEventPublisher), logger.New("test"))
+
+		uc := NewUnlockUserUseCase(repo, throttle, logger.New("test"))
+		assert.NoError(t, uc.Execute(context.Background(), UnlockUserInput{UserID: "user-1"}))
+		failures.AssertExpectations(t)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetByID", mock.Anything, "missing").Return(nil, user.ErrUserNotFound)
+
+		uc := NewUnlockUserUseCase(repo, newPermissiveLoginThrottle(), logger.New("test"))
+		assert.ErrorIs(t, uc.Execute(context.Background(), UnlockUserInput{UserID: "missing"}), user.ErrUserNotFound)
+	})
+}
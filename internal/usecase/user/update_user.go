@@ -0,0 +1,130 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// UpdateUserInput represents input data for updating a user
+type UpdateUserInput struct {
+	UserID string
+	Name   string
+	// UpdateMask lists the fields to change. When empty only Name is
+	// changed, as before update masks were supported.
+	UpdateMask []string
+	// Version is the version of the user the change is based on. Unless it
+	// is zero, the update fails with user.ErrVersionMismatch when the user
+	// was changed since.
+	Version int64
+}
+
+// UpdateUserOutput represents the result of user update
+type UpdateUserOutput struct {
+	ID    string
+	Email string
+	Name  string
+	Roles []string
+
+	EmailVerified bool
+	PendingEmail  string
+	Version       int64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// UpdateUserUseCase handles user profile update business flow
+type UpdateUserUseCase struct {
+	repo     user.Repository
+	eventPub EventPublisher
+	logger   *logger.Logger
+}
+
+// NewUpdateUserUseCase creates a new use case instance
+func NewUpdateUserUseCase(
+	repo user.Repository,
+	eventPub EventPublisher,
+	logger *logger.Logger,
+) *UpdateUserUseCase {
+	return &UpdateUserUseCase{
+		repo:     repo,
+		eventPub: eventPub,
+		logger:   logger,
+	}
+}
+
+// Execute updates an existing user
+func (uc *UpdateUserUseCase) Execute(ctx context.Context, input UpdateUserInput) (*UpdateUserOutput, error) {
+	uc.logger.WithFields(map[string]any{
+		"user_id":     input.UserID,
+		"name":        input.Name,
+		"update_mask": input.UpdateMask,
+	}).Info("Updating user")
+
+	// 1. Load existing user
+	u, err := uc.repo.GetByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if input.Version != 0 && input.Version != u.Version {
+		return nil, user.ErrVersionMismatch
+	}
+
+	// 2. Apply domain change (with validation)
+	paths := input.UpdateMask
+	if len(paths) == 0 {
+		paths = []string{user.ProfilePathName}
+	}
+	update := user.ProfileUpdate{
+		Paths: paths,
+		Name:  input.Name,
+	}
+	if err := u.ApplyProfileUpdate(update); err != nil {
+		return nil, fmt.Errorf("invalid user data: %w", err)
+	}
+
+	// 3. Save to repository
+	if err := uc.repo.Update(ctx, u); err != nil {
+		if errors.Is(err, user.ErrUserNotFound) || errors.Is(err, user.ErrVersionMismatch) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to update user in database")
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// 4. Publish domain event
+	event := user.UserUpdatedEvent{
+		UserID:    u.ID,
+		Name:      u.Name,
+		UpdatedAt: u.UpdatedAt,
+	}
+	if err := uc.eventPub.Publish(ctx, user.EventTypeUserUpdated, event); err != nil {
+		// Don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to publish user updated event")
+	}
+
+	uc.logger.WithField("user_id", u.ID).Info("User updated successfully")
+
+	return &UpdateUserOutput{
+		ID:    u.ID,
+		Email: u.Email,
+		Name:  u.Name,
+		Roles: u.RoleNames(),
+
+		EmailVerified: u.IsEmailVerified(),
+		PendingEmail:  u.PendingEmail,
+		Version:       u.Version,
+
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,139 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// UpdateUserRolesInput represents roles to grant to and revoke from a user
+type UpdateUserRolesInput struct {
+	UserID string
+	Grant  []string
+	Revoke []string
+}
+
+// UpdateUserRolesUseCase handles granting and revoking roles
+type UpdateUserRolesUseCase struct {
+	repo     user.Repository
+	eventPub EventPublisher
+	logger   *logger.Logger
+}
+
+// NewUpdateUserRolesUseCase creates a new use case instance
+func NewUpdateUserRolesUseCase(
+	repo user.Repository,
+	eventPub EventPublisher,
+	logger *logger.Logger,
+) *UpdateUserRolesUseCase {
+	return &UpdateUserRolesUseCase{
+		repo:     repo,
+		eventPub: eventPub,
+		logger:   logger,
+	}
+}
+
+// Execute grants and revokes roles. Granting a held role or revoking a
+// missing one is a no-op.
+func (uc *UpdateUserRolesUseCase) Execute(ctx context.Context, input UpdateUserRolesInput) (*GetUserOutput, error) {
+	uc.logger.WithFields(map[string]any{
+		"user_id": input.UserID,
+		"grant":   input.Grant,
+		"revoke":  input.Revoke,
+	}).Info("Updating user roles")
+
+	// 1. Validate roles
+	grant, err := parseRoles(input.Grant)
+	if err != nil {
+		return nil, err
+	}
+	revoke, err := parseRoles(input.Revoke)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Load existing user
+	u, err := uc.repo.GetByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 3. Apply domain change
+	var granted, revoked []string
+	for _, r := range grant {
+		if u.GrantRole(r) {
+			granted = append(granted, string(r))
+		}
+	}
+	for _, r := range revoke {
+		changed, err := u.RevokeRole(r)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			revoked = append(revoked, string(r))
+		}
+	}
+
+	if len(granted) > 0 || len(revoked) > 0 {
+		u.UpdatedAt = time.Now()
+
+		// 4. Save to repository
+		if err := uc.repo.UpdateRoles(ctx, u); err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				return nil, err
+			}
+			uc.logger.WithError(err).Error("Failed to update user roles in database")
+			return nil, fmt.Errorf("failed to update user roles: %w", err)
+		}
+
+		// 5. Publish domain event
+		event := user.UserRolesChangedEvent{
+			UserID:    u.ID,
+			Roles:     u.RoleNames(),
+			Granted:   granted,
+			Revoked:   revoked,
+			UpdatedAt: u.UpdatedAt,
+		}
+		if err := uc.eventPub.Publish(ctx, user.EventTypeUserRolesChanged, event); err != nil {
+			// Don't fail the use case, just log the error
+			uc.logger.WithError(err).Warn("Failed to publish user roles changed event")
+		}
+	}
+
+	uc.logger.WithField("user_id", u.ID).Info("User roles updated successfully")
+
+	return &GetUserOutput{
+		ID:    u.ID,
+		Email: u.Email,
+		Name:  u.Name,
+		Roles: u.RoleNames(),
+
+		EmailVerified: u.IsEmailVerified(),
+		PendingEmail:  u.PendingEmail,
+		Version:       u.Version,
+
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}, nil
+}
+
+func parseRoles(names []string) ([]user.Role, error) {
+	roles := make([]user.Role, len(names))
+	for i, name := range names {
+		r, err := user.ParseRole(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", err, name)
+		}
+		roles[i] = r
+	}
+	return roles, nil
+}
@@ -0,0 +1,76 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateUserRolesUseCase_Execute(t *testing.T) {
+	log := logger.New("test")
+
+	t.Run("grants and revokes roles", func(t *testing.T) {
+		existing := &user.User{ID: "user-1", Roles: []user.Role{user.RoleUser, user.RoleSupport}}
+		repo := new(MockRepository)
+		pub := new(MockEventPublisher)
+		repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil)
+		repo.On("UpdateRoles", mock.Anything, existing).Return(nil)
+		pub.On("Publish", mock.Anything, user.EventTypeUserRolesChanged, mock.Anything).Return(nil)
+
+		uc := NewUpdateUserRolesUseCase(repo, pub, log)
+		result, err := uc.Execute(context.Background(), UpdateUserRolesInput{
+			UserID: "user-1",
+			Grant:  []string{"admin"},
+			Revoke: []string{"support"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user", "admin"}, result.Roles)
+		repo.AssertExpectations(t)
+		pub.AssertExpectations(t)
+	})
+
+	t.Run("no change skips save", func(t *testing.T) {
+		existing := &user.User{ID: "user-1", Roles: []user.Role{user.RoleUser}}
+		repo := new(MockRepository)
+		repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil)
+
+		uc := NewUpdateUserRolesUseCase(repo, new(MockEventPublisher), log)
+		result, err := uc.Execute(context.Background(), UpdateUserRolesInput{
+			UserID: "user-1",
+			Grant:  []string{"user"},
+			Revoke: []string{"admin"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user"}, result.Roles)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("unknown role is rejected", func(t *testing.T) {
+		repo := new(MockRepository)
+
+		uc := NewUpdateUserRolesUseCase(repo, new(MockEventPublisher), log)
+		_, err := uc.Execute(context.Background(), UpdateUserRolesInput{UserID: "user-1", Grant: []string{"root"}})
+
+		assert.ErrorIs(t, err, user.ErrInvalidRole)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("base role cannot be revoked", func(t *testing.T) {
+		existing := &user.User{ID: "user-1", Roles: []user.Role{user.RoleUser}}
+		repo := new(MockRepository)
+		repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil)
+
+		uc := NewUpdateUserRolesUseCase(repo, new(MockEventPublisher), log)
+		_, err := uc.Execute(context.Background(), UpdateUserRolesInput{UserID: "user-1", Revoke: []string{"user"}})
+
+		assert.ErrorIs(t, err, user.ErrInvalidRole)
+		repo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,170 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateUserUseCase_Execute(t *testing.T) {
+	existing := func() *user.User {
+		return &user.User{
+			ID:        "user-1",
+			Email:     "test@example.com",
+			Name:      "Old Name",
+			CreatedAt: time.Now().Add(-time.Hour),
+			UpdatedAt: time.Now().Add(-time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		input   UpdateUserInput
+		setup   func(*MockRepository, *MockEventPublisher)
+		wantErr error
+	}{
+		{
+			name: "successful user update",
+			input: UpdateUserInput{
+				UserID: "user-1",
+				Name:   "New Name",
+			},
+			setup: func(repo *MockRepository, pub *MockEventPublisher) {
+				repo.On("GetByID", mock.Anything, "user-1").Return(existing(), nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+					return u.Name == "New Name"
+				})).Return(nil)
+				pub.On("Publish", mock.Anything, user.EventTypeUserUpdated, mock.AnythingOfType("user.UserUpdatedEvent")).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "user not found",
+			input: UpdateUserInput{
+				UserID: "missing",
+				Name:   "New Name",
+			},
+			setup: func(repo *MockRepository, pub *MockEventPublisher) {
+				repo.On("GetByID", mock.Anything, "missing").Return(nil, user.ErrUserNotFound)
+			},
+			wantErr: user.ErrUserNotFound,
+		},
+		{
+			name: "invalid name",
+			input: UpdateUserInput{
+				UserID: "user-1",
+				Name:   "",
+			},
+			setup: func(repo *MockRepository, pub *MockEventPublisher) {
+				repo.On("GetByID", mock.Anything, "user-1").Return(existing(), nil)
+			},
+			wantErr: user.ErrInvalidName,
+		},
+		{
+			name: "masked name",
+			input: UpdateUserInput{
+				UserID:     "user-1",
+				Name:       "New Name",
+				UpdateMask: []string{user.ProfilePathName},
+			},
+			setup: func(repo *MockRepository, pub *MockEventPublisher) {
+				repo.On("GetByID", mock.Anything, "user-1").Return(existing(), nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+					return u.Name == "New Name"
+				})).Return(nil)
+				pub.On("Publish", mock.Anything, user.EventTypeUserUpdated, mock.AnythingOfType("user.UserUpdatedEvent")).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "unknown mask path",
+			input: UpdateUserInput{
+				UserID:     "user-1",
+				Name:       "New Name",
+				UpdateMask: []string{"email"},
+			},
+			setup: func(repo *MockRepository, pub *MockEventPublisher) {
+				repo.On("GetByID", mock.Anything, "user-1").Return(existing(), nil)
+			},
+			wantErr: user.ErrInvalidUpdateMask,
+		},
+		{
+			name: "stale version",
+			input: UpdateUserInput{
+				UserID:  "user-1",
+				Name:    "New Name",
+				Version: 1,
+			},
+			setup: func(repo *MockRepository, pub *MockEventPublisher) {
+				u := existing()
+				u.Version = 2
+				repo.On("GetByID", mock.Anything, "user-1").Return(u, nil)
+			},
+			wantErr: user.ErrVersionMismatch,
+		},
+		{
+			name: "changed while updating",
+			input: UpdateUserInput{
+				UserID: "user-1",
+				Name:   "New Name",
+			},
+			setup: func(repo *MockRepository, pub *MockEventPublisher) {
+				repo.On("GetByID", mock.Anything, "user-1").Return(existing(), nil)
+				repo.On("Update", mock.Anything, mock.AnythingOfType("*user.User")).Return(user.ErrVersionMismatch)
+			},
+			wantErr: user.ErrVersionMismatch,
+		},
+		{
+			name: "event publish failure does not fail update",
+			input: UpdateUserInput{
+				UserID: "user-1",
+				Name:   "New Name",
+			},
+			setup: func(repo *MockRepository, pub *MockEventPublisher) {
+				repo.On("GetByID", mock.Anything, "user-1").Return(existing(), nil)
+				repo.On("Update", mock.Anything, mock.AnythingOfType("*user.User")).Return(nil)
+				pub.On("Publish", mock.Anything, user.EventTypeUserUpdated, mock.Anything).Return(errors.New("broker down"))
+			},
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup mocks
+			repo := new(MockRepository)
+			eventPub := new(MockEventPublisher)
+			log := logger.New("test")
+
+			tt.setup(repo, eventPub)
+
+			// Create use case
+			uc := NewUpdateUserUseCase(repo, eventPub, log)
+
+			// Execute
+			result, err := uc.Execute(context.Background(), tt.input)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, tt.input.UserID, result.ID)
+				assert.Equal(t, tt.input.Name, result.Name)
+			}
+
+			// Verify mocks
+			repo.AssertExpectations(t)
+			eventPub.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// VerifyEmailInput represents an email verification token
+type VerifyEmailInput struct {
+	Token string
+}
+
+// VerifyEmailUseCase handles confirming that a user owns their email address
+type VerifyEmailUseCase struct {
+	repo          user.Repository
+	verifications user.EmailVerificationRepository
+	eventPub      EventPublisher
+	logger        *logger.Logger
+}
+
+// NewVerifyEmailUseCase creates a new use case instance
+func NewVerifyEmailUseCase(
+	repo user.Repository,
+	verifications user.EmailVerificationRepository,
+	eventPub EventPublisher,
+	logger *logger.Logger,
+) *VerifyEmailUseCase {
+	return &VerifyEmailUseCase{
+		repo:          repo,
+		verifications: verifications,
+		eventPub:      eventPub,
+		logger:        logger,
+	}
+}
+
+// Execute consumes the verification token and marks the user's email verified.
+// A token sent to the pending address completes an email change instead.
+// Unknown, used and expired tokens, as well as tokens sent to an address the
+// user neither has nor is switching to, return user.ErrInvalidVerificationToken.
+func (uc *VerifyEmailUseCase) Execute(ctx context.Context, input VerifyEmailInput) error {
+	uc.logger.Info("Verifying email")
+
+	// 1. Look up verification token
+	token, err := uc.verifications.GetByHash(ctx, auth.HashSecretToken(input.Token))
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidVerificationToken) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to get email verification token from database")
+		return fmt.Errorf("failed to get email verification token: %w", err)
+	}
+	now := time.Now()
+	if !token.IsUsable(now) {
+		return user.ErrInvalidVerificationToken
+	}
+
+	// 2. Load user and check the token was sent to their current or pending address
+	u, err := uc.repo.GetByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return user.ErrInvalidVerificationToken
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	emailChange := u.PendingEmail != "" && u.PendingEmail == token.Email
+	if !emailChange && u.Email != token.Email {
+		return user.ErrInvalidVerificationToken
+	}
+
+	// 3. Consume token
+	if err := uc.verifications.Consume(ctx, token, now); err != nil {
+		if errors.Is(err, user.ErrInvalidVerificationToken) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to consume email verification token")
+		return fmt.Errorf("failed to consume email verification token: %w", err)
+	}
+
+	// 4. Apply domain change and save to repository
+	oldEmail := u.Email
+	if emailChange {
+		u.ConfirmEmailChange()
+	} else {
+		u.VerifyEmail()
+	}
+	if err := uc.repo.UpdateEmail(ctx, u); err != nil {
+		// The new address may have been registered while the change was pending
+		if errors.Is(err, user.ErrUserAlreadyExists) {
+			return err
+		}
+		uc.logger.WithError(err).Error("Failed to update user email in database")
+		return fmt.Errorf("failed to update email: %w", err)
+	}
+
+	// 5. Publish domain event
+	if emailChange {
+		event := user.UserEmailChangedEvent{
+			UserID:    u.ID,
+			OldEmail:  oldEmail,
+			NewEmail:  u.Email,
+			ChangedAt: u.UpdatedAt,
+		}
+		if err := uc.eventPub.Publish(ctx, user.EventTypeUserEmailChanged, event); err != nil {
+			// Don't fail the use case, just log the error
+			uc.logger.WithError(err).Warn("Failed to publish user email changed event")
+		}
+
+		uc.logger.WithField("user_id", u.ID).Info("Email changed successfully")
+		return nil
+	}
+
+	event := user.UserEmailVerifiedEvent{
+		UserID:     u.ID,
+		Email:      u.Email,
+		VerifiedAt: *u.EmailVerifiedAt,
+	}
+	if err := uc.eventPub.Publish(ctx, user.EventTypeUserEmailVerified, event); err != nil {
+		// Don't fail the use case, just log the error
+		uc.logger.WithError(err).Warn("Failed to publish user email verified event")
+	}
+
+	uc.logger.WithField("user_id", u.ID).Info("Email verified successfully")
+
+	return nil
+}
+
+// requestEmailVerification issues a verification token for email and
+// publishes it for delivery to that address
+func requestEmailVerification(
+	ctx context.Context,
+	verifications user.EmailVerificationRepository,
+	eventPub EventPublisher,
+	u *user.User,
+	email string,
+	ttl time.Duration,
+) error {
+	token, tokenHash, err := auth.NewSecretToken()
+	if err != nil {
+		return err
+	}
+
+	verification := user.NewEmailVerificationToken(tokenHash, u.ID, email, ttl)
+	if err := verifications.Create(ctx, verification); err != nil {
+		return fmt.Errorf("failed to create email verification token: %w", err)
+	}
+
+	event := user.VerificationRequestedEvent{
+		UserID:    u.ID,
+		Email:     email,
+		Name:      u.Name,
+		Token:     token,
+		ExpiresAt: verification.ExpiresAt,
+	}
+	if err := eventPub.Publish(ctx, user.EventTypeVerificationRequested, event); err != nil {
+		return fmt.Errorf("failed to publish verification request: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockEmailVerificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationRepository) Create(ctx context.Context, t *user.EmailVerificationToken) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationRepository) GetByHash(ctx context.Context, tokenHash string) (*user.EmailVerificationToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.EmailVerificationToken), args.Error(1)
+}
+
+func (m *MockEmailVerificationRepository) Consume(ctx context.Context, t *user.EmailVerificationToken, at time.Time) error {
+	args := m.Called(ctx, t, at)
+	return args.Error(0)
+}
+
+func TestVerifyEmailUseCase_Execute(t *testing.T) {
+	const token = "verification-token"
+	tokenHash := auth.HashSecretToken(token)
+
+	tests := []struct {
+		name         string
+		verification *user.EmailVerificationToken
+		setup        func(*MockRepository, *MockEmailVerificationRepository, *MockEventPublisher)
+		wantErr      error
+	}{
+		{
+			name:         "successful verification",
+			verification: user.NewEmailVerificationToken(tokenHash, "user-1", "test@example.com", time.Hour),
+			setup: func(repo *MockRepository, verifications *MockEmailVerificationRepository, pub *MockEventPublisher) {
+				verifications.On("Consume", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				repo.On("UpdateEmail", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+					return u.IsEmailVerified()
+				})).Return(nil)
+				pub.On("Publish", mock.Anything, user.EventTypeUserEmailVerified, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:         "expired token",
+			verification: user.NewEmailVerificationToken(tokenHash, "user-1", "test@example.com", -time.Minute),
+			wantErr:      user.ErrInvalidVerificationToken,
+		},
+		{
+			name:         "token sent to a previous address",
+			verification: user.NewEmailVerificationToken(tokenHash, "user-1", "old@example.com", time.Hour),
+			wantErr:      user.ErrInvalidVerificationToken,
+		},
+		{
+			name:         "token consumed concurrently",
+			verification: user.NewEmailVerificationToken(tokenHash, "user-1", "test@example.com", time.Hour),
+			setup: func(repo *MockRepository, verifications *MockEmailVerificationRepository, pub *MockEventPublisher) {
+				verifications.On("Consume", mock.Anything, mock.Anything, mock.Anything).Return(user.ErrInvalidVerificationToken)
+			},
+			wantErr: user.ErrInvalidVerificationToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing, err := user.NewUser("test@example.com", "Test User", "password123", testPolicy, testHasher)
+			require.NoError(t, err)
+			existing.ID = "user-1"
+
+			repo := new(MockRepository)
+			verifications := new(MockEmailVerificationRepository)
+			pub := new(MockEventPublisher)
+			verifications.On("GetByHash", mock.Anything, tokenHash).Return(tt.verification, nil)
+			repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil).Maybe()
+			if tt.setup != nil {
+				tt.setup(repo, verifications, pub)
+			}
+
+			uc := NewVerifyEmailUseCase(repo, verifications, pub, logger.New("test"))
+			err = uc.Execute(context.Background(), VerifyEmailInput{Token: token})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, existing.IsEmailVerified())
+			} else {
+				require.NoError(t, err)
+				assert.True(t, existing.IsEmailVerified())
+			}
+
+			repo.AssertExpectations(t)
+			verifications.AssertExpectations(t)
+			pub.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVerifyEmailUseCase_ConfirmsPendingEmail(t *testing.T) {
+	const token = "verification-token"
+	tokenHash := auth.HashSecretToken(token)
+
+	existing, err := user.NewUser("old@example.com", "Test User", "password123", testPolicy, testHasher)
+	require.NoError(t, err)
+	existing.ID = "user-1"
+	require.NoError(t, existing.RequestEmailChange("new@example.com"))
+
+	repo := new(MockRepository)
+	verifications := new(MockEmailVerificationRepository)
+	pub := new(MockEventPublisher)
+	verifications.On("GetByHash", mock.Anything, tokenHash).
+		Return(user.NewEmailVerificationToken(tokenHash, "user-1", "new@example.com", time.Hour), nil)
+	verifications.On("Consume", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil)
+	repo.On("UpdateEmail", mock.Anything, existing).Return(nil)
+	pub.On("Publish", mock.Anything, user.EventTypeUserEmailChanged, mock.MatchedBy(func(e user.UserEmailChangedEvent) bool {
+		return e.OldEmail == "old@example.com" && e.NewEmail == "new@example.com"
+	})).Return(nil)
+
+	uc := NewVerifyEmailUseCase(repo, verifications, pub, logger.New("test"))
+	require.NoError(t, uc.Execute(context.Background(), VerifyEmailInput{Token: token}))
+
+	assert.Equal(t, "new@example.com", existing.Email)
+	assert.Empty(t, existing.PendingEmail)
+	assert.True(t, existing.IsEmailVerified())
+	pub.AssertExpectations(t)
+}
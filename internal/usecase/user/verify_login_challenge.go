@@ -0,0 +1,145 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+)
+
+// VerifyLoginChallengeInput represents the challenge returned by Login with
+// either a TOTP code or a recovery code, and the client starting the session
+type VerifyLoginChallengeInput struct {
+	ChallengeToken string
+	Code           string
+	RecoveryCode   string
+	UserAgent      string
+	IPAddress      string
+}
+
+// VerifyLoginChallengeOutput represents the tokens of the started session
+type VerifyLoginChallengeOutput struct {
+	SessionTokens
+}
+
+// VerifyLoginChallengeUseCase handles the second step of logging in with
+// two-factor authentication
+type VerifyLoginChallengeUseCase struct {
+	repo       user.Repository
+	challenges user.LoginChallengeRepository
+	twoFactor  user.TwoFactorRepository
+	sessions   user.SessionRepository
+	tokens     TokenIssuer
+	throttle   *LoginThrottle
+	refreshTTL time.Duration
+	logger     *logger.Logger
+}
+
+// NewVerifyLoginChallengeUseCase creates a new use case instance
+func NewVerifyLoginChallengeUseCase(
+	repo user.Repository,
+	challenges user.LoginChallengeRepository,
+	twoFactor user.TwoFactorRepository,
+	sessions user.SessionRepository,
+	tokens TokenIssuer,
+	throttle *LoginThrottle,
+	refreshTTL time.Duration,
+	logger *logger.Logger,
+) *VerifyLoginChallengeUseCase {
+	return &VerifyLoginChallengeUseCase{
+		repo:       repo,
+		challenges: challenges,
+		twoFactor:  twoFactor,
+		sessions:   sessions,
+		tokens:     tokens,
+		throttle:   throttle,
+		refreshTTL: refreshTTL,
+		logger:     logger,
+	}
+}
+
+// Execute verifies the second factor, consumes the challenge and starts a session.
+// Unknown, used and expired challenges return user.ErrInvalidLoginChallenge;
+// wrong codes return user.ErrInvalidTwoFactorCode and count as failed logins,
+// so the challenge can be retried until the login delay or lockout applies.
+func (uc *VerifyLoginChallengeUseCase) Execute(ctx context.Context, input VerifyLoginChallengeInput) (*VerifyLoginChallengeOutput, error) {
+	uc.logger.Info("Verifying login challenge")
+
+	// 1. Find usable challenge
+	challenge, err := uc.challenges.GetByHash(ctx, auth.HashSecretToken(input.ChallengeToken))
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidLoginChallenge) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to get login challenge")
+		return nil, fmt.Errorf("failed to get login challenge: %w", err)
+	}
+	if !challenge.IsUsable(time.Now()) {
+		return nil, user.ErrInvalidLoginChallenge
+	}
+
+	// 2. Load challenged user
+	u, err := uc.repo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, user.ErrInvalidLoginChallenge
+		}
+		uc.logger.WithError(err).Error("Failed to get user from database")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 3. Apply delay or lockout of earlier failures
+	if err := uc.throttle.Check(ctx, u.Email, input.IPAddress); err != nil {
+		if !errors.Is(err, user.ErrTooManyLoginAttempts) {
+			uc.logger.WithError(err).Error("Failed to check login failures")
+		}
+		return nil, err
+	}
+
+	// 4. Verify second factor
+	userTOTP, err := uc.twoFactor.GetTOTP(ctx, u.ID)
+	if err != nil && !errors.Is(err, user.ErrTwoFactorNotEnrolled) {
+		uc.logger.WithError(err).Error("Failed to get TOTP secret")
+		return nil, fmt.Errorf("failed to get totp secret: %w", err)
+	}
+	if err != nil || !userTOTP.IsEnabled() {
+		// Two-factor authentication was disabled after the challenge was issued
+		return nil, user.ErrInvalidLoginChallenge
+	}
+	if err := verifySecondFactor(ctx, uc.twoFactor, userTOTP, input.Code, input.RecoveryCode); err != nil {
+		if errors.Is(err, user.ErrInvalidTwoFactorCode) {
+			uc.logger.WithField("user_id", u.ID).Info("Login failed: wrong second factor")
+			uc.throttle.RecordFailure(ctx, u, u.Email, input.IPAddress)
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to verify second factor")
+		return nil, fmt.Errorf("failed to verify second factor: %w", err)
+	}
+
+	// 5. Consume challenge
+	if err := uc.challenges.Consume(ctx, challenge, time.Now()); err != nil {
+		if errors.Is(err, user.ErrInvalidLoginChallenge) {
+			return nil, err
+		}
+		uc.logger.WithError(err).Error("Failed to consume login challenge")
+		return nil, fmt.Errorf("failed to consume login challenge: %w", err)
+	}
+	uc.throttle.RecordSuccess(ctx, u.Email)
+
+	// 6. Start session and issue its tokens
+	tokens, err := startSession(ctx, uc.sessions, uc.tokens, u.ID, input.UserAgent, input.IPAddress, uc.refreshTTL, uc.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.WithFields(map[string]any{
+		"user_id":    u.ID,
+		"session_id": tokens.SessionID,
+	}).Info("User logged in successfully with second factor")
+
+	return &VerifyLoginChallengeOutput{SessionTokens: *tokens}, nil
+}
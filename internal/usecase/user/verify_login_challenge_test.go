@@ -0,0 +1,115 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/memclutter/go-microservices-template/internal/domain/user"
+	"github.com/memclutter/go-microservices-template/pkg/auth"
+	"github.com/memclutter/go-microservices-template/pkg/logger"
+	"github.com/memclutter/go-microservices-template/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyLoginChallengeUseCase_Execute(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	existing := &user.User{ID: "user-1", Email: "test@example.com", TwoFactorEnabledAt: &enabledAt}
+	expiresAt := time.Now().Add(15 * time.Minute)
+
+	token, tokenHash, err := auth.NewSecretToken()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		input     VerifyLoginChallengeInput
+		challenge *user.LoginChallenge
+		setup     func(*MockLoginChallengeRepository, *MockTwoFactorRepository, *MockSessionRepository, *MockTokenIssuer, *MockLoginFailureRepository)
+		wantErr   error
+	}{
+		{
+			name:      "valid code",
+			input:     VerifyLoginChallengeInput{ChallengeToken: token, Code: currentTOTPCode(t, secret), UserAgent: "curl/8.0"},
+			challenge: user.NewLoginChallenge(tokenHash, "user-1", time.Minute),
+			setup: func(challenges *MockLoginChallengeRepository, twoFactor *MockTwoFactorRepository, sessions *MockSessionRepository, tokens *MockTokenIssuer, failures *MockLoginFailureRepository) {
+				twoFactor.On("UseTOTPStep", mock.Anything, mock.Anything).Return(nil)
+				challenges.On("Consume", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				failures.On("Reset", mock.Anything, user.LoginSubjectEmail, "test@example.com").Return(nil)
+				sessions.On("Create", mock.Anything, mock.MatchedBy(func(s *user.Session) bool {
+					return s.UserID == "user-1" && s.UserAgent == "curl/8.0"
+				})).Return(nil)
+				tokens.On("IssueAccessToken", "user-1", mock.Anything).Return("token", expiresAt, nil)
+			},
+		},
+		{
+			name:      "wrong code counts as failed login",
+			input:     VerifyLoginChallengeInput{ChallengeToken: token, Code: "000000"},
+			challenge: user.NewLoginChallenge(tokenHash, "user-1", time.Minute),
+			setup: func(challenges *MockLoginChallengeRepository, twoFactor *MockTwoFactorRepository, sessions *MockSessionRepository, tokens *MockTokenIssuer, failures *MockLoginFailureRepository) {
+				failures.On("RecordFailure", mock.Anything, user.LoginSubjectEmail, "test@example.com", mock.Anything, mock.Anything).
+					Return(&user.LoginFailures{Count: 1}, nil)
+			},
+			wantErr: user.ErrInvalidTwoFactorCode,
+		},
+		{
+			name:      "replayed code",
+			input:     VerifyLoginChallengeInput{ChallengeToken: token, Code: currentTOTPCode(t, secret)},
+			challenge: user.NewLoginChallenge(tokenHash, "user-1", time.Minute),
+			setup: func(challenges *MockLoginChallengeRepository, twoFactor *MockTwoFactorRepository, sessions *MockSessionRepository, tokens *MockTokenIssuer, failures *MockLoginFailureRepository) {
+				twoFactor.On("UseTOTPStep", mock.Anything, mock.Anything).Return(user.ErrInvalidTwoFactorCode)
+				failures.On("RecordFailure", mock.Anything, user.LoginSubjectEmail, "test@example.com", mock.Anything, mock.Anything).
+					Return(&user.LoginFailures{Count: 1}, nil)
+			},
+			wantErr: user.ErrInvalidTwoFactorCode,
+		},
+		{
+			name:      "expired challenge",
+			input:     VerifyLoginChallengeInput{ChallengeToken: token, Code: currentTOTPCode(t, secret)},
+			challenge: user.NewLoginChallenge(tokenHash, "user-1", -time.Minute),
+			setup: func(*MockLoginChallengeRepository, *MockTwoFactorRepository, *MockSessionRepository, *MockTokenIssuer, *MockLoginFailureRepository) {
+			},
+			wantErr: user.ErrInvalidLoginChallenge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			repo.On("GetByID", mock.Anything, "user-1").Return(existing, nil).Maybe()
+			challenges := new(MockLoginChallengeRepository)
+			challenges.On("GetByHash", mock.Anything, tokenHash).Return(tt.challenge, nil)
+			twoFactor := new(MockTwoFactorRepository)
+			twoFactor.On("GetTOTP", mock.Anything, "user-1").
+				Return(&user.TOTP{UserID: "user-1", Secret: secret, EnabledAt: &enabledAt}, nil).Maybe()
+			sessions := new(MockSessionRepository)
+			tokens := new(MockTokenIssuer)
+			failures := new(MockLoginFailureRepository)
+			failures.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(&user.LoginFailures{}, nil).Maybe()
+			tt.setup(challenges, twoFactor, sessions, tokens, failures)
+			throttle := NewLoginThrottle(failures, user.LoginThrottlePolicy{}, user.LoginThrottlePolicy{}, new(MockEventPublisher), logger.New("test"))
+
+			uc := NewVerifyLoginChallengeUseCase(repo, challenges, twoFactor, sessions, tokens, throttle, time.Hour, logger.New("test"))
+			result, err := uc.Execute(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				sessions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "token", result.AccessToken)
+				assert.NotEmpty(t, result.RefreshToken)
+			}
+
+			challenges.AssertExpectations(t)
+			twoFactor.AssertExpectations(t)
+			sessions.AssertExpectations(t)
+			tokens.AssertExpectations(t)
+			failures.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+const (
+	// apiKeyTag starts every API key, so that leaked keys are easy to
+	// recognize, e.g. by secret scanners
+	apiKeyTag = "mst_"
+	// apiKeyVisibleLength is the number of leading characters of a key,
+	// tag included, that may be stored and shown to identify it
+	apiKeyVisibleLength = 12
+)
+
+// NewAPIKey generates a key for service-to-service callers, such as
+// "mst_9fQk2LmZ...". Only the returned prefix and hash should be stored.
+func NewAPIKey() (key, prefix, hash string, err error) {
+	secret := make([]byte, secretTokenSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	key = apiKeyTag + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:apiKeyVisibleLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hash under which an API key is stored
+func HashAPIKey(key string) string {
+	return hashSecret(key)
+}
@@ -0,0 +1,25 @@
+package auth
+
+import "context"
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	UserID string
+	// SessionID is set for callers with an access token
+	SessionID string
+	// APIKeyID is set for callers with an API key, who act as its owner UserID
+	APIKeyID string
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+)
+
+const (
+	// recoveryCodeSize is the number of random bytes in a recovery code,
+	// 80 bits that encode to 16 base32 characters
+	recoveryCodeSize = 10
+	// recoveryCodeGroup is the number of characters between dashes
+	recoveryCodeGroup = 4
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewRecoveryCodes generates n one-time recovery codes formatted for reading,
+// such as "abcd-efgh-ijkl-mnop". Only the returned hashes should be stored.
+func NewRecoveryCodes(n int) (codes, hashes []string, err error) {
+	codes = make([]string, n)
+	hashes = make([]string, n)
+	for i := range n {
+		secret := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+
+		encoded := strings.ToLower(recoveryEncoding.EncodeToString(secret))
+		groups := make([]string, 0, len(encoded)/recoveryCodeGroup)
+		for j := 0; j < len(encoded); j += recoveryCodeGroup {
+			groups = append(groups, encoded[j:j+recoveryCodeGroup])
+		}
+
+		codes[i] = strings.Join(groups, "-")
+		hashes[i] = HashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode returns the hash under which a recovery code is stored.
+// Case, dashes and spaces are ignored, so that codes can be typed loosely.
+func HashRecoveryCode(code string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(code))
+	return hashSecret(normalized)
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// refreshSecretSize is the number of random bytes in a refresh token
+const refreshSecretSize = 32
+
+// ErrInvalidRefreshToken is returned when a refresh token is malformed
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+// NewRefreshToken generates an opaque refresh token bound to a session.
+// Only the returned hash should be stored.
+func NewRefreshToken(sessionID string) (token, hash string, err error) {
+	secret := make([]byte, refreshSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(secret)
+	return sessionID + "." + encoded, hashSecret(encoded), nil
+}
+
+// ParseRefreshToken extracts the session ID and the secret hash from a refresh token
+func ParseRefreshToken(token string) (sessionID, hash string, err error) {
+	sessionID, encoded, ok := strings.Cut(token, ".")
+	if !ok || sessionID == "" {
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	secret, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(secret) != refreshSecretSize {
+		return "", "", ErrInvalidRefreshToken
+	}
+	return sessionID, hashSecret(encoded), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
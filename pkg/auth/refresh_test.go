@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshToken_RoundTrip(t *testing.T) {
+	token, hash, err := NewRefreshToken("session-1")
+	require.NoError(t, err)
+	assert.NotContains(t, hash, token)
+
+	sessionID, parsedHash, err := ParseRefreshToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", sessionID)
+	assert.Equal(t, hash, parsedHash)
+
+	other, otherHash, err := NewRefreshToken("session-1")
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+	assert.NotEqual(t, hash, otherHash)
+}
+
+func TestParseRefreshToken_Invalid(t *testing.T) {
+	for _, token := range []string{"", "session-1", ".secret", "session-1.!!!", "session-1.c2hvcnQ"} {
+		_, _, err := ParseRefreshToken(token)
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken, token)
+	}
+}
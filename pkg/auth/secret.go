@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// secretTokenSize is the number of random bytes in a secret token
+const secretTokenSize = 32
+
+// NewSecretToken generates an opaque single-purpose token, such as a password
+// reset link token. Only the returned hash should be stored.
+func NewSecretToken() (token, hash string, err error) {
+	secret := make([]byte, secretTokenSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("failed to generate secret token: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(secret)
+	return token, hashSecret(token), nil
+}
+
+// HashSecretToken returns the hash under which a secret token is stored
+func HashSecretToken(token string) string {
+	return hashSecret(token)
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretToken(t *testing.T) {
+	token, hash, err := NewSecretToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, hash)
+	assert.Equal(t, hash, HashSecretToken(token))
+
+	other, otherHash, err := NewSecretToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+	assert.NotEqual(t, hash, otherHash)
+}
+
+func TestAPIKey(t *testing.T) {
+	key, prefix, hash, err := NewAPIKey()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, "mst_"))
+	assert.True(t, strings.HasPrefix(key, prefix))
+	assert.Len(t, prefix, 12)
+	assert.Equal(t, hash, HashAPIKey(key))
+
+	other, _, otherHash, err := NewAPIKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+	assert.NotEqual(t, hash, otherHash)
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := NewRecoveryCodes(10)
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+	require.Len(t, hashes, 10)
+
+	seen := map[string]bool{}
+	for i, code := range codes {
+		assert.Regexp(t, `^[a-z2-7]{4}(-[a-z2-7]{4}){3}$`, code)
+		assert.Equal(t, hashes[i], HashRecoveryCode(code))
+		assert.False(t, seen[code], "codes are unique")
+		seen[code] = true
+	}
+
+	// Case, dashes and spaces are ignored
+	loose := strings.ToUpper(strings.ReplaceAll(codes[0], "-", " "))
+	assert.Equal(t, hashes[0], HashRecoveryCode(loose))
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidToken is returned when an access token is malformed, expired or was not signed by us
+var ErrInvalidToken = errors.New("invalid access token")
+
+// Claims are the JWT claims carried by an access token
+type Claims struct {
+	jwt.RegisteredClaims
+	// SessionID identifies the session the token was issued for
+	SessionID string `json:"sid,omitempty"`
+}
+
+// TokenManager issues and verifies HS256-signed JWT access tokens
+type TokenManager struct {
+	key    []byte
+	issuer string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewTokenManager creates a token manager that signs tokens with the given key
+func NewTokenManager(signingKey, issuer string, ttl time.Duration) (*TokenManager, error) {
+	if signingKey == "" {
+		return nil, errors.New("token signing key is required")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("access token ttl must be positive")
+	}
+	return &TokenManager{
+		key:    []byte(signingKey),
+		issuer: issuer,
+		ttl:    ttl,
+		now:    time.Now,
+	}, nil
+}
+
+// IssueAccessToken creates a signed access token for the user session and returns it with its expiry
+func (m *TokenManager) IssueAccessToken(userID, sessionID string) (string, time.Time, error) {
+	now := m.now()
+	expiresAt := now.Add(m.ttl)
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   userID,
+			Issuer:    m.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		SessionID: sessionID,
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.key)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// VerifyAccessToken checks the token signature, issuer and expiry and returns its claims
+func (m *TokenManager) VerifyAccessToken(token string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return m.key, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(m.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(m.now),
+	)
+	if err != nil || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
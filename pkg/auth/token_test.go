@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenManager_RoundTrip(t *testing.T) {
+	m, err := NewTokenManager("test-key", "test-issuer", time.Minute)
+	require.NoError(t, err)
+
+	token, expiresAt, err := m.IssueAccessToken("user-1", "session-1")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+
+	claims, err := m.VerifyAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "session-1", claims.SessionID)
+	assert.Equal(t, "test-issuer", claims.Issuer)
+	assert.NotEmpty(t, claims.ID)
+}
+
+func TestTokenManager_RejectsInvalidTokens(t *testing.T) {
+	m, err := NewTokenManager("test-key", "test-issuer", time.Minute)
+	require.NoError(t, err)
+	token, _, err := m.IssueAccessToken("user-1", "session-1")
+	require.NoError(t, err)
+
+	t.Run("other key", func(t *testing.T) {
+		other, err := NewTokenManager("other-key", "test-issuer", time.Minute)
+		require.NoError(t, err)
+		_, err = other.VerifyAccessToken(token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("other issuer", func(t *testing.T) {
+		other, err := NewTokenManager("test-key", "other-issuer", time.Minute)
+		require.NoError(t, err)
+		_, err = other.VerifyAccessToken(token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired, err := NewTokenManager("test-key", "test-issuer", time.Minute)
+		require.NoError(t, err)
+		expired.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		_, err = expired.VerifyAccessToken(token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		_, err := m.VerifyAccessToken("not-a-token")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestNewTokenManager_RequiresKey(t *testing.T) {
+	_, err := NewTokenManager("", "issuer", time.Minute)
+	assert.Error(t, err)
+}
@@ -3,17 +3,23 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config holds all application configuration
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	RabbitMQ RabbitMQConfig
-	GRPC     GRPCConfig
-	HTTP     HTTPConfig
+	App        AppConfig
+	Database   DatabaseConfig
+	RabbitMQ   RabbitMQConfig
+	GRPC       GRPCConfig
+	HTTP       HTTPConfig
+	Pagination PaginationConfig
+	Auth       AuthConfig
+	Password   PasswordConfig
+	Lockout    LockoutConfig
+	WebAuthn   WebAuthnConfig
 }
 
 type AppConfig struct {
@@ -39,12 +45,103 @@ type RabbitMQConfig struct {
 
 type GRPCConfig struct {
 	Port int
+	// TrustedProxies lists the CIDRs of proxies whose X-Forwarded-For is
+	// believed. The default trusts the HTTP gateway on loopback only.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type HTTPConfig struct {
 	Port int
 }
 
+type PaginationConfig struct {
+	// TokenSecret signs opaque page tokens so clients cannot forge cursors
+	TokenSecret string `mapstructure:"token_secret"`
+	// BatchGetMaxIDs caps the number of users one BatchGetUsers call returns
+	BatchGetMaxIDs int `mapstructure:"batch_get_max_ids"`
+}
+
+type AuthConfig struct {
+	// SigningKey is the HMAC key used to sign and verify JWT access tokens
+	SigningKey      string        `mapstructure:"signing_key"`
+	Issuer          string        `mapstructure:"issuer"`
+	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
+	// PasswordResetTTL is how long a password reset token stays usable
+	PasswordResetTTL time.Duration `mapstructure:"password_reset_ttl"`
+	// EmailVerificationTTL is how long an email verification token stays usable
+	EmailVerificationTTL time.Duration `mapstructure:"email_verification_ttl"`
+	// RequireVerifiedEmail prevents users from logging in before they verify their email
+	RequireVerifiedEmail bool `mapstructure:"require_verified_email"`
+	// TOTPEncryptionKey is the base64-encoded 32-byte key encrypting TOTP secrets at rest
+	TOTPEncryptionKey string `mapstructure:"totp_encryption_key"`
+	// TOTPIssuer names the service in authenticator apps
+	TOTPIssuer string `mapstructure:"totp_issuer"`
+	// LoginChallengeTTL is how long a login waits for its second factor
+	LoginChallengeTTL time.Duration `mapstructure:"login_challenge_ttl"`
+	// MagicLinkTTL is how long a passwordless login link stays usable
+	MagicLinkTTL time.Duration `mapstructure:"magic_link_ttl"`
+	// MagicLinkMaxRequests is how many magic links an email receives per
+	// MagicLinkRateWindow, zero for no limit
+	MagicLinkMaxRequests int           `mapstructure:"magic_link_max_requests"`
+	MagicLinkRateWindow  time.Duration `mapstructure:"magic_link_rate_window"`
+}
+
+type PasswordConfig struct {
+	// Algorithm hashes new passwords, either "argon2id" or "bcrypt".
+	// Hashes made with the other algorithm still verify and are upgraded on login.
+	Algorithm  string `mapstructure:"algorithm"`
+	BcryptCost int    `mapstructure:"bcrypt_cost"`
+	// Argon2idMemory is in KiB
+	Argon2idMemory      uint32 `mapstructure:"argon2id_memory"`
+	Argon2idIterations  uint32 `mapstructure:"argon2id_iterations"`
+	Argon2idParallelism uint8  `mapstructure:"argon2id_parallelism"`
+	Argon2idSaltLength  uint32 `mapstructure:"argon2id_salt_length"`
+	Argon2idKeyLength   uint32 `mapstructure:"argon2id_key_length"`
+
+	// MinLength and MaxLength count characters
+	MinLength        int  `mapstructure:"min_length"`
+	MaxLength        int  `mapstructure:"max_length"`
+	RequireUppercase bool `mapstructure:"require_uppercase"`
+	RequireLowercase bool `mapstructure:"require_lowercase"`
+	RequireDigit     bool `mapstructure:"require_digit"`
+	RequireSymbol    bool `mapstructure:"require_symbol"`
+	// BlocklistFile lists forbidden passwords, one per line. Empty disables the check.
+	BlocklistFile string `mapstructure:"blocklist_file"`
+	// HistorySize is how many recent passwords, the current one included,
+	// cannot be reused. Zero allows reuse.
+	HistorySize int `mapstructure:"history_size"`
+}
+
+// LockoutConfig holds the failed login thresholds per email and per client IP address
+type LockoutConfig struct {
+	Email LoginThrottleConfig `mapstructure:"email"`
+	IP    LoginThrottleConfig `mapstructure:"ip"`
+}
+
+type LoginThrottleConfig struct {
+	// DelayAfter failures, each attempt waits BaseDelay doubled per further failure, up to MaxDelay
+	DelayAfter int           `mapstructure:"delay_after"`
+	BaseDelay  time.Duration `mapstructure:"base_delay"`
+	MaxDelay   time.Duration `mapstructure:"max_delay"`
+	// LockAfter failures, logins are locked for LockDuration
+	LockAfter    int           `mapstructure:"lock_after"`
+	LockDuration time.Duration `mapstructure:"lock_duration"`
+	// ResetAfter without failures, they are forgotten
+	ResetAfter time.Duration `mapstructure:"reset_after"`
+}
+
+// WebAuthnConfig identifies the service as a WebAuthn relying party for passkeys
+type WebAuthnConfig struct {
+	// RPID is the domain passkeys are scoped to, the host of Origins or a parent domain of it
+	RPID   string `mapstructure:"rp_id"`
+	RPName string `mapstructure:"rp_name"`
+	// Origins lists the web origins allowed to use passkeys, e.g. https://example.com
+	Origins []string `mapstructure:"origins"`
+	// ChallengeTTL is how long a registration or login ceremony stays open
+	ChallengeTTL time.Duration `mapstructure:"challenge_ttl"`
+}
+
 // Load reads configuration from file and environment variables
 func Load(configPath string) (*Config, error) {
 	v := viper.New()
@@ -64,7 +161,51 @@ func Load(configPath string) (*Config, error) {
 	v.SetDefault("app.name", "microservices-template")
 	v.SetDefault("http.port", 8080)
 	v.SetDefault("grpc.port", 50051)
+	v.SetDefault("grpc.trusted_proxies", []string{"127.0.0.1/32", "::1/128"})
 	v.SetDefault("database.sslmode", "disable")
+	v.SetDefault("pagination.batch_get_max_ids", 100)
+	v.SetDefault("auth.issuer", "microservices-template")
+	v.SetDefault("auth.access_token_ttl", 15*time.Minute)
+	v.SetDefault("auth.refresh_token_ttl", 30*24*time.Hour)
+	v.SetDefault("auth.password_reset_ttl", time.Hour)
+	v.SetDefault("auth.email_verification_ttl", 48*time.Hour)
+	v.SetDefault("auth.require_verified_email", false)
+	v.SetDefault("auth.totp_issuer", "microservices-template")
+	v.SetDefault("auth.login_challenge_ttl", 5*time.Minute)
+	v.SetDefault("auth.magic_link_ttl", 15*time.Minute)
+	v.SetDefault("auth.magic_link_max_requests", 3)
+	v.SetDefault("auth.magic_link_rate_window", time.Hour)
+	v.SetDefault("password.algorithm", "argon2id")
+	v.SetDefault("password.bcrypt_cost", 12)
+	v.SetDefault("password.argon2id_memory", 19*1024)
+	v.SetDefault("password.argon2id_iterations", 2)
+	v.SetDefault("password.argon2id_parallelism", 1)
+	v.SetDefault("password.argon2id_salt_length", 16)
+	v.SetDefault("password.argon2id_key_length", 32)
+	v.SetDefault("password.min_length", 8)
+	v.SetDefault("password.max_length", 64)
+	v.SetDefault("password.require_uppercase", false)
+	v.SetDefault("password.require_lowercase", false)
+	v.SetDefault("password.require_digit", false)
+	v.SetDefault("password.require_symbol", false)
+	v.SetDefault("password.blocklist_file", "")
+	v.SetDefault("password.history_size", 5)
+	v.SetDefault("lockout.email.delay_after", 3)
+	v.SetDefault("lockout.email.base_delay", time.Second)
+	v.SetDefault("lockout.email.max_delay", 30*time.Second)
+	v.SetDefault("lockout.email.lock_after", 10)
+	v.SetDefault("lockout.email.lock_duration", 15*time.Minute)
+	v.SetDefault("lockout.email.reset_after", time.Hour)
+	v.SetDefault("lockout.ip.delay_after", 20)
+	v.SetDefault("lockout.ip.base_delay", time.Second)
+	v.SetDefault("lockout.ip.max_delay", 30*time.Second)
+	v.SetDefault("lockout.ip.lock_after", 100)
+	v.SetDefault("lockout.ip.lock_duration", 15*time.Minute)
+	v.SetDefault("lockout.ip.reset_after", time.Hour)
+	v.SetDefault("webauthn.rp_id", "localhost")
+	v.SetDefault("webauthn.rp_name", "Microservices Template")
+	v.SetDefault("webauthn.origins", []string{"http://localhost:8080"})
+	v.SetDefault("webauthn.challenge_ttl", 5*time.Minute)
 
 	// Read config file
 	if err := v.ReadInConfig(); err != nil {
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,6 +29,36 @@ func TestLoad(t *testing.T) {
 			check: func(t *testing.T, cfg *Config) {
 				assert.Equal(t, "test", cfg.App.Env)
 				assert.Equal(t, 8080, cfg.HTTP.Port)
+				assert.Equal(t, []string{"127.0.0.1/32", "::1/128"}, cfg.GRPC.TrustedProxies)
+				assert.Equal(t, 100, cfg.Pagination.BatchGetMaxIDs)
+				assert.Equal(t, 15*time.Minute, cfg.Auth.AccessTokenTTL)
+				assert.Equal(t, 30*24*time.Hour, cfg.Auth.RefreshTokenTTL)
+				assert.Equal(t, time.Hour, cfg.Auth.PasswordResetTTL)
+				assert.Equal(t, 48*time.Hour, cfg.Auth.EmailVerificationTTL)
+				assert.False(t, cfg.Auth.RequireVerifiedEmail)
+				assert.Equal(t, "microservices-template", cfg.Auth.TOTPIssuer)
+				assert.Equal(t, 5*time.Minute, cfg.Auth.LoginChallengeTTL)
+				assert.Equal(t, 15*time.Minute, cfg.Auth.MagicLinkTTL)
+				assert.Equal(t, 3, cfg.Auth.MagicLinkMaxRequests)
+				assert.Equal(t, time.Hour, cfg.Auth.MagicLinkRateWindow)
+				assert.Equal(t, "argon2id", cfg.Password.Algorithm)
+				assert.Equal(t, 12, cfg.Password.BcryptCost)
+				assert.Equal(t, uint32(19*1024), cfg.Password.Argon2idMemory)
+				assert.Equal(t, uint32(2), cfg.Password.Argon2idIterations)
+				assert.Equal(t, uint8(1), cfg.Password.Argon2idParallelism)
+				assert.Equal(t, 8, cfg.Password.MinLength)
+				assert.Equal(t, 64, cfg.Password.MaxLength)
+				assert.False(t, cfg.Password.RequireDigit)
+				assert.Empty(t, cfg.Password.BlocklistFile)
+				assert.Equal(t, 5, cfg.Password.HistorySize)
+				assert.Equal(t, 3, cfg.Lockout.Email.DelayAfter)
+				assert.Equal(t, 10, cfg.Lockout.Email.LockAfter)
+				assert.Equal(t, 15*time.Minute, cfg.Lockout.Email.LockDuration)
+				assert.Equal(t, 100, cfg.Lockout.IP.LockAfter)
+				assert.Equal(t, time.Hour, cfg.Lockout.IP.ResetAfter)
+				assert.Equal(t, "localhost", cfg.WebAuthn.RPID)
+				assert.Equal(t, []string{"http://localhost:8080"}, cfg.WebAuthn.Origins)
+				assert.Equal(t, 5*time.Minute, cfg.WebAuthn.ChallengeTTL)
 			},
 		},
 	}
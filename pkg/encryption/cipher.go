@@ -0,0 +1,74 @@
+// Package encryption encrypts small secrets, such as TOTP keys, before they
+// are stored
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the length of an AES-256 key in bytes
+const KeySize = 32
+
+// ErrDecrypt is returned for a ciphertext that was tampered with, encrypted
+// under another key or bound to other associated data
+var ErrDecrypt = errors.New("failed to decrypt")
+
+// Cipher encrypts with AES-256-GCM. Ciphertexts are base64 encoded with the
+// random nonce prepended.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher from a base64 encoded 32 byte key
+func NewCipher(key string) (*Cipher, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key must be base64 encoded: %w", err)
+	}
+	if len(raw) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(raw))
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt encrypts plaintext. associatedData, such as the owner's ID, is not
+// stored but must be passed to Decrypt, so that a ciphertext copied to
+// another record does not decrypt.
+func (c *Cipher) Encrypt(plaintext, associatedData []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, plaintext, associatedData)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt, returning ErrDecrypt when the ciphertext does not authenticate
+func (c *Cipher) Decrypt(ciphertext string, associatedData []byte) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+
+	nonce, sealed := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, sealed, associatedData)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,56 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", KeySize)))
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c, err := NewCipher(testKey)
+	require.NoError(t, err)
+
+	ciphertext, err := c.Encrypt([]byte("secret"), []byte("user-1"))
+	require.NoError(t, err)
+	assert.NotContains(t, ciphertext, "secret")
+
+	plaintext, err := c.Decrypt(ciphertext, []byte("user-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	// Every encryption uses a fresh nonce
+	again, err := c.Encrypt([]byte("secret"), []byte("user-1"))
+	require.NoError(t, err)
+	assert.NotEqual(t, ciphertext, again)
+}
+
+func TestCipher_RejectsTampering(t *testing.T) {
+	c, err := NewCipher(testKey)
+	require.NoError(t, err)
+	ciphertext, err := c.Encrypt([]byte("secret"), []byte("user-1"))
+	require.NoError(t, err)
+
+	_, err = c.Decrypt(ciphertext, []byte("user-2"))
+	assert.ErrorIs(t, err, ErrDecrypt, "bound to other associated data")
+
+	other, err := NewCipher(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("o", KeySize))))
+	require.NoError(t, err)
+	_, err = other.Decrypt(ciphertext, []byte("user-1"))
+	assert.ErrorIs(t, err, ErrDecrypt, "encrypted under another key")
+
+	_, err = c.Decrypt("not base64!", []byte("user-1"))
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestNewCipher_InvalidKey(t *testing.T) {
+	_, err := NewCipher("not base64!")
+	assert.Error(t, err)
+
+	_, err = NewCipher(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+}
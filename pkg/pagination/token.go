@@ -0,0 +1,70 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidPageToken is returned when a page token is malformed or was not signed by us
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// TokenCodec encodes pagination cursors into opaque, tamper-proof page tokens
+type TokenCodec struct {
+	secret []byte
+}
+
+// NewTokenCodec creates a codec that signs tokens with HMAC-SHA256
+func NewTokenCodec(secret string) (*TokenCodec, error) {
+	if secret == "" {
+		return nil, errors.New("page token secret is required")
+	}
+	return &TokenCodec{secret: []byte(secret)}, nil
+}
+
+// Encode serializes the cursor and signs it
+func (c *TokenCodec) Encode(cursor any) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode verifies the token signature and deserializes the cursor into dst
+func (c *TokenCodec) Decode(token string, dst any) error {
+	encPayload, encSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidPageToken
+	}
+
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(encPayload)
+	if err != nil {
+		return ErrInvalidPageToken
+	}
+	sig, err := enc.DecodeString(encSig)
+	if err != nil {
+		return ErrInvalidPageToken
+	}
+	if !hmac.Equal(sig, c.sign(payload)) {
+		return ErrInvalidPageToken
+	}
+
+	if err := json.Unmarshal(payload, dst); err != nil {
+		return ErrInvalidPageToken
+	}
+	return nil
+}
+
+func (c *TokenCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,58 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCursor struct {
+	CreatedAt int64  `json:"c"`
+	ID        string `json:"i"`
+}
+
+func TestTokenCodec_RoundTrip(t *testing.T) {
+	codec, err := NewTokenCodec("secret")
+	require.NoError(t, err)
+
+	token, err := codec.Encode(testCursor{CreatedAt: 42, ID: "user-1"})
+	require.NoError(t, err)
+	assert.NotContains(t, token, "user-1")
+
+	var got testCursor
+	require.NoError(t, codec.Decode(token, &got))
+	assert.Equal(t, testCursor{CreatedAt: 42, ID: "user-1"}, got)
+}
+
+func TestTokenCodec_Decode(t *testing.T) {
+	codec, err := NewTokenCodec("secret")
+	require.NoError(t, err)
+	other, err := NewTokenCodec("other-secret")
+	require.NoError(t, err)
+
+	foreign, err := other.Encode(testCursor{CreatedAt: 42, ID: "user-1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "missing signature", token: "eyJjIjo0Mn0"},
+		{name: "not base64", token: "!!!.???"},
+		{name: "signed with another secret", token: foreign},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got testCursor
+			assert.ErrorIs(t, codec.Decode(tt.token, &got), ErrInvalidPageToken)
+		})
+	}
+}
+
+func TestNewTokenCodec_EmptySecret(t *testing.T) {
+	_, err := NewTokenCodec("")
+	assert.Error(t, err)
+}
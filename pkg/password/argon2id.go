@@ -0,0 +1,113 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2idPrefix starts every Argon2id hash in PHC string format
+const argon2idPrefix = "$argon2id$"
+
+// Argon2idParams holds the Argon2id cost parameters
+type Argon2idParams struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// Argon2id hashes passwords with Argon2id
+type Argon2id struct {
+	params Argon2idParams
+}
+
+// NewArgon2id creates an Argon2id hasher
+func NewArgon2id(params Argon2idParams) (*Argon2id, error) {
+	if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return nil, errors.New("argon2id memory, iterations and parallelism must be positive")
+	}
+	if params.SaltLength < 8 || params.KeyLength < 16 {
+		return nil, errors.New("argon2id salt must be at least 8 bytes and key at least 16 bytes")
+	}
+	return &Argon2id{params: params}, nil
+}
+
+// Hash hashes password with Argon2id and encodes the result in PHC string format:
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+func (a *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, a.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	p := a.params
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks password against an Argon2id hash using the parameters recorded in it
+func (a *Argon2id) Verify(hash, password string) error {
+	p, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return err
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	if subtle.ConstantTimeCompare(key, candidate) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// NeedsRehash reports whether hash is not an Argon2id hash of the configured parameters
+func (a *Argon2id) NeedsRehash(hash string) bool {
+	p, _, _, err := decodeArgon2id(hash)
+	return err != nil || p != a.params
+}
+
+func (a *Argon2id) identifies(hash string) bool {
+	return strings.HasPrefix(hash, argon2idPrefix)
+}
+
+// decodeArgon2id parses a PHC string format Argon2id hash
+func decodeArgon2id(hash string) (Argon2idParams, []byte, []byte, error) {
+	var p Argon2idParams
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, ErrUnsupportedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrUnsupportedHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, ErrUnsupportedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, ErrUnsupportedHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return p, nil, nil, ErrUnsupportedHash
+	}
+
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+	return p, salt, key, nil
+}
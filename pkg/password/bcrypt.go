@@ -0,0 +1,71 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrPasswordTooLong is returned by bcrypt for passwords longer than 72 bytes,
+// which bcrypt would otherwise silently truncate
+var ErrPasswordTooLong = errors.New("password is too long for bcrypt")
+
+// BcryptMaxPasswordLength is the number of bytes bcrypt takes into account
+const BcryptMaxPasswordLength = 72
+
+// BcryptParams holds the bcrypt work factor
+type BcryptParams struct {
+	Cost int
+}
+
+// Bcrypt hashes passwords with bcrypt
+type Bcrypt struct {
+	cost int
+}
+
+// NewBcrypt creates a bcrypt hasher
+func NewBcrypt(params BcryptParams) (*Bcrypt, error) {
+	if params.Cost < bcrypt.MinCost || params.Cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return &Bcrypt{cost: params.Cost}, nil
+}
+
+// Hash hashes password with bcrypt
+func (b *Bcrypt) Hash(password string) (string, error) {
+	if len(password) > BcryptMaxPasswordLength {
+		return "", ErrPasswordTooLong
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Verify checks password against a bcrypt hash
+func (b *Bcrypt) Verify(hash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return ErrMismatch
+	case !b.identifies(hash):
+		return ErrUnsupportedHash
+	default:
+		return fmt.Errorf("failed to verify password: %w", err)
+	}
+}
+
+// NeedsRehash reports whether hash is not a bcrypt hash of the configured cost
+func (b *Bcrypt) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != b.cost
+}
+
+func (b *Bcrypt) identifies(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
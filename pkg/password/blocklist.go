@@ -0,0 +1,62 @@
+package password
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Blocklist is a set of passwords that must not be used, compared
+// case-insensitively
+type Blocklist struct {
+	passwords map[string]struct{}
+}
+
+// NewBlocklist creates a blocklist of the given passwords
+func NewBlocklist(passwords ...string) *Blocklist {
+	b := &Blocklist{passwords: make(map[string]struct{}, len(passwords))}
+	for _, p := range passwords {
+		b.add(p)
+	}
+	return b
+}
+
+// LoadBlocklist reads a blocklist file with one password per line.
+// Blank lines and lines starting with # are skipped.
+func LoadBlocklist(path string) (*Blocklist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open password blocklist: %w", err)
+	}
+	defer f.Close()
+
+	b := NewBlocklist()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		b.add(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read password blocklist: %w", err)
+	}
+	return b, nil
+}
+
+// Contains reports whether password is blocklisted
+func (b *Blocklist) Contains(password string) bool {
+	_, ok := b.passwords[strings.ToLower(password)]
+	return ok
+}
+
+// Len returns the number of blocklisted passwords
+func (b *Blocklist) Len() int {
+	return len(b.passwords)
+}
+
+func (b *Blocklist) add(password string) {
+	b.passwords[strings.ToLower(password)] = struct{}{}
+}
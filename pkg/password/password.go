@@ -0,0 +1,92 @@
+// Package password hashes and verifies user passwords.
+//
+// Hashes are self-describing: bcrypt hashes use the modular crypt format
+// ($2a$...) and Argon2id hashes the PHC string format ($argon2id$...), so a
+// Hasher verifies both regardless of the algorithm it hashes new passwords with.
+package password
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrMismatch is returned when a password does not match a hash
+	ErrMismatch = errors.New("password does not match")
+	// ErrUnsupportedHash is returned when a hash was not made by a known algorithm
+	ErrUnsupportedHash = errors.New("unsupported password hash")
+)
+
+// Algorithm names accepted by New
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// scheme is a single hashing algorithm
+type scheme interface {
+	Hash(password string) (string, error)
+	Verify(hash, password string) error
+	NeedsRehash(hash string) bool
+	// identifies reports whether hash was made by this algorithm
+	identifies(hash string) bool
+}
+
+// Config selects the algorithm for new hashes and its parameters
+type Config struct {
+	Algorithm string
+	Bcrypt    BcryptParams
+	Argon2id  Argon2idParams
+}
+
+// Hasher hashes passwords with the configured algorithm and verifies hashes
+// made by any supported algorithm
+type Hasher struct {
+	preferred scheme
+	schemes   []scheme
+}
+
+// New creates a hasher that hashes new passwords with cfg.Algorithm
+func New(cfg Config) (*Hasher, error) {
+	bcryptScheme, err := NewBcrypt(cfg.Bcrypt)
+	if err != nil {
+		return nil, err
+	}
+	argon2idScheme, err := NewArgon2id(cfg.Argon2id)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Hasher{schemes: []scheme{argon2idScheme, bcryptScheme}}
+	switch cfg.Algorithm {
+	case AlgorithmArgon2id:
+		h.preferred = argon2idScheme
+	case AlgorithmBcrypt:
+		h.preferred = bcryptScheme
+	default:
+		return nil, fmt.Errorf("unknown password hashing algorithm %q", cfg.Algorithm)
+	}
+	return h, nil
+}
+
+// Hash hashes password with the configured algorithm
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.preferred.Hash(password)
+}
+
+// Verify checks password against a hash made by any supported algorithm.
+// It returns ErrMismatch when the password is wrong.
+func (h *Hasher) Verify(hash, password string) error {
+	for _, s := range h.schemes {
+		if s.identifies(hash) {
+			return s.Verify(hash, password)
+		}
+	}
+	return ErrUnsupportedHash
+}
+
+// NeedsRehash reports whether hash was made with another algorithm or with
+// parameters other than the configured ones
+func (h *Hasher) NeedsRehash(hash string) bool {
+	return !h.preferred.identifies(hash) || h.preferred.NeedsRehash(hash)
+}
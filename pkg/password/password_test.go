@@ -0,0 +1,114 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testArgon2idParams = Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func newTestHasher(t *testing.T, algorithm string, bcryptCost int, argon2id Argon2idParams) *Hasher {
+	t.Helper()
+	h, err := New(Config{Algorithm: algorithm, Bcrypt: BcryptParams{Cost: bcryptCost}, Argon2id: argon2id})
+	require.NoError(t, err)
+	return h
+}
+
+func TestHasher_RoundTrip(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmArgon2id, AlgorithmBcrypt} {
+		t.Run(algorithm, func(t *testing.T) {
+			h := newTestHasher(t, algorithm, 4, testArgon2idParams)
+
+			hash, err := h.Hash("password123")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, "$"))
+			assert.NotContains(t, hash, "password123")
+
+			assert.NoError(t, h.Verify(hash, "password123"))
+			assert.ErrorIs(t, h.Verify(hash, "wrong-password"), ErrMismatch)
+			assert.False(t, h.NeedsRehash(hash))
+
+			other, err := h.Hash("password123")
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, other, "hashes must be salted")
+		})
+	}
+}
+
+func TestHasher_VerifiesEveryFormat(t *testing.T) {
+	bcryptHasher := newTestHasher(t, AlgorithmBcrypt, 4, testArgon2idParams)
+	argon2idHasher := newTestHasher(t, AlgorithmArgon2id, 4, testArgon2idParams)
+
+	bcryptHash, err := bcryptHasher.Hash("password123")
+	require.NoError(t, err)
+	argon2idHash, err := argon2idHasher.Hash("password123")
+	require.NoError(t, err)
+
+	assert.NoError(t, argon2idHasher.Verify(bcryptHash, "password123"))
+	assert.NoError(t, bcryptHasher.Verify(argon2idHash, "password123"))
+	assert.True(t, argon2idHasher.NeedsRehash(bcryptHash))
+	assert.True(t, bcryptHasher.NeedsRehash(argon2idHash))
+
+	assert.ErrorIs(t, argon2idHasher.Verify("plain-text", "plain-text"), ErrUnsupportedHash)
+	assert.ErrorIs(t, argon2idHasher.Verify("$argon2id$v=19$m=1024$salt$key", "password123"), ErrUnsupportedHash)
+}
+
+func TestHasher_NeedsRehashWithOutdatedParameters(t *testing.T) {
+	old := newTestHasher(t, AlgorithmArgon2id, 4, testArgon2idParams)
+	hash, err := old.Hash("password123")
+	require.NoError(t, err)
+
+	stronger := testArgon2idParams
+	stronger.Iterations = 2
+	current := newTestHasher(t, AlgorithmArgon2id, 4, stronger)
+	assert.NoError(t, current.Verify(hash, "password123"), "old parameters must still verify")
+	assert.True(t, current.NeedsRehash(hash))
+
+	oldBcrypt := newTestHasher(t, AlgorithmBcrypt, 4, testArgon2idParams)
+	bcryptHash, err := oldBcrypt.Hash("password123")
+	require.NoError(t, err)
+	assert.True(t, newTestHasher(t, AlgorithmBcrypt, 5, testArgon2idParams).NeedsRehash(bcryptHash))
+}
+
+func TestBcrypt_RejectsTruncatedPasswords(t *testing.T) {
+	h := newTestHasher(t, AlgorithmBcrypt, 4, testArgon2idParams)
+	_, err := h.Hash(strings.Repeat("a", 73))
+	assert.ErrorIs(t, err, ErrPasswordTooLong)
+
+	long := newTestHasher(t, AlgorithmArgon2id, 4, testArgon2idParams)
+	hash, err := long.Hash(strings.Repeat("a", 73))
+	require.NoError(t, err)
+	assert.ErrorIs(t, long.Verify(hash, strings.Repeat("a", 72)), ErrMismatch)
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	_, err := New(Config{Algorithm: "md5", Bcrypt: BcryptParams{Cost: 10}, Argon2id: testArgon2idParams})
+	assert.Error(t, err)
+
+	_, err = New(Config{Algorithm: AlgorithmBcrypt, Bcrypt: BcryptParams{Cost: 1}, Argon2id: testArgon2idParams})
+	assert.Error(t, err)
+
+	_, err = New(Config{Algorithm: AlgorithmArgon2id, Bcrypt: BcryptParams{Cost: 10}})
+	assert.Error(t, err)
+}
+
+func TestLoadBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# common passwords\n123456\n\n  Password1  \nqwerty\n"), 0o600))
+
+	blocklist, err := LoadBlocklist(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, blocklist.Len())
+	assert.True(t, blocklist.Contains("123456"))
+	assert.True(t, blocklist.Contains("PASSWORD1"), "comparison ignores case")
+	assert.False(t, blocklist.Contains("# common passwords"))
+	assert.False(t, blocklist.Contains("correct horse"))
+
+	_, err = LoadBlocklist(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
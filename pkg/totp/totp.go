@@ -0,0 +1,130 @@
+// Package totp implements time-based one-time passwords (RFC 6238) with the
+// parameters authenticator apps support universally: HMAC-SHA1, 6 digits and
+// a 30 second period.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Digits is the length of a code
+	Digits = 6
+	// Period is how long a code is valid
+	Period = 30 * time.Second
+	// Skew is the number of periods before and after the current one whose
+	// codes are still accepted, to allow for clock drift
+	Skew = 1
+
+	// secretSize is the number of random bytes in a secret, as recommended by RFC 4226
+	secretSize = 20
+)
+
+var (
+	// ErrInvalidSecret is returned for a secret that is not valid base32
+	ErrInvalidSecret = errors.New("invalid totp secret")
+	// ErrInvalidCode is returned for a code that does not match any accepted period
+	ErrInvalidCode = errors.New("invalid totp code")
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random secret, base32 encoded without padding
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+// Step returns the time step, the number of periods since the Unix epoch, of t
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period/time.Second)
+}
+
+// Code returns the code for the secret at time t
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, Step(t)), nil
+}
+
+// Validate checks code against the periods around t and returns the time step
+// it matched, so that callers can reject a code that was already used.
+// It returns ErrInvalidCode when no period matches.
+func Validate(secret, code string, t time.Time) (int64, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, err
+	}
+	if len(code) != Digits {
+		return 0, ErrInvalidCode
+	}
+
+	current := Step(t)
+	for step := current - Skew; step <= current+Skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(hotp(key, step)), []byte(code)) == 1 {
+			return step, nil
+		}
+	}
+	return 0, ErrInvalidCode
+}
+
+// URI returns the otpauth:// URI that authenticator apps import, usually from a QR code
+func URI(issuer, accountName, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(int(Period/time.Second)))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + accountName,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
+
+// hotp computes the HOTP value (RFC 4226) of the time step
+func hotp(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for range Digits {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", Digits, value%modulo)
+}
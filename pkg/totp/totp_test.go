@@ -0,0 +1,79 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors, "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists 8 digit codes, 6 digit codes are their last 6 digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := Code(rfcSecret, time.Unix(tt.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tt.code, code, "at %d", tt.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+
+	code, err := Code(secret, now)
+	require.NoError(t, err)
+	step, err := Validate(secret, code, now)
+	require.NoError(t, err)
+	assert.Equal(t, Step(now), step)
+
+	// The previous period is accepted for clock drift
+	previous, err := Code(secret, now.Add(-Period))
+	require.NoError(t, err)
+	step, err = Validate(secret, previous, now)
+	require.NoError(t, err)
+	assert.Equal(t, Step(now)-1, step)
+
+	// Older periods are not
+	stale, err := Code(secret, now.Add(-3*Period))
+	require.NoError(t, err)
+	_, err = Validate(secret, stale, now)
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	_, err = Validate(secret, "12345", now)
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	_, err = Validate("not base32!", code, now)
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Example App", "user@example.com", rfcSecret)
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Example App:user@example.com", parsed.Path)
+	assert.Equal(t, rfcSecret, parsed.Query().Get("secret"))
+	assert.Equal(t, "Example App", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+	assert.Equal(t, "30", parsed.Query().Get("period"))
+}
@@ -0,0 +1,154 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// maxCBORDepth bounds the nesting of arrays and maps in decoded CBOR
+const maxCBORDepth = 16
+
+var errCBOR = errors.New("malformed cbor")
+
+// decodeCBOR decodes the first CBOR (RFC 8949) data item of data and returns
+// it together with the bytes following it. Only the subset authenticators
+// produce is supported: definite-length integers, byte and text strings,
+// arrays, maps, booleans and null. Integers decode to int64, byte strings to
+// []byte, text strings to string, arrays to []any and maps to map[any]any.
+func decodeCBOR(data []byte) (any, []byte, error) {
+	return decodeCBORItem(data, 0)
+}
+
+func decodeCBORItem(data []byte, depth int) (any, []byte, error) {
+	if depth > maxCBORDepth {
+		return nil, nil, fmt.Errorf("%w: nested too deeply", errCBOR)
+	}
+	if len(data) == 0 {
+		return nil, nil, fmt.Errorf("%w: unexpected end of data", errCBOR)
+	}
+
+	major := data[0] >> 5
+	info := data[0] & 0x1f
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, data[1:], nil
+		case 21:
+			return true, data[1:], nil
+		case 22:
+			return nil, data[1:], nil
+		default:
+			return nil, nil, fmt.Errorf("%w: unsupported simple value %d", errCBOR, info)
+		}
+	}
+
+	arg, rest, err := decodeCBORArgument(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0:
+		if arg > math.MaxInt64 {
+			return nil, nil, fmt.Errorf("%w: integer overflow", errCBOR)
+		}
+		return int64(arg), rest, nil
+	case 1:
+		if arg > math.MaxInt64 {
+			return nil, nil, fmt.Errorf("%w: integer overflow", errCBOR)
+		}
+		return -1 - int64(arg), rest, nil
+	case 2, 3:
+		if arg > uint64(len(rest)) {
+			return nil, nil, fmt.Errorf("%w: unexpected end of data", errCBOR)
+		}
+		if major == 2 {
+			return rest[:arg], rest[arg:], nil
+		}
+		return string(rest[:arg]), rest[arg:], nil
+	case 4:
+		// Every item takes at least one byte
+		if arg > uint64(len(rest)) {
+			return nil, nil, fmt.Errorf("%w: unexpected end of data", errCBOR)
+		}
+		items := make([]any, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			var item any
+			item, rest, err = decodeCBORItem(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, rest, nil
+	case 5:
+		if arg > uint64(len(rest))/2 {
+			return nil, nil, fmt.Errorf("%w: unexpected end of data", errCBOR)
+		}
+		entries := make(map[any]any, arg)
+		for i := uint64(0); i < arg; i++ {
+			var key, value any
+			key, rest, err = decodeCBORItem(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, fmt.Errorf("%w: unsupported map key", errCBOR)
+			}
+			value, rest, err = decodeCBORItem(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, ok := entries[key]; ok {
+				return nil, nil, fmt.Errorf("%w: duplicate map key", errCBOR)
+			}
+			entries[key] = value
+		}
+		return entries, rest, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: unsupported major type %d", errCBOR, major)
+	}
+}
+
+// decodeCBORArgument returns the argument of the initial byte of data and the
+// bytes following it
+func decodeCBORArgument(data []byte) (uint64, []byte, error) {
+	info := data[0] & 0x1f
+	data = data[1:]
+
+	var size int
+	switch {
+	case info < 24:
+		return uint64(info), data, nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, nil, fmt.Errorf("%w: indefinite lengths are not supported", errCBOR)
+	}
+	if len(data) < size {
+		return 0, nil, fmt.Errorf("%w: unexpected end of data", errCBOR)
+	}
+
+	var arg uint64
+	switch size {
+	case 1:
+		arg = uint64(data[0])
+	case 2:
+		arg = uint64(binary.BigEndian.Uint16(data))
+	case 4:
+		arg = uint64(binary.BigEndian.Uint32(data))
+	case 8:
+		arg = binary.BigEndian.Uint64(data)
+	}
+	return arg, data[size:], nil
+}
@@ -0,0 +1,120 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+// COSE algorithm identifiers of the supported public keys
+const (
+	AlgES256 = -7
+	AlgEdDSA = -8
+	AlgRS256 = -257
+)
+
+// SupportedAlgorithms lists the COSE algorithms accepted for credentials in
+// order of preference, as offered to authenticators during registration
+var SupportedAlgorithms = []int{AlgES256, AlgEdDSA, AlgRS256}
+
+// COSE key parameters (RFC 9052, RFC 9053)
+const (
+	coseKeyType   = 1
+	coseAlgorithm = 3
+	coseCurve     = -1 // x for OKP keys, n for RSA keys
+	coseX         = -2 // e for RSA keys
+	coseY         = -3
+
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+
+	// minRSAKeyBits rejects RSA keys too short to be secure
+	minRSAKeyBits = 2048
+)
+
+// publicKey verifies signatures of a credential
+type publicKey interface {
+	verify(data, signature []byte) bool
+}
+
+type es256Key struct{ key *ecdsa.PublicKey }
+
+func (k es256Key) verify(data, signature []byte) bool {
+	digest := sha256.Sum256(data)
+	return ecdsa.VerifyASN1(k.key, digest[:], signature)
+}
+
+type eddsaKey struct{ key ed25519.PublicKey }
+
+func (k eddsaKey) verify(data, signature []byte) bool {
+	return ed25519.Verify(k.key, data, signature)
+}
+
+type rs256Key struct{ key *rsa.PublicKey }
+
+func (k rs256Key) verify(data, signature []byte) bool {
+	digest := sha256.Sum256(data)
+	return rsa.VerifyPKCS1v15(k.key, crypto.SHA256, digest[:], signature) == nil
+}
+
+// parsePublicKey parses a COSE_Key of one of the SupportedAlgorithms and
+// returns it with its algorithm
+func parsePublicKey(coseKey []byte) (publicKey, int, error) {
+	value, rest, err := decodeCBOR(coseKey)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+	}
+	params, ok := value.(map[any]any)
+	if !ok || len(rest) != 0 {
+		return nil, 0, ErrInvalidPublicKey
+	}
+
+	keyType, _ := params[int64(coseKeyType)].(int64)
+	alg, _ := params[int64(coseAlgorithm)].(int64)
+	switch {
+	case keyType == coseKeyTypeEC2 && alg == AlgES256:
+		curve, _ := params[int64(coseCurve)].(int64)
+		x, _ := params[int64(coseX)].([]byte)
+		y, _ := params[int64(coseY)].([]byte)
+		if curve != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, 0, ErrInvalidPublicKey
+		}
+		// Reject points that are not on the curve
+		point := append(append([]byte{4}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, 0, ErrInvalidPublicKey
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		return es256Key{key: key}, AlgES256, nil
+	case keyType == coseKeyTypeOKP && alg == AlgEdDSA:
+		curve, _ := params[int64(coseCurve)].(int64)
+		x, _ := params[int64(coseX)].([]byte)
+		if curve != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, 0, ErrInvalidPublicKey
+		}
+		return eddsaKey{key: ed25519.PublicKey(x)}, AlgEdDSA, nil
+	case keyType == coseKeyTypeRSA && alg == AlgRS256:
+		n, _ := params[int64(coseCurve)].([]byte)
+		e, _ := params[int64(coseX)].([]byte)
+		if len(e) == 0 || len(e) > 4 {
+			return nil, 0, ErrInvalidPublicKey
+		}
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if key.N.BitLen() < minRSAKeyBits || key.E < 3 {
+			return nil, 0, ErrInvalidPublicKey
+		}
+		return rs256Key{key: key}, AlgRS256, nil
+	default:
+		return nil, 0, fmt.Errorf("%w: key type %d, algorithm %d", ErrUnsupportedAlgorithm, keyType, alg)
+	}
+}
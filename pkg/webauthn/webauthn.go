@@ -0,0 +1,378 @@
+// Package webauthn implements the relying party side of the WebAuthn
+// (https://www.w3.org/TR/webauthn-2/) registration and authentication
+// ceremonies used by passkeys. It supports the "none" and "packed"
+// attestation formats and ES256, EdDSA and RS256 credential keys, and
+// requires user verification. Attestation certificates are checked for a
+// valid signature but not against a trust store, so the make and model of
+// authenticators is not enforced.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const (
+	// challengeSize is the number of random bytes in a challenge
+	challengeSize = 32
+	// maxCredentialIDLength is the longest credential ID accepted
+	maxCredentialIDLength = 1023
+
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+)
+
+// Authenticator data flags
+const (
+	flagUserPresent            = 0x01
+	flagUserVerified           = 0x04
+	flagBackupEligible         = 0x08
+	flagBackupState            = 0x10
+	flagAttestedCredentialData = 0x40
+)
+
+var (
+	// ErrInvalidClientData is returned for client data of another ceremony,
+	// challenge or origin, or that is not valid JSON
+	ErrInvalidClientData = errors.New("invalid webauthn client data")
+	// ErrInvalidAuthenticatorData is returned for authenticator data of
+	// another relying party, without user verification, or that is malformed
+	ErrInvalidAuthenticatorData = errors.New("invalid webauthn authenticator data")
+	// ErrInvalidAttestation is returned for a malformed attestation object or
+	// an attestation statement that does not verify
+	ErrInvalidAttestation = errors.New("invalid webauthn attestation")
+	// ErrUnsupportedAttestation is returned for attestation formats other than "none" and "packed"
+	ErrUnsupportedAttestation = errors.New("unsupported webauthn attestation format")
+	// ErrInvalidPublicKey is returned for a malformed credential public key
+	ErrInvalidPublicKey = errors.New("invalid webauthn public key")
+	// ErrUnsupportedAlgorithm is returned for credential keys of algorithms
+	// other than SupportedAlgorithms
+	ErrUnsupportedAlgorithm = errors.New("unsupported webauthn algorithm")
+	// ErrInvalidSignature is returned for an assertion signature that does not verify
+	ErrInvalidSignature = errors.New("invalid webauthn signature")
+	// ErrSignCountMismatch is returned when the signature counter did not
+	// increase, which suggests the authenticator was cloned
+	ErrSignCountMismatch = errors.New("webauthn signature counter did not increase")
+)
+
+// encoding encodes challenges, as they appear in client data
+var encoding = base64.RawURLEncoding
+
+// Config identifies the relying party to authenticators
+type Config struct {
+	// RPID is the domain credentials are scoped to, e.g. "example.com"
+	RPID string
+	// RPName is shown by authenticators during registration
+	RPName string
+	// Origins lists the origins of the web pages allowed to run ceremonies,
+	// e.g. "https://example.com"
+	Origins []string
+}
+
+// RelyingParty verifies the responses of authenticators
+type RelyingParty struct {
+	config   Config
+	rpIDHash [32]byte
+}
+
+// New creates a relying party
+func New(config Config) (*RelyingParty, error) {
+	if config.RPID == "" {
+		return nil, errors.New("webauthn relying party id is required")
+	}
+	if len(config.Origins) == 0 {
+		return nil, errors.New("webauthn requires at least one origin")
+	}
+	if config.RPName == "" {
+		config.RPName = config.RPID
+	}
+	return &RelyingParty{
+		config:   config,
+		rpIDHash: sha256.Sum256([]byte(config.RPID)),
+	}, nil
+}
+
+// ID returns the relying party ID
+func (rp *RelyingParty) ID() string {
+	return rp.config.RPID
+}
+
+// Name returns the relying party name
+func (rp *RelyingParty) Name() string {
+	return rp.config.RPName
+}
+
+// NewChallenge returns a new random challenge, base64url encoded without padding
+func NewChallenge() (string, error) {
+	challenge := make([]byte, challengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return "", fmt.Errorf("failed to generate webauthn challenge: %w", err)
+	}
+	return encoding.EncodeToString(challenge), nil
+}
+
+// ClientData is the data the client passed to the authenticator
+type ClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// ParseClientData parses the clientDataJSON of a response, so that the
+// challenge can be looked up before the response is verified
+func ParseClientData(clientDataJSON []byte) (*ClientData, error) {
+	var clientData ClientData
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidClientData, err)
+	}
+	return &clientData, nil
+}
+
+// Credential is a newly registered credential
+type Credential struct {
+	ID []byte
+	// PublicKey is the COSE_Key of the credential, passed to VerifyAssertion
+	PublicKey []byte
+	Algorithm int
+	SignCount uint32
+	// AAGUID identifies the authenticator model, all zeros when not disclosed
+	AAGUID []byte
+	// AttestationFormat is "none" or "packed"
+	AttestationFormat string
+	// BackupEligible reports whether the credential can be synced between
+	// devices, BackedUp whether it currently is
+	BackupEligible bool
+	BackedUp       bool
+}
+
+// VerifyRegistration verifies the response of an authenticator to a
+// registration ceremony started with challenge and returns the new credential
+func (rp *RelyingParty) VerifyRegistration(clientDataJSON, attestationObject []byte, challenge string) (*Credential, error) {
+	if err := rp.verifyClientData(clientDataJSON, ceremonyCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	value, rest, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAttestation, err)
+	}
+	attestation, ok := value.(map[any]any)
+	if !ok || len(rest) != 0 {
+		return nil, ErrInvalidAttestation
+	}
+	format, _ := attestation["fmt"].(string)
+	rawAuthData, _ := attestation["authData"].([]byte)
+	statement, _ := attestation["attStmt"].(map[any]any)
+	if statement == nil {
+		return nil, ErrInvalidAttestation
+	}
+
+	authData, err := rp.parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if authData.flags&flagAttestedCredentialData == 0 {
+		return nil, fmt.Errorf("%w: no attested credential", ErrInvalidAuthenticatorData)
+	}
+	credential, key, err := parseAttestedCredential(authData.rest)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(slices.Clone(rawAuthData), clientDataHash[:]...)
+	switch format {
+	case "none":
+		if len(statement) != 0 {
+			return nil, fmt.Errorf("%w: none attestation with statement", ErrInvalidAttestation)
+		}
+	case "packed":
+		if err := verifyPackedAttestation(statement, signed, key, credential.Algorithm); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAttestation, format)
+	}
+
+	credential.SignCount = authData.signCount
+	credential.AttestationFormat = format
+	credential.BackupEligible = authData.flags&flagBackupEligible != 0
+	credential.BackedUp = authData.flags&flagBackupState != 0
+	return credential, nil
+}
+
+// Assertion is the response of an authenticator to an authentication ceremony
+type Assertion struct {
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+}
+
+// VerifyAssertion verifies the response of an authenticator to an
+// authentication ceremony started with challenge against the stored public
+// key and signature counter of the credential, and returns the new counter
+func (rp *RelyingParty) VerifyAssertion(credentialPublicKey []byte, signCount uint32, assertion Assertion, challenge string) (uint32, error) {
+	if err := rp.verifyClientData(assertion.ClientDataJSON, ceremonyGet, challenge); err != nil {
+		return 0, err
+	}
+
+	authData, err := rp.parseAuthenticatorData(assertion.AuthenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	key, _, err := parsePublicKey(credentialPublicKey)
+	if err != nil {
+		return 0, err
+	}
+	clientDataHash := sha256.Sum256(assertion.ClientDataJSON)
+	signed := append(slices.Clone(assertion.AuthenticatorData), clientDataHash[:]...)
+	if !key.verify(signed, assertion.Signature) {
+		return 0, ErrInvalidSignature
+	}
+
+	// Authenticators without a counter always report zero
+	if (authData.signCount != 0 || signCount != 0) && authData.signCount <= signCount {
+		return 0, ErrSignCountMismatch
+	}
+	return authData.signCount, nil
+}
+
+// verifyClientData checks the ceremony, challenge and origin of client data
+func (rp *RelyingParty) verifyClientData(clientDataJSON []byte, ceremony, challenge string) error {
+	clientData, err := ParseClientData(clientDataJSON)
+	if err != nil {
+		return err
+	}
+	if clientData.Type != ceremony {
+		return fmt.Errorf("%w: type %q", ErrInvalidClientData, clientData.Type)
+	}
+	if challenge == "" || clientData.Challenge != challenge {
+		return fmt.Errorf("%w: challenge mismatch", ErrInvalidClientData)
+	}
+	if !slices.Contains(rp.config.Origins, clientData.Origin) || clientData.CrossOrigin {
+		return fmt.Errorf("%w: origin %q", ErrInvalidClientData, clientData.Origin)
+	}
+	return nil
+}
+
+// authenticatorData is the parsed fixed part of authenticator data
+type authenticatorData struct {
+	flags     byte
+	signCount uint32
+	// rest holds the attested credential data and extensions
+	rest []byte
+}
+
+// parseAuthenticatorData parses authenticator data and checks that it was
+// made for this relying party with the user present and verified
+func (rp *RelyingParty) parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidAuthenticatorData)
+	}
+	if !bytes.Equal(data[:32], rp.rpIDHash[:]) {
+		return nil, fmt.Errorf("%w: relying party mismatch", ErrInvalidAuthenticatorData)
+	}
+
+	authData := &authenticatorData{
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+		rest:      data[37:],
+	}
+	if authData.flags&flagUserPresent == 0 {
+		return nil, fmt.Errorf("%w: user not present", ErrInvalidAuthenticatorData)
+	}
+	if authData.flags&flagUserVerified == 0 {
+		return nil, fmt.Errorf("%w: user not verified", ErrInvalidAuthenticatorData)
+	}
+	return authData, nil
+}
+
+// parseAttestedCredential parses the attested credential data following the
+// fixed part of authenticator data
+func parseAttestedCredential(data []byte) (*Credential, publicKey, error) {
+	if len(data) < 18 {
+		return nil, nil, fmt.Errorf("%w: attested credential too short", ErrInvalidAuthenticatorData)
+	}
+	aaguid := data[:16]
+	idLength := int(binary.BigEndian.Uint16(data[16:18]))
+	data = data[18:]
+	if idLength == 0 || idLength > maxCredentialIDLength || len(data) < idLength {
+		return nil, nil, fmt.Errorf("%w: invalid credential id", ErrInvalidAuthenticatorData)
+	}
+	id := data[:idLength]
+	data = data[idLength:]
+
+	// The public key is followed by extensions, if any
+	_, rest, err := decodeCBOR(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+	}
+	coseKey := data[:len(data)-len(rest)]
+	key, alg, err := parsePublicKey(coseKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &Credential{
+		ID:        slices.Clone(id),
+		PublicKey: slices.Clone(coseKey),
+		Algorithm: alg,
+		AAGUID:    slices.Clone(aaguid),
+	}, key, nil
+}
+
+// verifyPackedAttestation verifies a "packed" attestation statement, made
+// either with an attestation certificate or with the credential key itself
+func verifyPackedAttestation(statement map[any]any, signed []byte, credentialKey publicKey, credentialAlg int) error {
+	alg, _ := statement["alg"].(int64)
+	signature, _ := statement["sig"].([]byte)
+	if len(signature) == 0 {
+		return fmt.Errorf("%w: packed attestation without signature", ErrInvalidAttestation)
+	}
+
+	chain, hasChain := statement["x5c"].([]any)
+	if !hasChain {
+		// Self attestation
+		if int(alg) != credentialAlg {
+			return fmt.Errorf("%w: algorithm mismatch", ErrInvalidAttestation)
+		}
+		if !credentialKey.verify(signed, signature) {
+			return fmt.Errorf("%w: %w", ErrInvalidAttestation, ErrInvalidSignature)
+		}
+		return nil
+	}
+
+	if len(chain) == 0 {
+		return fmt.Errorf("%w: empty certificate chain", ErrInvalidAttestation)
+	}
+	der, _ := chain[0].([]byte)
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAttestation, err)
+	}
+	var signatureAlgorithm x509.SignatureAlgorithm
+	switch alg {
+	case AlgES256:
+		signatureAlgorithm = x509.ECDSAWithSHA256
+	case AlgEdDSA:
+		signatureAlgorithm = x509.PureEd25519
+	case AlgRS256:
+		signatureAlgorithm = x509.SHA256WithRSA
+	default:
+		return fmt.Errorf("%w: algorithm %d", ErrUnsupportedAlgorithm, alg)
+	}
+	if err := certificate.CheckSignature(signatureAlgorithm, signed, signature); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAttestation, ErrInvalidSignature)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package webauthn_test
+
+import (
+	"testing"
+
+	"github.com/memclutter/go-microservices-template/pkg/webauthn"
+	"github.com/memclutter/go-microservices-template/pkg/webauthn/webauthntest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rpID   = "example.com"
+	origin = "https://example.com"
+)
+
+func newRelyingParty(t *testing.T) *webauthn.RelyingParty {
+	rp, err := webauthn.New(webauthn.Config{RPID: rpID, RPName: "Example", Origins: []string{origin}})
+	require.NoError(t, err)
+	return rp
+}
+
+// register runs a registration ceremony with the authenticator
+func register(t *testing.T, rp *webauthn.RelyingParty, authenticator *webauthntest.Authenticator) *webauthn.Credential {
+	challenge, err := webauthn.NewChallenge()
+	require.NoError(t, err)
+	clientDataJSON, attestationObject, err := authenticator.Register(challenge)
+	require.NoError(t, err)
+	credential, err := rp.VerifyRegistration(clientDataJSON, attestationObject, challenge)
+	require.NoError(t, err)
+	return credential
+}
+
+// login responds to a new authentication challenge with the authenticator
+func login(t *testing.T, authenticator *webauthntest.Authenticator) (webauthn.Assertion, string) {
+	challenge, err := webauthn.NewChallenge()
+	require.NoError(t, err)
+	clientDataJSON, authenticatorData, signature, err := authenticator.Login(challenge)
+	require.NoError(t, err)
+	return webauthn.Assertion{ClientDataJSON: clientDataJSON, AuthenticatorData: authenticatorData, Signature: signature}, challenge
+}
+
+func TestCeremonies(t *testing.T) {
+	for _, format := range []string{"none", "packed"} {
+		t.Run(format+" attestation", func(t *testing.T) {
+			rp := newRelyingParty(t)
+			authenticator, err := webauthntest.New(rpID, origin)
+			require.NoError(t, err)
+			authenticator.AttestationFormat = format
+
+			credential := register(t, rp, authenticator)
+			assert.Equal(t, authenticator.CredentialID, credential.ID)
+			assert.Equal(t, authenticator.PublicKey(), credential.PublicKey)
+			assert.Equal(t, webauthn.AlgES256, credential.Algorithm)
+			assert.Equal(t, format, credential.AttestationFormat)
+
+			assertion, challenge := login(t, authenticator)
+			signCount, err := rp.VerifyAssertion(credential.PublicKey, credential.SignCount, assertion, challenge)
+			require.NoError(t, err)
+			assert.Equal(t, uint32(1), signCount)
+		})
+	}
+}
+
+func TestVerifyRegistration_Rejects(t *testing.T) {
+	rp := newRelyingParty(t)
+	challenge, err := webauthn.NewChallenge()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		setup   func(*webauthntest.Authenticator)
+		wantErr error
+	}{
+		{name: "other origin", setup: func(a *webauthntest.Authenticator) { a.Origin = "https://evil.example" }, wantErr: webauthn.ErrInvalidClientData},
+		{name: "other relying party", setup: func(a *webauthntest.Authenticator) { a.RPID = "evil.example" }, wantErr: webauthn.ErrInvalidAuthenticatorData},
+		{name: "user not verified", setup: func(a *webauthntest.Authenticator) { a.SkipUserVerification = true }, wantErr: webauthn.ErrInvalidAuthenticatorData},
+		{name: "unsupported attestation", setup: func(a *webauthntest.Authenticator) { a.AttestationFormat = "tpm" }, wantErr: webauthn.ErrUnsupportedAttestation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator, err := webauthntest.New(rpID, origin)
+			require.NoError(t, err)
+			tt.setup(authenticator)
+
+			clientDataJSON, attestationObject, err := authenticator.Register(challenge)
+			require.NoError(t, err)
+			_, err = rp.VerifyRegistration(clientDataJSON, attestationObject, challenge)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("other challenge", func(t *testing.T) {
+		authenticator, err := webauthntest.New(rpID, origin)
+		require.NoError(t, err)
+		clientDataJSON, attestationObject, err := authenticator.Register(challenge)
+		require.NoError(t, err)
+		_, err = rp.VerifyRegistration(clientDataJSON, attestationObject, "other")
+		assert.ErrorIs(t, err, webauthn.ErrInvalidClientData)
+	})
+
+	t.Run("assertion instead of attestation", func(t *testing.T) {
+		authenticator, err := webauthntest.New(rpID, origin)
+		require.NoError(t, err)
+		assertion, challenge := login(t, authenticator)
+		_, err = rp.VerifyRegistration(assertion.ClientDataJSON, assertion.AuthenticatorData, challenge)
+		assert.ErrorIs(t, err, webauthn.ErrInvalidClientData)
+	})
+
+	t.Run("malformed attestation object", func(t *testing.T) {
+		authenticator, err := webauthntest.New(rpID, origin)
+		require.NoError(t, err)
+		clientDataJSON, attestationObject, err := authenticator.Register(challenge)
+		require.NoError(t, err)
+		_, err = rp.VerifyRegistration(clientDataJSON, attestationObject[:len(attestationObject)-10], challenge)
+		assert.ErrorIs(t, err, webauthn.ErrInvalidAttestation)
+	})
+}
+
+func TestVerifyAssertion_Rejects(t *testing.T) {
+	rp := newRelyingParty(t)
+	authenticator, err := webauthntest.New(rpID, origin)
+	require.NoError(t, err)
+	credential := register(t, rp, authenticator)
+
+	t.Run("tampered signature", func(t *testing.T) {
+		assertion, challenge := login(t, authenticator)
+		assertion.Signature[len(assertion.Signature)-1] ^= 0xff
+		_, err := rp.VerifyAssertion(credential.PublicKey, 0, assertion, challenge)
+		assert.ErrorIs(t, err, webauthn.ErrInvalidSignature)
+	})
+
+	t.Run("key of another credential", func(t *testing.T) {
+		other, err := webauthntest.New(rpID, origin)
+		require.NoError(t, err)
+		assertion, challenge := login(t, authenticator)
+		_, err = rp.VerifyAssertion(other.PublicKey(), 0, assertion, challenge)
+		assert.ErrorIs(t, err, webauthn.ErrInvalidSignature)
+	})
+
+	t.Run("signature counter did not increase", func(t *testing.T) {
+		assertion, challenge := login(t, authenticator)
+		_, err := rp.VerifyAssertion(credential.PublicKey, authenticator.SignCount, assertion, challenge)
+		assert.ErrorIs(t, err, webauthn.ErrSignCountMismatch)
+	})
+
+	t.Run("replayed to another challenge", func(t *testing.T) {
+		assertion, _ := login(t, authenticator)
+		other, err := webauthn.NewChallenge()
+		require.NoError(t, err)
+		_, err = rp.VerifyAssertion(credential.PublicKey, 0, assertion, other)
+		assert.ErrorIs(t, err, webauthn.ErrInvalidClientData)
+	})
+
+	t.Run("user not verified", func(t *testing.T) {
+		authenticator.SkipUserVerification = true
+		defer func() { authenticator.SkipUserVerification = false }()
+		assertion, challenge := login(t, authenticator)
+		_, err := rp.VerifyAssertion(credential.PublicKey, 0, assertion, challenge)
+		assert.ErrorIs(t, err, webauthn.ErrInvalidAuthenticatorData)
+	})
+}
@@ -0,0 +1,141 @@
+// Package webauthntest provides a software authenticator, so that WebAuthn
+// ceremonies can be tested offline without a browser or security key
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/memclutter/go-microservices-template/pkg/webauthn"
+)
+
+// Authenticator holds a single ES256 discoverable credential
+type Authenticator struct {
+	RPID   string
+	Origin string
+	// CredentialID identifies the credential to the relying party
+	CredentialID []byte
+	// SignCount is the signature counter, incremented by every Login
+	SignCount uint32
+	// AttestationFormat is "none" or "packed", for self attestation
+	AttestationFormat string
+	// SkipUserVerification clears the user verified flag of responses
+	SkipUserVerification bool
+
+	key *ecdsa.PrivateKey
+}
+
+// New creates an authenticator for the relying party and origin with a new key pair
+func New(rpID, origin string) (*Authenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		return nil, fmt.Errorf("failed to generate credential id: %w", err)
+	}
+	return &Authenticator{
+		RPID:              rpID,
+		Origin:            origin,
+		CredentialID:      credentialID,
+		AttestationFormat: "none",
+		key:               key,
+	}, nil
+}
+
+// Register responds to a registration ceremony started with challenge
+func (a *Authenticator) Register(challenge string) (clientDataJSON, attestationObject []byte, err error) {
+	clientDataJSON, err = a.clientData("webauthn.create", challenge)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	authData := a.authenticatorData(0x40)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.CredentialID)))
+	authData = append(authData, a.CredentialID...)
+	authData = append(authData, a.PublicKey()...)
+
+	statement := cborMap{}
+	if a.AttestationFormat == "packed" {
+		signature, err := a.sign(authData, clientDataJSON)
+		if err != nil {
+			return nil, nil, err
+		}
+		statement = cborMap{{"alg", int64(webauthn.AlgES256)}, {"sig", signature}}
+	}
+
+	attestationObject = encodeCBOR(cborMap{
+		{"fmt", a.AttestationFormat},
+		{"attStmt", statement},
+		{"authData", authData},
+	})
+	return clientDataJSON, attestationObject, nil
+}
+
+// Login responds to an authentication ceremony started with challenge
+func (a *Authenticator) Login(challenge string) (clientDataJSON, authenticatorData, signature []byte, err error) {
+	clientDataJSON, err = a.clientData("webauthn.get", challenge)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	a.SignCount++
+	authenticatorData = a.authenticatorData(0)
+	signature, err = a.sign(authenticatorData, clientDataJSON)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return clientDataJSON, authenticatorData, signature, nil
+}
+
+// PublicKey returns the COSE_Key of the credential
+func (a *Authenticator) PublicKey() []byte {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+	return encodeCBOR(cborMap{
+		{int64(1), int64(2)},                 // kty: EC2
+		{int64(3), int64(webauthn.AlgES256)}, // alg
+		{int64(-1), int64(1)},                // crv: P-256
+		{int64(-2), x},
+		{int64(-3), y},
+	})
+}
+
+func (a *Authenticator) clientData(ceremony, challenge string) ([]byte, error) {
+	return json.Marshal(webauthn.ClientData{
+		Type:      ceremony,
+		Challenge: challenge,
+		Origin:    a.Origin,
+	})
+}
+
+// authenticatorData returns the fixed part of authenticator data with the
+// user present flag, the user verified flag unless skipped, and extraFlags
+func (a *Authenticator) authenticatorData(extraFlags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.RPID))
+	flags := byte(0x01) | extraFlags
+	if !a.SkipUserVerification {
+		flags |= 0x04
+	}
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, a.SignCount)
+}
+
+func (a *Authenticator) sign(authData, clientDataJSON []byte) ([]byte, error) {
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	return signature, nil
+}
@@ -0,0 +1,48 @@
+package webauthntest
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// cborMap is a CBOR map whose entries are encoded in order
+type cborMap [][2]any
+
+// encodeCBOR encodes int64, []byte, string and cborMap values
+func encodeCBOR(value any) []byte {
+	switch v := value.(type) {
+	case int64:
+		if v < 0 {
+			return cborHeader(1, uint64(-1-v))
+		}
+		return cborHeader(0, uint64(v))
+	case []byte:
+		return append(cborHeader(2, uint64(len(v))), v...)
+	case string:
+		return append(cborHeader(3, uint64(len(v))), v...)
+	case cborMap:
+		data := cborHeader(5, uint64(len(v)))
+		for _, entry := range v {
+			data = append(data, encodeCBOR(entry[0])...)
+			data = append(data, encodeCBOR(entry[1])...)
+		}
+		return data
+	default:
+		panic(fmt.Sprintf("webauthntest: cannot encode %T", value))
+	}
+}
+
+func cborHeader(major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return []byte{major<<5 | byte(arg)}
+	case arg <= 0xff:
+		return []byte{major<<5 | 24, byte(arg)}
+	case arg <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(arg))
+	case arg <= 0xffffffff:
+		return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64([]byte{major<<5 | 27}, arg)
+	}
+}